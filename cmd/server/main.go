@@ -2,109 +2,642 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/api"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/config"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/graphql"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/handler"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/health"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/outbox"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/ratelimit"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/rpc"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/rpc/transactionpb"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/webhook"
 	"github.com/dgraph-io/badger/v3"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 	"net/http"
 )
 
+// logOutput is where the application logs are written
+var logOutput io.Writer = os.Stdout
+
 func main() {
-	// Setup structured logger
-	jsonLogger := logger.NewJSONLogger(os.Stdout, logger.InfoLevel)
-	jsonLogger.Info("Starting WEX TAG Transaction Processing System", map[string]interface{}{
+	// Load configuration: defaults < config file < environment < flags
+	cfg, err := config.Load(os.Args[0], os.Args[1:], os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\nSettings:\n", err)
+		config.Usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	// Stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err = run(ctx, cfg)
+	stop()
+
+	// run has already logged the error
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// run starts the server and blocks until ctx is cancelled or the server fails.
+// Startup failures are returned rather than exiting, so every resource opened
+// before the failure is released by its deferred cleanup.
+func run(ctx context.Context, cfg *config.Config) (err error) {
+	// Setup metrics
+	promMetrics := metrics.NewPrometheusMetrics("wex")
+	metrics.SetDefaultMetrics(promMetrics)
+
+	// Setup structured logger; code using log/slog or the standard log package is
+	// routed into the same stream
+	logLevels := logger.NewLevelController(logger.InfoLevel)
+	applyLogLevels(logLevels, cfg.Log)
+	appLogger, closeLogger := newLogger(cfg.Log, logLevels, promMetrics)
+	defer func() {
+		// Deferred first so it runs last, after everything else has logged
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := closeLogger(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to flush logs: %v\n", err)
+		}
+	}()
+	defer func() {
+		if err != nil {
+			appLogger.Error("Server exited with error", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}()
+	logger.SetDefaultLogger(appLogger)
+	slog.SetDefault(slog.New(logger.NewSlogHandler(appLogger)))
+	appLogger.Info("Starting WEX TAG Transaction Processing System", map[string]interface{}{
 		"version":   "1.0.0",
 		"timestamp": "2025-03-29T12:00:00Z",
 	})
+	appLogger.Info("Effective configuration", map[string]interface{}{
+		"config": cfg.Redacted(),
+	})
+
+	// Setup tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		File:        cfg.Tracing.File,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("configure tracing: %w", err)
+	}
+	defer func() {
+		// Flush buffered spans before exiting
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Error("Error flushing spans", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}()
 
 	// Setup BadgerDB
-	dbPath := filepath.Join(".", "data")
+	dbPath := cfg.Database.Path
 	if err := os.MkdirAll(dbPath, 0755); err != nil {
-		jsonLogger.Fatal("Failed to create database directory", map[string]interface{}{
-			"error": err.Error(),
-			"path":  dbPath,
-		})
+		return fmt.Errorf("create database directory %s: %w", dbPath, err)
 	}
 
 	badgerOpts := badger.DefaultOptions(dbPath)
-	badgerOpts.Logger = nil // Disable Badger's default logger
+	badgerOpts.Logger = db.NewBadgerLogger(appLogger)
+	badgerOpts.SyncWrites = cfg.Database.SyncWrites
 
-	jsonLogger.Info("Opening database", map[string]interface{}{
+	appLogger.Info("Opening database", map[string]interface{}{
 		"path": dbPath,
 	})
 
 	badgerDB, err := badger.Open(badgerOpts)
 	if err != nil {
-		jsonLogger.Fatal("Failed to open database", map[string]interface{}{
-			"error": err.Error(),
-			"path":  dbPath,
-		})
+		return fmt.Errorf("open database %s: %w", dbPath, err)
 	}
 
 	defer func() {
 		if err := badgerDB.Close(); err != nil {
-			jsonLogger.Error("Error closing BadgerDB", map[string]interface{}{
+			appLogger.Error("Error closing BadgerDB", map[string]interface{}{
 				"error": err.Error(),
 			})
+			return
 		}
+		appLogger.Info("Database closed", nil)
 	}()
 
+	// Reports read transactions through the date index, which older databases lack
+	indexed, err := db.BuildDateIndex(badgerDB)
+	if err != nil {
+		return err
+	}
+	if indexed > 0 {
+		appLogger.Info("Date index built", map[string]interface{}{
+			"transactions": indexed,
+		})
+	}
+
 	// Initialize repositories and services
-	txRepo := db.NewBadgerTransactionRepository(badgerDB, jsonLogger)
-	treasuryClient := api.NewTreasuryAPIClient(jsonLogger)
-	exchangeRateRepo := db.NewTreasuryExchangeRateRepository(treasuryClient, jsonLogger)
+	// Each component logs with its own name so its level can be overridden
+	componentLogger := func(component string) logger.Logger {
+		return appLogger.WithField(logger.ComponentField, component)
+	}
+	httpLogger := componentLogger("http")
+
+	retention := db.RetentionConfig{
+		ReportExpired: cfg.Retention.ExpiredStatus == "gone",
+		GonePeriod:    cfg.Retention.GonePeriod(),
+		PublishEvents: cfg.PublishEvents(),
+	}
+	txRepo := db.NewBadgerTransactionRepositoryWithRetention(badgerDB, retention, componentLogger("db"), promMetrics)
+	purger := db.NewRetentionPurger(badgerDB, retention, componentLogger("db"), promMetrics)
+	auditRepo := db.NewBadgerAuditRepository(badgerDB, componentLogger("db"), promMetrics)
+	treasuryClient := api.NewTreasuryAPIClientWithConfig(api.ClientConfig{
+		BaseURL:        cfg.Treasury.BaseURL,
+		Timeout:        cfg.Treasury.Timeout,
+		MaxRetries:     cfg.Treasury.MaxRetries,
+		LookbackMonths: cfg.Treasury.LookbackMonths,
+		CacheTTL:       cfg.Treasury.CacheTTL,
+	}, componentLogger("api"), promMetrics)
+	// Rates are kept in Badger once fetched, so stored transactions convert offline
+	exchangeRateRepo := db.NewBadgerExchangeRateRepository(badgerDB,
+		db.NewTreasuryExchangeRateRepository(treasuryClient, componentLogger("db")), componentLogger("db"))
+
+	// Events recorded with each transaction change are relayed to the configured
+	// sinks and, through the webhook dispatcher, to tenants' subscriptions
+	webhookRepo := db.NewBadgerWebhookRepositoryWithRetention(badgerDB, cfg.Webhooks.HistoryRetention, componentLogger("db"), promMetrics)
+	var relay *outbox.Relay
+	var webhookWorker *webhook.Worker
+	if cfg.PublishEvents() {
+		sinks, closeSinks, err := newOutboxSinks(cfg.Outbox)
+		if err != nil {
+			return fmt.Errorf("configure outbox: %w", err)
+		}
+		defer closeSinks()
+		if cfg.Webhooks.Enabled {
+			sinks = append(sinks, webhook.NewDispatcher(webhookRepo))
+			webhookWorker = webhook.NewWorker(webhookRepo, webhook.WorkerConfig{
+				Timeout:              cfg.Webhooks.Timeout,
+				PollInterval:         cfg.Webhooks.PollInterval,
+				BatchSize:            cfg.Webhooks.BatchSize,
+				RetryInitial:         cfg.Webhooks.RetryInitial,
+				RetryMax:             cfg.Webhooks.RetryMax,
+				MaxAttempts:          cfg.Webhooks.MaxAttempts,
+				AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+			}, componentLogger("webhook"), promMetrics)
+		}
+		relay = outbox.NewRelay(db.NewBadgerOutboxRepository(badgerDB, componentLogger("db"), promMetrics), sinks,
+			outbox.RelayConfig{
+				PollInterval: cfg.Outbox.PollInterval,
+				BatchSize:    cfg.Outbox.BatchSize,
+				RetryInitial: cfg.Outbox.RetryInitial,
+				RetryMax:     cfg.Outbox.RetryMax,
+			}, componentLogger("outbox"), promMetrics)
+		appLogger.Info("Outbox relay enabled", map[string]interface{}{
+			"sinks": outbox.SinkNames(sinks),
+		})
+	}
 
 	// Initialize services
-	txService := service.NewTransactionService(txRepo, jsonLogger)
-	conversionService := service.NewConversionService(txRepo, exchangeRateRepo, jsonLogger)
+	serviceLogger := componentLogger("service")
+	auditService := service.NewAuditService(auditRepo, serviceLogger)
+	conversionService := service.NewConversionServiceWithAudit(txRepo, exchangeRateRepo, auditService, serviceLogger)
+	reportService := service.NewReportService(txRepo, conversionService, service.ReportConfig{
+		CacheTTL:  cfg.Reports.CacheTTL,
+		CacheSize: cfg.Reports.CacheSize,
+	}, serviceLogger)
+	txService := service.NewTransactionServiceWithConfig(txRepo, service.TransactionServiceConfig{
+		Retention: func(ctx context.Context) time.Duration {
+			return tenant.FromContext(ctx).Retention(cfg.Retention.Period())
+		},
+		Audit:         auditService,
+		PublishEvents: cfg.PublishEvents(),
+		Reports:       reportService,
+	}, serviceLogger)
+	webhookService := service.NewWebhookService(webhookRepo, serviceLogger)
+	exportService := service.NewExportService(txRepo, conversionService, serviceLogger)
+	streamService := service.NewTransactionStreamService(db.NewBadgerTransactionStreamRepository(badgerDB, componentLogger("db"), promMetrics), serviceLogger)
 
 	// Initialize handlers
-	txHandler := handler.NewTransactionHandler(txService, jsonLogger)
-	conversionHandler := handler.NewConversionHandler(conversionService, jsonLogger)
+	handlerLogger := componentLogger("handler")
+	txHandler := handler.NewTransactionHandler(txService, handlerLogger)
+	conversionHandler := handler.NewConversionHandler(conversionService, handlerLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logLevels, handlerLogger)
+	legalHoldHandler := handler.NewLegalHoldHandler(txService, handlerLogger)
+	auditHandler := handler.NewAuditHandler(auditService, handlerLogger)
+	webhookHandler := handler.NewWebhookHandler(webhookService, handlerLogger)
+	streamHandler := handler.NewStreamHandler(streamService, cfg.Stream.HeartbeatInterval, handlerLogger)
+	exportHandler := handler.NewExportHandler(exportService, handlerLogger)
+	reportHandler := handler.NewReportHandler(reportService, handlerLogger)
+
+	// Readiness checks: the database and disk are critical, the Treasury API only
+	// affects conversions so its failures degrade rather than fail readiness
+	healthRegistry := health.NewRegistry(cfg.Health.CheckTimeout)
+	healthRegistry.Register("badger", true, health.BadgerCheck(badgerDB))
+	healthRegistry.Register("disk", true, health.DiskCheck(dbPath, uint64(cfg.Health.MinFreeBytes)))
+	healthRegistry.Register("treasury", false, health.Cached(health.CheckerFunc(treasuryClient.Ping), cfg.Health.TreasuryInterval))
+	healthRegistry.Register("exchange_rates", false, health.FreshnessCheck(treasuryClient.LastSuccess, treasuryClient.LastFailure, cfg.Health.RateMaxAge))
+	healthHandler := handler.NewHealthHandler(healthRegistry, handlerLogger)
 
 	// Setup router
 	router := mux.NewRouter()
 
 	// Add middleware
 	router.Use(middleware.RequestIDMiddleware)
-	router.Use(middleware.LoggingMiddleware(jsonLogger))
+	router.Use(middleware.TracingMiddleware)
+	router.Use(middleware.MetricsMiddleware(promMetrics))
+	router.Use(middleware.LoggingMiddleware(httpLogger))
+	router.Use(middleware.MaxBodyBytesMiddleware(cfg.Server.MaxBodyBytes))
 
-	// Register routes
-	txHandler.RegisterRoutes(router)
-	conversionHandler.RegisterRoutes(router)
+	// Add authentication unless it is explicitly disabled. The gRPC API shares
+	// the authenticator and policy.
+	var authenticator *auth.Authenticator
+	var policy *auth.Policy
+	if cfg.Auth.Enabled() {
+		authenticator, policy, err = newAuth(cfg, httpLogger)
+		if err != nil {
+			return fmt.Errorf("configure authentication: %w", err)
+		}
+		router.Use(middleware.AuthMiddleware(authenticator, policy, httpLogger))
+	} else {
+		appLogger.Warn("Authentication disabled: every route is served without a token", nil)
+	}
 
-	// Add health check endpoint
-	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	}).Methods("GET")
+	// Add health check endpoints
+	healthHandler.RegisterRoutes(router)
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Add admin endpoints
+	logLevelHandler.RegisterRoutes(router)
+
+	// Add Prometheus metrics endpoint
+	if cfg.Metrics.Enabled {
+		router.Handle(cfg.Metrics.Path, promMetrics.Handler()).Methods("GET")
 	}
 
-	serverAddr := ":" + port
-	jsonLogger.Info("Server listening", map[string]interface{}{
-		"address": serverAddr,
+	// Tenant-scoped API routes
+	tenantRegistry, err := cfg.Tenancy.Registry()
+	if err != nil {
+		return fmt.Errorf("load tenant configuration: %w", err)
+	}
+	appLogger.Info("Tenants configured", map[string]interface{}{
+		"tenants":      tenantRegistry.IDs(),
+		"multi_tenant": tenantRegistry.MultiTenant(),
 	})
 
+	apiRouter := router.NewRoute().Subrouter()
+	if cfg.RateLimit.Enabled {
+		apiRouter.Use(middleware.RateLimitMiddleware(ratelimit.NewMemoryStore(), newRateLimitConfig(cfg.RateLimit), httpLogger))
+	}
+	apiRouter.Use(middleware.TenantMiddleware(tenantRegistry, cfg.Tenancy.Claim, httpLogger))
+
+	// Register routes; the stream and export come first so /transactions/{id} does
+	// not match them
+	streamHandler.RegisterRoutes(apiRouter)
+	exportHandler.RegisterRoutes(apiRouter)
+	txHandler.RegisterRoutes(apiRouter)
+	conversionHandler.RegisterRoutes(apiRouter)
+	legalHoldHandler.RegisterRoutes(apiRouter)
+	auditHandler.RegisterRoutes(apiRouter)
+	reportHandler.RegisterRoutes(apiRouter)
+	if cfg.Webhooks.Enabled {
+		webhookHandler.RegisterRoutes(apiRouter)
+	}
+	if cfg.GraphQL.Enabled {
+		graphql.NewHandler(txService, conversionService, auditService, graphql.Config{
+			Policy:   policy,
+			MaxDepth: cfg.GraphQL.MaxDepth,
+		}, componentLogger("graphql")).RegisterRoutes(apiRouter)
+	}
+
+	// Start server
+	listener, err := net.Listen("tcp", cfg.Server.Addr())
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", cfg.Server.Addr(), err)
+	}
+
+	// The gRPC API serves the same services on its own port
+	var grpcServer *grpc.Server
+	var grpcListener net.Listener
+	if cfg.GRPC.Enabled {
+		grpcListener, err = net.Listen("tcp", cfg.GRPCAddr())
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("listen on %s: %w", cfg.GRPCAddr(), err)
+		}
+		grpcServer = rpc.NewServer(txService, conversionService, rpc.Config{
+			Authenticator:  authenticator,
+			Policy:         policy,
+			Tenants:        tenantRegistry,
+			TenantClaim:    cfg.Tenancy.Claim,
+			MaxRecvMsgSize: int(cfg.Server.MaxBodyBytes),
+		}, componentLogger("grpc"))
+	}
 	server := &http.Server{
-		Addr:    serverAddr,
-		Handler: router,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
 	}
+	// Open streams never go idle, so end them for Shutdown to complete
+	server.RegisterOnShutdown(streamHandler.Close)
 
-	if err := server.ListenAndServe(); err != nil {
-		jsonLogger.Fatal("Server failed", map[string]interface{}{
+	// Start background workers; they stop when the context is cancelled
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		treasuryClient.Cache().RunJanitor(ctx, time.Hour)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		reloadLogLevelsOnHangup(ctx, logLevels, appLogger)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		purger.Run(ctx, cfg.Retention.PurgeInterval)
+	}()
+	if relay != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(ctx)
+		}()
+	}
+	if webhookWorker != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			webhookWorker.Run(ctx)
+		}()
+	}
+
+	serverErr := make(chan error, 2)
+	go func() {
+		appLogger.Info("Server listening", map[string]interface{}{
+			"address": listener.Addr().String(),
+		})
+		serverErr <- server.Serve(listener)
+	}()
+	if grpcServer != nil {
+		go func() {
+			appLogger.Info("gRPC server listening", map[string]interface{}{
+				"address": grpcListener.Addr().String(),
+			})
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				serverErr <- fmt.Errorf("grpc: %w", err)
+			}
+		}()
+	}
+
+	var serveErr error
+	select {
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr = fmt.Errorf("serve: %w", err)
+		}
+	case <-ctx.Done():
+		appLogger.Info("Shutdown signal received, draining in-flight requests", map[string]interface{}{
+			"timeout": cfg.Server.ShutdownTimeout.String(),
+		})
+	}
+
+	// Stop accepting new requests and wait for in-flight ones to complete
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error("Graceful shutdown failed", map[string]interface{}{
 			"error": err.Error(),
 		})
 	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			appLogger.Error("Graceful gRPC shutdown timed out", nil)
+			grpcServer.Stop()
+		}
+	}
+
+	// Stop background workers before the database is closed by the deferred Close
+	stop()
+	workers.Wait()
+
+	appLogger.Info("Server stopped", nil)
+	return serveErr
+}
+
+// newOutboxSinks creates the event sinks that are configured. The returned
+// function closes them.
+func newOutboxSinks(cfg config.OutboxConfig) ([]outbox.Sink, func(), error) {
+	var sinks []outbox.Sink
+	closeSinks := func() {}
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, outbox.NewWebhookSink(cfg.WebhookURL, cfg.WebhookTimeout))
+	}
+	if cfg.File != "" {
+		fileSink, err := outbox.NewFileSink(cfg.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, fileSink)
+		closeSinks = func() { fileSink.Close() }
+	}
+	return sinks, closeSinks, nil
+}
+
+// applyLogLevels sets the default level and component overrides from the
+// configuration, which has already been validated
+func applyLogLevels(levels *logger.LevelController, cfg config.LogConfig) {
+	level, _ := logger.ParseLevel(cfg.Level)
+	components, _ := logger.ParseComponentLevels(cfg.Components)
+	levels.Set(level, components)
+}
+
+// newLogger creates the application logger for the configured backend and format,
+// and returns a function that flushes buffered output. The backend writes every
+// level; filtering is done by levels so that it can be changed at runtime. Entries
+// pass through level filtering, then sampling, then redaction.
+func newLogger(cfg config.LogConfig, levels *logger.LevelController, m metrics.Metrics) (logger.Logger, func(context.Context) error) {
+	output := logOutput
+	closeOutput := func(context.Context) error { return nil }
+	if cfg.Async {
+		asyncWriter := logger.NewAsyncWriter(logOutput, logger.AsyncWriterConfig{
+			BufferSize: cfg.BufferSize,
+			Overflow:   logger.OverflowPolicy(cfg.Overflow),
+			Metrics:    m,
+		})
+		output = asyncWriter
+		closeOutput = asyncWriter.Close
+	}
+
+	var base logger.Logger
+	switch {
+	case cfg.Backend == "slog" && cfg.Format == "text":
+		base = logger.NewSlogTextLogger(output, logger.DebugLevel)
+	case cfg.Backend == "slog":
+		base = logger.NewSlogJSONLogger(output, logger.DebugLevel)
+	default:
+		base = logger.NewJSONLogger(output, logger.DebugLevel)
+	}
+
+	// The rules were validated when the configuration was loaded
+	rules, _ := logger.ParseRedactionRules(cfg.Redact)
+	base = logger.NewRedactingLogger(base, logger.NewRedactor(rules, cfg.RedactKey))
+
+	if cfg.SampleInitial > 0 {
+		base = logger.NewSamplingLogger(base, logger.SamplingConfig{
+			Initial:    cfg.SampleInitial,
+			Thereafter: cfg.SampleThereafter,
+			Interval:   cfg.SampleInterval,
+			Metrics:    m,
+		})
+	}
+
+	return logger.NewLeveledLogger(base, levels), closeOutput
+}
+
+// reloadLogLevelsOnHangup re-reads the configuration on SIGHUP and applies its log
+// level and component overrides. Other settings still require a restart.
+func reloadLogLevelsOnHangup(ctx context.Context, levels *logger.LevelController, log logger.Logger) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		}
+
+		cfg, err := config.Load(os.Args[0], os.Args[1:], os.Getenv)
+		if err != nil {
+			log.Error("Failed to reload configuration, keeping current log levels", map[string]interface{}{
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		applyLogLevels(levels, cfg.Log)
+		log.Warn("Log levels reloaded", map[string]interface{}{
+			"log_level":  levels.Level(),
+			"components": logger.FormatComponentLevels(levels.ComponentLevels()),
+		})
+	}
+}
+
+// newAuth builds the JWT authenticator and the policy of the HTTP routes and gRPC
+// methods
+func newAuth(appCfg *config.Config, log logger.Logger) (*auth.Authenticator, *auth.Policy, error) {
+	cfg := appCfg.Auth
+
+	var keys auth.KeySource
+	if cfg.JWKSFile != "" {
+		keySet, err := auth.LoadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = keySet
+	} else {
+		keys = auth.NewRemoteKeySet(cfg.JWKSURL, log)
+	}
+
+	authenticator := auth.NewAuthenticator(keys, auth.Config{
+		Issuer:     cfg.Issuer,
+		Audience:   cfg.Audience,
+		RolesClaim: cfg.RolesClaim,
+		Leeway:     cfg.Leeway,
+	})
+
+	policy := auth.NewPolicy().
+		Public("GET /health").
+		Public("GET /health/live").
+		Public("GET /health/ready").
+		Public("GET "+appCfg.Metrics.Path).
+		Require("POST /transactions", "transactions:write").
+		Require("GET /transactions/stream", "transactions:read").
+		Require("GET /transactions/export", "transactions:read").
+		Require("GET /transactions/{id}", "transactions:read").
+		Require("GET /transactions/{id}/convert", "transactions:read").
+		Require("PUT /transactions/{id}/legal-hold", "compliance").
+		Require("DELETE /transactions/{id}/legal-hold", "compliance").
+		Require("GET /legal-holds", "compliance").
+		Require("GET /transactions/{id}/audit", "compliance").
+		Require("GET /reports/summary", "transactions:read").
+		Require("POST /webhooks", "webhooks:manage").
+		Require("GET /webhooks", "webhooks:manage").
+		Require("GET /webhooks/{id}", "webhooks:manage").
+		Require("DELETE /webhooks/{id}", "webhooks:manage").
+		Require("GET /admin/webhooks/dead-letters", "admin").
+		Require("GET /admin/webhooks/{id}/deliveries", "admin").
+		Require("POST /admin/webhooks/deliveries/{id}/retry", "admin").
+		Require("GET /graphql", "transactions:read").
+		Require("POST /graphql", "transactions:read").
+		Require(graphql.AuditTrailField, "compliance").
+		Require("GET /admin/log-level", "admin").
+		Require("PUT /admin/log-level", "admin").
+		Require(transactionpb.TransactionService_CreateTransaction_FullMethodName, "transactions:write").
+		Require(transactionpb.TransactionService_GetTransaction_FullMethodName, "transactions:read").
+		Require(transactionpb.TransactionService_ListTransactions_FullMethodName, "transactions:read").
+		Require(transactionpb.TransactionService_ConvertTransaction_FullMethodName, "transactions:read")
+
+	log.Info("Authentication enabled", map[string]interface{}{
+		"issuer":   cfg.Issuer,
+		"audience": cfg.Audience,
+	})
+
+	return authenticator, policy, nil
+}
+
+// newRateLimitConfig converts the validated "rate:burst" settings into limits
+func newRateLimitConfig(cfg config.RateLimitConfig) middleware.RateLimitConfig {
+	limits := middleware.DefaultRateLimitConfig()
+
+	// The values have already been checked by config.Validate
+	if limit, err := ratelimit.ParseLimit(cfg.Write); err == nil {
+		limits.Write = limit
+	}
+	if limit, err := ratelimit.ParseLimit(cfg.Read); err == nil {
+		limits.Read = limit
+	}
+	if limit, err := ratelimit.ParseLimit(cfg.Convert); err == nil {
+		limits.Convert = limit
+	}
+
+	return limits
 }
@@ -0,0 +1,184 @@
+// cmd/server/main_test.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/config"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/rpc/transactionpb"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes
+type syncBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+// freePort returns a port that is free on the loopback interface
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// testConfig returns a configuration with a fresh data directory and free ports,
+// capturing the server's log output for the duration of the test
+func testConfig(t *testing.T) (*config.Config, *syncBuffer) {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.GRPC.Port = freePort(t)
+	cfg.Database.Path = filepath.Join(t.TempDir(), "data")
+	cfg.Health.MinFreeBytes = 0
+
+	var logs syncBuffer
+	previous := logOutput
+	logOutput = &logs
+	t.Cleanup(func() { logOutput = previous })
+
+	return cfg, &logs
+}
+
+// assertDatabaseReleased fails unless the data directory can be opened again, which
+// Badger's directory lock only allows once the server has closed it
+func assertDatabaseReleased(t *testing.T, path string) {
+	t.Helper()
+	badgerDB, err := badger.Open(badger.DefaultOptions(path).WithLogger(nil))
+	require.NoError(t, err, "database was not closed")
+	require.NoError(t, badgerDB.Close())
+}
+
+func TestRunStartupFailures(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	t.Run("Unwritable data directory", func(t *testing.T) {
+		cfg, logs := testConfig(t)
+
+		// A regular file where a parent directory is needed cannot be created over,
+		// even by root
+		blocker := filepath.Join(t.TempDir(), "blocker")
+		require.NoError(t, os.WriteFile(blocker, nil, 0600))
+		cfg.Database.Path = filepath.Join(blocker, "data")
+
+		err := run(context.Background(), cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "create database directory")
+		assert.Contains(t, logs.String(), "Server exited with error")
+	})
+
+	t.Run("Database locked by another process", func(t *testing.T) {
+		cfg, _ := testConfig(t)
+		badgerDB, err := badger.Open(badger.DefaultOptions(cfg.Database.Path).WithLogger(nil))
+		require.NoError(t, err)
+		defer badgerDB.Close()
+
+		err = run(context.Background(), cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "open database")
+	})
+
+	t.Run("Invalid tenants file closes the database", func(t *testing.T) {
+		cfg, logs := testConfig(t)
+		cfg.Tenancy.File = filepath.Join(t.TempDir(), "missing.yaml")
+
+		err := run(context.Background(), cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "load tenant configuration")
+		assert.Contains(t, logs.String(), "Database closed")
+		assertDatabaseReleased(t, cfg.Database.Path)
+	})
+
+	t.Run("Port in use closes the database", func(t *testing.T) {
+		cfg, _ := testConfig(t)
+		listener, err := net.Listen("tcp", cfg.Server.Addr())
+		require.NoError(t, err)
+		defer listener.Close()
+
+		err = run(context.Background(), cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "listen on")
+		assertDatabaseReleased(t, cfg.Database.Path)
+	})
+}
+
+func TestRunServesUntilCancelled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	cfg, logs := testConfig(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- run(ctx, cfg) }()
+
+	// Wait for the server to accept requests
+	url := fmt.Sprintf("http://%s/health/live", cfg.Server.Addr())
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(url)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// The gRPC API is served alongside
+	conn, err := grpc.NewClient(cfg.GRPCAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = transactionpb.NewTransactionServiceClient(conn).ListTransactions(ctx, &transactionpb.ListTransactionsRequest{})
+	require.NoError(t, err)
+
+	// As is the GraphQL endpoint
+	resp, err := http.Post(fmt.Sprintf("http://%s/graphql", cfg.Server.Addr()), "application/json",
+		strings.NewReader(`{"query": "{ transactions { nextCursor } }"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not return after the context was cancelled")
+	}
+
+	assert.Contains(t, logs.String(), "Server stopped")
+	assertDatabaseReleased(t, cfg.Database.Path)
+}
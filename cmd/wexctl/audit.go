@@ -0,0 +1,56 @@
+// cmd/wexctl/audit.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// auditExport writes the tenant's audit events, or one transaction's, as NDJSON
+func auditExport(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("audit export", "[-transaction ID]")
+	transactionID := fs.String("transaction", "", "only export the events of this transaction")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	// Encode writes one document per line
+	encoder := json.NewEncoder(a.stdout)
+	write := func(event *entity.AuditEvent) error {
+		return encoder.Encode(event)
+	}
+	if *transactionID != "" {
+		return a.audit.TransactionTrail(ctx, *transactionID, write)
+	}
+	return a.audit.Export(ctx, write)
+}
+
+// auditVerify checks the tenant's audit hash chain and prints its head hash
+func auditVerify(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("audit verify", "")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.audit.Verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range result.Problems {
+		fmt.Fprintln(a.stderr, problem)
+	}
+	fmt.Fprintf(a.stdout, "Checked %d audit events, %d problems\n", result.Events, len(result.Problems))
+	if result.HeadHash != "" {
+		fmt.Fprintf(a.stdout, "Head hash: %s\n", result.HeadHash)
+	}
+	if len(result.Problems) > 0 {
+		return fmt.Errorf("audit chain of tenant %s is broken", *tenantID)
+	}
+	return nil
+}
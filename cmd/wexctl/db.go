@@ -0,0 +1,53 @@
+// cmd/wexctl/db.go
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+)
+
+// compactDiscardRatio is the share of stale data a value log file must hold to be
+// rewritten
+const compactDiscardRatio = 0.5
+
+// dbCompact reclaims space left by overwritten and expired records
+func dbCompact(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("db compact", "")
+	if _, err := a.parse(ctx, fs, tenantID, args, 0, 0); err != nil {
+		return err
+	}
+
+	result, err := db.Compact(a.db, compactDiscardRatio)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Compacted %s: %d -> %d bytes, %d value log files rewritten\n",
+		a.cfg.Database.Path, result.Before, result.After, result.ValueLogFilesRewritten)
+	return nil
+}
+
+// dbVerify reports records that are corrupt or inconsistent with their keys
+func dbVerify(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("db verify", "")
+	if _, err := a.parse(ctx, fs, tenantID, args, 0, 0); err != nil {
+		return err
+	}
+
+	result, err := db.Verify(a.db)
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range result.Problems {
+		fmt.Fprintf(a.stderr, "%s: %v\n", problem.Key, problem.Err)
+	}
+	fmt.Fprintf(a.stdout, "Checked %d transactions and %d exchange rates, %d problems\n",
+		result.Transactions, result.Rates, len(result.Problems))
+	if len(result.Problems) > 0 {
+		return fmt.Errorf("%d records failed verification", len(result.Problems))
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// cmd/wexctl/main.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/api"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/config"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// errUsage is returned by commands given invalid arguments; the command's usage
+// has already been printed
+var errUsage = errors.New("invalid usage")
+
+// command is a wexctl subcommand
+type command struct {
+	name    string
+	args    string
+	summary string
+	// readOnly commands open the database without taking the write lock
+	readOnly bool
+	run      func(ctx context.Context, app *app, args []string) error
+}
+
+// commands lists the subcommands in the order they are shown in usage
+var commands = []command{
+	{"tx get", "ID...", "print transactions as JSON", true, txGet},
+	{"tx list", "", "list transactions", true, txList},
+	{"tx import", "FILE.csv", "create transactions from a CSV file with description,date,amount and optional category columns", false, txImport},
+	{"tx export", "[-format ndjson]", "write every transaction to stdout", true, txExport},
+	{"rates sync", "-currencies EUR,CAD", "fetch and store the rates needed to convert every transaction", false, ratesSync},
+	{"rates show", "CURRENCY DATE", "print the rate used for a conversion on DATE (YYYY-MM-DD)", false, ratesShow},
+	{"audit export", "[-transaction ID]", "write the audit log to stdout as NDJSON", true, auditExport},
+	{"audit verify", "", "check the audit log's hash chain", true, auditVerify},
+	{"db compact", "", "flatten the LSM tree and collect value log garbage", false, dbCompact},
+	{"db verify", "", "check checksums and every stored record", true, dbVerify},
+}
+
+// app holds what a command needs: the opened database and the services built on it
+type app struct {
+	cfg    *config.Config
+	db     *badger.DB
+	log    logger.Logger
+	stdout io.Writer
+	stderr io.Writer
+
+	tenants      *tenant.Registry
+	principal    *auth.Principal
+	transactions *service.TransactionService
+	audit        *service.AuditService
+	rates        repository.ExchangeRateRepository
+	// events is nil unless events are published
+	events repository.OutboxRepository
+}
+
+func main() {
+	// Stop on SIGINT or SIGTERM; long-running commands check the context
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run executes one command and returns the process exit code. Global settings are
+// the server's configuration flags and must come before the command.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	cfg, rest, err := config.LoadArgs("wexctl", args, cliEnv(getenv))
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n\n", err)
+		usage(stderr)
+		return exitUsage
+	}
+
+	cmd, cmdArgs, ok := findCommand(rest)
+	if !ok {
+		usage(stderr)
+		return exitUsage
+	}
+
+	app, err := openApp(cfg, cmd.readOnly, stdout, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "wexctl: %v\n", err)
+		return exitFailure
+	}
+
+	err = cmd.run(ctx, app, cmdArgs)
+	if closeErr := app.db.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("close database: %w", closeErr)
+	}
+
+	switch {
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case err != nil:
+		fmt.Fprintf(stderr, "wexctl %s: %v\n", cmd.name, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// cliEnv defaults settings that suit a command-line tool better than the server.
+// Commands report their own failures, so logging is off unless LOG_LEVEL is set,
+// and it is synchronous.
+func cliEnv(getenv func(string) string) func(string) string {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	defaults := map[string]string{
+		"LOG_LEVEL": "FATAL",
+		"LOG_ASYNC": "false",
+		// Commands open the database directly and serve no requests
+		"AUTH_DISABLED": "true",
+	}
+	return func(key string) string {
+		if value := getenv(key); value != "" {
+			return value
+		}
+		return defaults[key]
+	}
+}
+
+// findCommand matches the two-word command at the start of args
+func findCommand(args []string) (command, []string, bool) {
+	if len(args) < 2 {
+		return command{}, nil, false
+	}
+	name := args[0] + " " + args[1]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, args[2:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// usage writes the command list and the global settings to w
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: wexctl [settings] COMMAND [-tenant ID] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s %s\n\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nSettings (shared with the server):\n")
+	config.Usage(w)
+}
+
+// openApp opens the database and builds the repositories and services on it
+func openApp(cfg *config.Config, readOnly bool, stdout, stderr io.Writer) (*app, error) {
+	// The level was validated when the configuration was loaded
+	level, _ := logger.ParseLevel(cfg.Log.Level)
+	log := logger.NewJSONLogger(stderr, level)
+
+	tenants, err := cfg.Tenancy.Registry()
+	if err != nil {
+		return nil, fmt.Errorf("load tenant configuration: %w", err)
+	}
+
+	opts := badger.DefaultOptions(cfg.Database.Path)
+	opts.Logger = db.NewBadgerLogger(log)
+	opts.SyncWrites = cfg.Database.SyncWrites
+	opts.ReadOnly = readOnly
+	if readOnly {
+		// A read-only open must not create an empty database
+		if _, err := os.Stat(cfg.Database.Path); err != nil {
+			return nil, fmt.Errorf("open database %s: %w", cfg.Database.Path, err)
+		}
+	}
+
+	badgerDB, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("open database %s (is the server running?): %w", cfg.Database.Path, err)
+	}
+
+	txRepo := db.NewBadgerTransactionRepositoryWithRetention(badgerDB, db.RetentionConfig{
+		ReportExpired: cfg.Retention.ExpiredStatus == "gone",
+		GonePeriod:    cfg.Retention.GonePeriod(),
+		PublishEvents: cfg.PublishEvents(),
+	}, log, nil)
+	treasuryClient := api.NewTreasuryAPIClientWithConfig(api.ClientConfig{
+		BaseURL:        cfg.Treasury.BaseURL,
+		Timeout:        cfg.Treasury.Timeout,
+		MaxRetries:     cfg.Treasury.MaxRetries,
+		LookbackMonths: cfg.Treasury.LookbackMonths,
+		CacheTTL:       cfg.Treasury.CacheTTL,
+	}, log, nil)
+	audit := service.NewAuditService(db.NewBadgerAuditRepository(badgerDB, log, nil), log)
+	var events repository.OutboxRepository
+	if cfg.PublishEvents() {
+		events = db.NewBadgerOutboxRepository(badgerDB, log, nil)
+	}
+
+	return &app{
+		cfg:       cfg,
+		db:        badgerDB,
+		log:       log,
+		stdout:    stdout,
+		stderr:    stderr,
+		tenants:   tenants,
+		principal: &auth.Principal{Subject: "wexctl:" + currentUser()},
+		transactions: service.NewTransactionServiceWithConfig(txRepo, service.TransactionServiceConfig{
+			Retention: func(ctx context.Context) time.Duration {
+				return tenant.FromContext(ctx).Retention(cfg.Retention.Period())
+			},
+			Audit: audit,
+			// Imported transactions queue events for the server's relay
+			PublishEvents: cfg.PublishEvents(),
+		}, log),
+		audit:  audit,
+		events: events,
+		rates: db.NewBadgerExchangeRateRepository(badgerDB,
+			db.NewTreasuryExchangeRateRepository(treasuryClient, log), log),
+	}, nil
+}
+
+// flags returns a flag set for a command with the -tenant flag every command accepts
+func (a *app) flags(name, args string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("wexctl "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: wexctl %s [-tenant ID] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	tenantID := fs.String("tenant", tenant.DefaultID, "tenant whose transactions are used")
+	return fs, tenantID
+}
+
+// parse parses a command's flags and checks the number of positional arguments,
+// returning a context scoped to the selected tenant
+func (a *app) parse(ctx context.Context, fs *flag.FlagSet, tenantID *string, args []string, minArgs, maxArgs int) (context.Context, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, errUsage
+	}
+	if n := fs.NArg(); n < minArgs || (maxArgs >= 0 && n > maxArgs) {
+		fs.Usage()
+		return nil, errUsage
+	}
+	// Resolved like a request's tenant, so tenant settings such as retention apply
+	config, err := a.tenants.Resolve(*tenantID)
+	if err != nil {
+		return nil, err
+	}
+	// Audit events record the operator as the actor
+	ctx = middleware.WithPrincipal(ctx, a.principal)
+	return tenant.WithConfig(ctx, config), nil
+}
+
+// currentUser returns the name of the operating system user running wexctl
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,310 @@
+// cmd/wexctl/main_test.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTenants are the tenants registered for the commands under test
+const testTenants = `{"tenants": [
+	{"id": "default"},
+	{"id": "acme", "retention_days": 30},
+	{"id": "bad-rows"}
+]}`
+
+// harness runs wexctl commands against a temporary database and a fake Treasury API
+type harness struct {
+	t           *testing.T
+	dbPath      string
+	tenantsFile string
+	treasury    *httptest.Server
+	apiCalls    atomic.Int32
+	lastError   string
+}
+
+func newHarness(t *testing.T) *harness {
+	dir := t.TempDir()
+	h := &harness{t: t, dbPath: filepath.Join(dir, "data"), tenantsFile: filepath.Join(dir, "tenants.json")}
+	require.NoError(t, os.WriteFile(h.tenantsFile, []byte(testTenants), 0600))
+	h.treasury = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.apiCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"currency": "Euro", "exchange_rate": "0.85", "record_date": "2023-03-31"}]}`))
+	}))
+	t.Cleanup(h.treasury.Close)
+	return h
+}
+
+// run executes a command and returns its exit code and standard output
+func (h *harness) run(args ...string) (int, string) {
+	h.t.Helper()
+	var stdout, stderr bytes.Buffer
+	global := []string{"-db-path", h.dbPath, "-tenants-file", h.tenantsFile,
+		"-treasury-base-url", h.treasury.URL, "-treasury-max-retries", "1"}
+	code := run(context.Background(), append(global, args...), &stdout, &stderr, func(string) string { return "" })
+	h.lastError = stderr.String()
+	return code, stdout.String()
+}
+
+// importCSV writes content to a file and imports it
+func (h *harness) importCSV(content string, extra ...string) (int, string) {
+	h.t.Helper()
+	path := filepath.Join(h.t.TempDir(), "import.csv")
+	require.NoError(h.t, os.WriteFile(path, []byte(content), 0600))
+	return h.run(append(append([]string{"tx", "import"}, extra...), path)...)
+}
+
+// export returns the tenant's transactions as decoded by tx export
+func (h *harness) export(extra ...string) []entity.Transaction {
+	h.t.Helper()
+	code, out := h.run(append([]string{"tx", "export", "-format", "ndjson"}, extra...)...)
+	require.Equal(h.t, exitOK, code, h.lastError)
+
+	var txs []entity.Transaction
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var tx entity.Transaction
+		require.NoError(h.t, json.Unmarshal([]byte(line), &tx))
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// outboxEventTypes returns the types of the events waiting in the outbox
+func (h *harness) outboxEventTypes() []string {
+	h.t.Helper()
+	badgerDB, err := badger.Open(badger.DefaultOptions(h.dbPath).WithLogger(nil))
+	require.NoError(h.t, err)
+	defer badgerDB.Close()
+
+	entries, err := db.NewBadgerOutboxRepository(badgerDB, nil, nil).Due(context.Background(), time.Now(), 1000)
+	require.NoError(h.t, err)
+	types := make([]string, len(entries))
+	for i, entry := range entries {
+		types[i] = entry.Event.Type
+	}
+	return types
+}
+
+func TestTransactionCommands(t *testing.T) {
+	h := newHarness(t)
+
+	code, out := h.importCSV("description,date,amount\nFuel,2023-04-15,42.50\nTires,2023-05-01,120\n")
+	require.Equal(t, exitOK, code, h.lastError)
+	assert.Contains(t, out, "Imported 2 transactions")
+
+	t.Run("Export writes one JSON document per line", func(t *testing.T) {
+		txs := h.export()
+		require.Len(t, txs, 2)
+		descriptions := []string{txs[0].Description, txs[1].Description}
+		assert.ElementsMatch(t, []string{"Fuel", "Tires"}, descriptions)
+	})
+
+	t.Run("Get and list", func(t *testing.T) {
+		id := h.export()[0].ID
+
+		code, out := h.run("tx", "get", id)
+		require.Equal(t, exitOK, code, h.lastError)
+		var tx entity.Transaction
+		require.NoError(t, json.Unmarshal([]byte(out), &tx))
+		assert.Equal(t, id, tx.ID)
+
+		code, out = h.run("tx", "list")
+		require.Equal(t, exitOK, code, h.lastError)
+		assert.Contains(t, out, id)
+		assert.Contains(t, out, "Tires")
+	})
+
+	t.Run("Tenants are isolated", func(t *testing.T) {
+		code, _ := h.importCSV("description,date,amount,category\nAcme fuel,2023-04-20,10,fuel\n", "-tenant", "acme")
+		require.Equal(t, exitOK, code, h.lastError)
+
+		acme := h.export("-tenant", "acme")
+		require.Len(t, acme, 1)
+		assert.Equal(t, "fuel", acme[0].Category)
+		assert.Len(t, h.export(), 2)
+
+		code, _ = h.run("tx", "get", "-tenant", "acme", h.export()[0].ID)
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, h.lastError, "not found")
+
+		code, _ = h.run("tx", "list", "-tenant", "globex")
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, h.lastError, "unknown tenant")
+	})
+
+	t.Run("Tenant retention applies", func(t *testing.T) {
+		tx := h.export("-tenant", "acme")[0]
+		assert.Equal(t, tx.CreatedAt.Add(30*24*time.Hour).Unix(), tx.TTL)
+
+		tx = h.export()[0]
+		assert.Equal(t, tx.CreatedAt.Add(entity.DefaultRetention).Unix(), tx.TTL)
+	})
+
+	t.Run("Bad rows are reported and skipped", func(t *testing.T) {
+		code, out := h.importCSV("amount,description,date\n5,Snacks,2023-06-01\n-1,Refund,2023-06-02\n3,Soda,June 3\n7,Extra,2023-06-04,column\n", "-tenant", "bad-rows")
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, out, "Imported 1 transactions")
+		assert.Contains(t, h.lastError, "line 3:")
+		assert.Contains(t, h.lastError, "line 4: invalid date")
+		assert.Contains(t, h.lastError, "line 5:")
+		assert.Len(t, h.export("-tenant", "bad-rows"), 1)
+	})
+
+	t.Run("Database verifies", func(t *testing.T) {
+		code, out := h.run("db", "verify")
+		require.Equal(t, exitOK, code, h.lastError)
+		assert.Contains(t, out, "Checked 4 transactions")
+	})
+
+	t.Run("Compaction keeps the data", func(t *testing.T) {
+		code, _ := h.run("db", "compact")
+		require.Equal(t, exitOK, code, h.lastError)
+		assert.Len(t, h.export(), 2)
+	})
+}
+
+func TestRateCommands(t *testing.T) {
+	h := newHarness(t)
+	code, _ := h.importCSV("description,date,amount\nFuel,2023-04-15,42.50\nTires,2023-04-15,120\nWipers,2023-05-01,15\n")
+	require.Equal(t, exitOK, code, h.lastError)
+
+	// One lookup per currency and distinct date
+	code, out := h.run("rates", "sync", "-currencies", "EUR,CAD")
+	require.Equal(t, exitOK, code, h.lastError)
+	assert.Contains(t, out, "Synced 4 rates for 2 dates")
+	assert.Equal(t, int32(4), h.apiCalls.Load())
+	assert.Contains(t, h.outboxEventTypes(), entity.EventRateSyncCompleted)
+
+	// Synced rates are served from the database
+	code, out = h.run("rates", "show", "EUR", "2023-04-15")
+	require.Equal(t, exitOK, code, h.lastError)
+	assert.Contains(t, out, "EUR 2023-04-15: 0.85 (published 2023-03-31)")
+	assert.Equal(t, int32(4), h.apiCalls.Load())
+
+	code, _ = h.run("rates", "show", "EUR", "April")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestAuditCommands(t *testing.T) {
+	h := newHarness(t)
+	code, _ := h.importCSV("description,date,amount\nFuel,2023-04-15,42.50\nTires,2023-05-01,120\n")
+	require.Equal(t, exitOK, code, h.lastError)
+
+	t.Run("Export writes the chain as NDJSON", func(t *testing.T) {
+		code, out := h.run("audit", "export")
+		require.Equal(t, exitOK, code, h.lastError)
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 2)
+		var first, second entity.AuditEvent
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+		assert.Equal(t, entity.AuditTransactionCreated, first.Action)
+		assert.True(t, strings.HasPrefix(first.Actor, "wexctl:"), first.Actor)
+		assert.Equal(t, first.Hash, second.PrevHash)
+
+		code, out = h.run("audit", "export", "-transaction", second.TransactionID)
+		require.Equal(t, exitOK, code, h.lastError)
+		assert.Equal(t, lines[1], strings.TrimSpace(out))
+	})
+
+	t.Run("Verify accepts an intact chain", func(t *testing.T) {
+		code, out := h.run("audit", "verify")
+		require.Equal(t, exitOK, code, h.lastError)
+		assert.Contains(t, out, "Checked 2 audit events, 0 problems")
+		assert.Contains(t, out, "Head hash: ")
+	})
+
+	t.Run("Verify detects tampering", func(t *testing.T) {
+		badgerDB, err := badger.Open(badger.DefaultOptions(h.dbPath).WithLogger(nil))
+		require.NoError(t, err)
+		key := []byte("t:default:audit:00000000000000000001")
+		require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+			item, err := txn.Get(key)
+			if err != nil {
+				return err
+			}
+			var event entity.AuditEvent
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &event) }); err != nil {
+				return err
+			}
+			event.Actor = "someone else"
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			return txn.Set(key, data)
+		}))
+		require.NoError(t, badgerDB.Close())
+
+		code, _ := h.run("audit", "verify")
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, h.lastError, "event 1: hash does not match its contents")
+	})
+}
+
+func TestUsageErrors(t *testing.T) {
+	h := newHarness(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"No command", nil},
+		{"Unknown command", []string{"tx", "delete"}},
+		{"Unknown global flag", []string{"-no-such-flag", "tx", "list"}},
+		{"Missing argument", []string{"tx", "get"}},
+		{"Unsupported export format", []string{"tx", "export", "-format", "xml"}},
+		{"Sync without currencies", []string{"rates", "sync"}},
+	}
+
+	// The database must exist for read-only commands to reach argument checks
+	code, _ := h.importCSV("description,date,amount\n")
+	require.Equal(t, exitOK, code, h.lastError)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, _ := h.run(tc.args...)
+			assert.Equal(t, exitUsage, code)
+			assert.Contains(t, h.lastError, "Usage")
+		})
+	}
+}
+
+func TestReadOnlyCommandsRequireExistingDatabase(t *testing.T) {
+	h := newHarness(t)
+
+	code, _ := h.run("tx", "list")
+	assert.Equal(t, exitFailure, code)
+	assert.NoDirExists(t, h.dbPath)
+}
+
+func TestWriteCommandsFailWhileDatabaseInUse(t *testing.T) {
+	h := newHarness(t)
+	badgerDB, err := badger.Open(badger.DefaultOptions(h.dbPath).WithLogger(nil))
+	require.NoError(t, err)
+	defer badgerDB.Close()
+
+	code, _ := h.run("db", "compact")
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, h.lastError, "is the server running?")
+}
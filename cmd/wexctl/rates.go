@@ -0,0 +1,110 @@
+// cmd/wexctl/rates.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// ratesSync looks up the rate for each currency on each date the tenant has a
+// transaction, so the rates are stored before they are needed, and announces the
+// result with a RateSyncCompleted event
+func ratesSync(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("rates sync", "-currencies EUR,CAD")
+	currencyList := fs.String("currencies", "", "comma-separated currencies to fetch")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	var currencies []string
+	for _, currency := range strings.Split(*currencyList, ",") {
+		if currency = strings.TrimSpace(currency); currency != "" {
+			currencies = append(currencies, currency)
+		}
+	}
+	if len(currencies) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	dates := make(map[time.Time]bool)
+	err = a.transactions.ListTransactions(ctx, func(tx *entity.Transaction) error {
+		dates[tx.Date] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sorted := make([]time.Time, 0, len(dates))
+	for date := range dates {
+		sorted = append(sorted, date)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	synced, failed := 0, 0
+	for _, currency := range currencies {
+		for _, date := range sorted {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, err := a.rates.FindRate(ctx, currency, date); err != nil {
+				fmt.Fprintf(a.stderr, "%s %s: %v\n", currency, date.Format("2006-01-02"), err)
+				failed++
+				continue
+			}
+			synced++
+		}
+	}
+
+	fmt.Fprintf(a.stdout, "Synced %d rates for %d dates\n", synced, len(sorted))
+	if a.events != nil {
+		event, err := entity.NewRateSyncCompletedEvent(uuid.New().String(), entity.RateSyncPayload{
+			Currencies: currencies,
+			Dates:      len(sorted),
+			Synced:     synced,
+			Failed:     failed,
+		}, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if err := a.events.Add(ctx, event); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d lookups failed", failed)
+	}
+	return nil
+}
+
+// ratesShow prints the rate a conversion on the given date would use
+func ratesShow(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("rates show", "CURRENCY DATE")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse("2006-01-02", fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(a.stderr, "invalid date: expected YYYY-MM-DD")
+		fs.Usage()
+		return errUsage
+	}
+
+	rate, err := a.rates.FindRate(ctx, fs.Arg(0), date)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "%s %s: %v (published %s)\n",
+		strings.ToUpper(fs.Arg(0)), date.Format("2006-01-02"), rate.Rate, rate.Date.Format("2006-01-02"))
+	return nil
+}
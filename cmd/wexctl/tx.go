@@ -0,0 +1,182 @@
+// cmd/wexctl/tx.go
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// importColumns are the CSV columns read by tx import; a category column may
+// also be given
+var importColumns = []string{"description", "date", "amount"}
+
+// txGet prints the named transactions
+func txGet(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("tx get", "ID...")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+	for _, id := range fs.Args() {
+		tx, err := a.transactions.GetTransaction(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// txList prints a table of the tenant's transactions
+func txList(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("tx list", "")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDATE\tAMOUNT\tDESCRIPTION")
+	err = a.transactions.ListTransactions(ctx, func(tx *entity.Transaction) error {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%.2f\t%s\n", tx.ID, tx.Date.Format("2006-01-02"), tx.Amount, tx.Description)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// txExport writes every transaction of the tenant to stdout
+func txExport(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("tx export", "[-format ndjson]")
+	format := fs.String("format", "ndjson", "output format (ndjson)")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 0, 0)
+	if err != nil {
+		return err
+	}
+	if *format != "ndjson" {
+		fmt.Fprintf(a.stderr, "unsupported format %q\n", *format)
+		fs.Usage()
+		return errUsage
+	}
+
+	// Encode writes one document per line
+	encoder := json.NewEncoder(a.stdout)
+	return a.transactions.ListTransactions(ctx, func(tx *entity.Transaction) error {
+		return encoder.Encode(tx)
+	})
+}
+
+// txImport creates a transaction for each row of a CSV file. The first row names
+// the columns. Rows that fail are reported and skipped; the command then fails.
+func txImport(ctx context.Context, a *app, args []string) error {
+	fs, tenantID := a.flags("tx import", "FILE.csv")
+	ctx, err := a.parse(ctx, fs, tenantID, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	columns, err := columnIndexes(header)
+	if err != nil {
+		return err
+	}
+
+	imported, failed := 0, 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var line int
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			line = parseErr.Line
+		} else {
+			line, _ = reader.FieldPos(0)
+			err = importRecord(ctx, a, record, columns)
+		}
+		if err != nil {
+			// A malformed row does not stop csv.Reader, so carry on with the next one
+			fmt.Fprintf(a.stderr, "line %d: %v\n", line, err)
+			failed++
+			continue
+		}
+		imported++
+	}
+
+	fmt.Fprintf(a.stdout, "Imported %d transactions\n", imported)
+	if failed > 0 {
+		return fmt.Errorf("%d rows failed", failed)
+	}
+	return nil
+}
+
+// columnIndexes maps each import column to its position in the header
+func columnIndexes(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("header has no %q column; expected %s", name, strings.Join(importColumns, ","))
+		}
+	}
+	return columns, nil
+}
+
+// importRecord creates the transaction described by one CSV row
+func importRecord(ctx context.Context, a *app, record []string, columns map[string]int) error {
+	field := func(name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	date, err := time.Parse("2006-01-02", field("date"))
+	if err != nil {
+		return errors.New("invalid date: expected YYYY-MM-DD")
+	}
+	amount, err := strconv.ParseFloat(field("amount"), 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", field("amount"))
+	}
+
+	category := ""
+	if _, ok := columns["category"]; ok {
+		category = field("category")
+	}
+
+	_, err = a.transactions.CreateCategorizedTransaction(ctx, field("description"), category, date, amount)
+	return err
+}
@@ -0,0 +1,152 @@
+// Package service internal/application/service/audit_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+)
+
+// AuditVerification reports the result of checking a tenant's audit chain
+type AuditVerification struct {
+	// Events is the number of events checked
+	Events int
+	// HeadHash is the hash of the last event. Recording it elsewhere lets a later
+	// check detect events removed from the end of the chain.
+	HeadHash string
+	// Problems describes each break in the chain
+	Problems []string
+}
+
+// AuditService records transaction mutations and conversions in the audit log
+type AuditService struct {
+	repo   repository.AuditRepository
+	logger logger.Logger
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo repository.AuditRepository, log logger.Logger) *AuditService {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &AuditService{
+		repo:   repo,
+		logger: log,
+	}
+}
+
+// Event builds the event for a transaction, attributed to the authenticated subject
+// and request of ctx, for a repository to append with the change it records. It
+// returns nil on a nil AuditService, so services can run without an audit log.
+func (s *AuditService) Event(ctx context.Context, action, transactionID string, details map[string]string) *entity.AuditEvent {
+	if s == nil {
+		return nil
+	}
+
+	return &entity.AuditEvent{
+		TransactionID: transactionID,
+		Action:        action,
+		Actor:         middleware.GetSubject(ctx),
+		RequestID:     middleware.GetRequestID(ctx),
+		Details:       details,
+	}
+}
+
+// Record appends an event for a transaction, attributed to the authenticated
+// subject and request of ctx. It does nothing on a nil AuditService, so services
+// can run without an audit log.
+func (s *AuditService) Record(ctx context.Context, action, transactionID string, details map[string]string) error {
+	if s == nil {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	if err := s.repo.Append(ctx, s.Event(ctx, action, transactionID, details)); err != nil {
+		tracing.SetError(span, err)
+		return err
+	}
+	return nil
+}
+
+// TransactionTrail calls fn for each event recorded against a transaction, oldest
+// first. Events outlive the transaction, so the trail of an expired transaction
+// can still be read.
+func (s *AuditService) TransactionTrail(ctx context.Context, transactionID string, fn func(*entity.AuditEvent) error) error {
+	ctx, span := tracing.Start(ctx, "AuditService.TransactionTrail")
+	defer span.End()
+
+	if err := s.repo.ListByTransaction(ctx, transactionID, fn); err != nil {
+		logger.ForContext(ctx, s.logger).Error("Failed to read audit trail", map[string]interface{}{
+			"id":    transactionID,
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return err
+	}
+	return nil
+}
+
+// Export calls fn for each event of the tenant, oldest first
+func (s *AuditService) Export(ctx context.Context, fn func(*entity.AuditEvent) error) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Export")
+	defer span.End()
+
+	if err := s.repo.List(ctx, fn); err != nil {
+		tracing.SetError(span, err)
+		return err
+	}
+	return nil
+}
+
+// Verify walks the tenant's audit chain and checks that sequence numbers are
+// contiguous, that each event links to the hash of the one before it and that
+// each hash matches the event's contents
+func (s *AuditService) Verify(ctx context.Context) (AuditVerification, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.Verify")
+	defer span.End()
+
+	var result AuditVerification
+	var previous *entity.AuditEvent
+	err := s.repo.List(ctx, func(event *entity.AuditEvent) error {
+		result.Events++
+
+		wantSequence, wantPrevHash := uint64(1), ""
+		if previous != nil {
+			wantSequence, wantPrevHash = previous.Sequence+1, previous.Hash
+		}
+		if event.Sequence != wantSequence {
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("event %d: expected sequence %d", event.Sequence, wantSequence))
+		}
+		if event.PrevHash != wantPrevHash {
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("event %d: does not link to the previous event", event.Sequence))
+		}
+		if event.Hash != event.ComputeHash() {
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("event %d: hash does not match its contents", event.Sequence))
+		}
+
+		previous = event
+		result.HeadHash = event.Hash
+		return nil
+	})
+	if err != nil {
+		tracing.SetError(span, err)
+		return result, err
+	}
+
+	logger.ForContext(ctx, s.logger).Info("Audit chain verified", map[string]interface{}{
+		"events":   result.Events,
+		"problems": len(result.Problems),
+	})
+	return result, nil
+}
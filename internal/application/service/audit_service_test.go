@@ -0,0 +1,109 @@
+// internal/application/service/audit_service_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// auditChain builds a valid chain of n events
+func auditChain(n int) []*entity.AuditEvent {
+	var events []*entity.AuditEvent
+	prevHash := ""
+	for i := 1; i <= n; i++ {
+		event := &entity.AuditEvent{
+			Sequence:      uint64(i),
+			TransactionID: "test-id",
+			Action:        entity.AuditTransactionConverted,
+			Actor:         "auditor",
+			OccurredAt:    time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			Details:       map[string]string{"currency": "EUR"},
+			PrevHash:      prevHash,
+		}
+		event.Hash = event.ComputeHash()
+		prevHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestAuditServiceRecord(t *testing.T) {
+	repo := new(mocks.MockAuditRepository)
+	audit := NewAuditService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+	ctx := middleware.WithPrincipal(context.Background(), &auth.Principal{Subject: "auditor"})
+
+	repo.On("Append", mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+		return event.Action == entity.AuditTransactionCreated && event.TransactionID == "test-id" &&
+			event.Actor == "auditor" && event.Details["amount"] == "10.00"
+	})).Return(nil).Once()
+
+	require.NoError(t, audit.Record(ctx, entity.AuditTransactionCreated, "test-id", map[string]string{"amount": "10.00"}))
+	repo.AssertExpectations(t)
+
+	// A service without an audit log records nothing
+	var none *AuditService
+	assert.NoError(t, none.Record(ctx, entity.AuditTransactionCreated, "test-id", nil))
+}
+
+func TestAuditServiceVerify(t *testing.T) {
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	ctx := context.Background()
+
+	// verify checks the chain made of events
+	verify := func(events []*entity.AuditEvent) AuditVerification {
+		repo := new(mocks.MockAuditRepository)
+		repo.On("List", mock.Anything, mock.Anything).Return(events, nil)
+		result, err := NewAuditService(repo, log).Verify(ctx)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Intact chain", func(t *testing.T) {
+		events := auditChain(3)
+		result := verify(events)
+		assert.Equal(t, 3, result.Events)
+		assert.Equal(t, events[2].Hash, result.HeadHash)
+		assert.Empty(t, result.Problems)
+	})
+
+	t.Run("Altered event", func(t *testing.T) {
+		events := auditChain(3)
+		events[1].Details["currency"] = "GBP"
+		assert.Equal(t, []string{"event 2: hash does not match its contents"}, verify(events).Problems)
+	})
+
+	t.Run("Removed event", func(t *testing.T) {
+		events := auditChain(3)
+		result := verify([]*entity.AuditEvent{events[0], events[2]})
+		assert.Equal(t, []string{
+			"event 3: expected sequence 2",
+			"event 3: does not link to the previous event",
+		}, result.Problems)
+	})
+
+	t.Run("Rewritten chain", func(t *testing.T) {
+		// Rehashing an altered event still breaks the link from its successor
+		events := auditChain(3)
+		events[0].Actor = "someone else"
+		events[0].Hash = events[0].ComputeHash()
+		assert.Equal(t, []string{"event 2: does not link to the previous event"}, verify(events).Problems)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repo := new(mocks.MockAuditRepository)
+		repo.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("repository error"))
+		_, err := NewAuditService(repo, log).Verify(ctx)
+		assert.EqualError(t, err, "repository error")
+	})
+}
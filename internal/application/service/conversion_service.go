@@ -3,15 +3,24 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
-	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// maxConcurrentRateLookups limits the exchange rate lookups a batch runs at once
+const maxConcurrentRateLookups = 8
+
 // ConvertedTransaction represents a transaction with conversion information
 type ConvertedTransaction struct {
 	ID              string    `json:"id"`
@@ -28,11 +37,18 @@ type ConvertedTransaction struct {
 type ConversionService struct {
 	txRepo       repository.TransactionRepository
 	exchangeRepo repository.ExchangeRateRepository
+	audit        *AuditService
 	logger       logger.Logger
 }
 
 // NewConversionService creates a new conversion service
 func NewConversionService(txRepo repository.TransactionRepository, exchangeRepo repository.ExchangeRateRepository, log logger.Logger) *ConversionService {
+	return NewConversionServiceWithAudit(txRepo, exchangeRepo, nil, log)
+}
+
+// NewConversionServiceWithAudit creates a new conversion service that records
+// every conversion it serves in the audit log
+func NewConversionServiceWithAudit(txRepo repository.TransactionRepository, exchangeRepo repository.ExchangeRateRepository, audit *AuditService, log logger.Logger) *ConversionService {
 	if log == nil {
 		log = logger.GetDefaultLogger()
 	}
@@ -40,33 +56,36 @@ func NewConversionService(txRepo repository.TransactionRepository, exchangeRepo
 	return &ConversionService{
 		txRepo:       txRepo,
 		exchangeRepo: exchangeRepo,
+		audit:        audit,
 		logger:       log,
 	}
 }
 
 // GetTransactionInCurrency retrieves a transaction converted to the specified currency
 func (s *ConversionService) GetTransactionInCurrency(ctx context.Context, id, currency string) (*ConvertedTransaction, error) {
-	requestID := middleware.GetRequestID(ctx)
+	ctx, span := tracing.Start(ctx, "ConversionService.GetTransactionInCurrency",
+		trace.WithAttributes(attribute.String("currency", currency)))
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
 
-	s.logger.Info("Converting transaction currency", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
-		"currency":   currency,
+	log.Info("Converting transaction currency", map[string]interface{}{
+		"id":       id,
+		"currency": currency,
 	})
 
 	// Get transaction
 	tx, err := s.txRepo.FindByID(ctx, id)
 	if err != nil {
-		s.logger.Error("Failed to retrieve transaction for conversion", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
-			"error":      err.Error(),
+		log.Error("Failed to retrieve transaction for conversion", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
 		})
+		tracing.SetError(span, err)
 		return nil, fmt.Errorf("failed to retrieve transaction: %w", err)
 	}
 
-	s.logger.Debug("Retrieved transaction for conversion", map[string]interface{}{
-		"request_id":  requestID,
+	log.Debug("Retrieved transaction for conversion", map[string]interface{}{
 		"id":          id,
 		"description": tx.Description,
 		"date":        tx.Date.Format("2006-01-02"),
@@ -74,46 +93,188 @@ func (s *ConversionService) GetTransactionInCurrency(ctx context.Context, id, cu
 	})
 
 	// Find applicable exchange rate
-	s.logger.Debug("Finding exchange rate", map[string]interface{}{
-		"request_id": requestID,
-		"currency":   currency,
-		"date":       tx.Date.Format("2006-01-02"),
+	log.Debug("Finding exchange rate", map[string]interface{}{
+		"currency": currency,
+		"date":     tx.Date.Format("2006-01-02"),
 	})
 
 	rate, err := s.exchangeRepo.FindRate(ctx, currency, tx.Date)
 	if err != nil {
-		s.logger.Error("Failed to get exchange rate", map[string]interface{}{
-			"request_id": requestID,
-			"currency":   currency,
-			"date":       tx.Date.Format("2006-01-02"),
-			"error":      err.Error(),
+		log.Error("Failed to get exchange rate", map[string]interface{}{
+			"currency": currency,
+			"date":     tx.Date.Format("2006-01-02"),
+			"error":    err.Error(),
 		})
-		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+		tracing.SetError(span, err)
+		return nil, rateError(err)
+	}
+
+	log.Info("Found exchange rate", map[string]interface{}{
+		"currency":  currency,
+		"rate_date": rate.Date.Format("2006-01-02"),
+		"rate":      rate.Rate,
+	})
+
+	converted, err := s.convert(ctx, tx, currency, rate)
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+	return converted, nil
+}
+
+// RateKey identifies the exchange rate applicable to a currency on a date
+type RateKey struct {
+	Currency string
+	Date     time.Time
+}
+
+// NewRateKey creates the key of a currency's rate on the day of date
+func NewRateKey(currency string, date time.Time) RateKey {
+	y, m, d := date.Date()
+	return RateKey{Currency: currency, Date: time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// RateResult is the outcome of an exchange rate lookup
+type RateResult struct {
+	Rate *entity.ExchangeRate
+	Err  error
+}
+
+// ConversionRequest names a transaction and the currency to convert it to
+type ConversionRequest struct {
+	Transaction *entity.Transaction
+	Currency    string
+}
+
+// ConversionResult is the outcome of a ConversionRequest
+type ConversionResult struct {
+	Converted *ConvertedTransaction
+	Err       error
+}
+
+// FindRates looks up the rate of each distinct key once, running up to
+// maxConcurrentRateLookups lookups at a time
+func (s *ConversionService) FindRates(ctx context.Context, keys []RateKey) map[RateKey]RateResult {
+	ctx, span := tracing.Start(ctx, "ConversionService.FindRates")
+	defer span.End()
+
+	results := make(map[RateKey]RateResult, len(keys))
+	var distinct []RateKey
+	for _, key := range keys {
+		if _, ok := results[key]; !ok {
+			results[key] = RateResult{}
+			distinct = append(distinct, key)
+		}
+	}
+	span.SetAttributes(attribute.Int("lookups", len(distinct)))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRateLookups)
+	for _, key := range distinct {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key RateKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := s.findRate(ctx, key)
+			mu.Lock()
+			results[key] = result
+			mu.Unlock()
+		}(key)
 	}
+	wg.Wait()
 
-	s.logger.Info("Found exchange rate", map[string]interface{}{
-		"request_id": requestID,
-		"currency":   currency,
-		"rate_date":  rate.Date.Format("2006-01-02"),
-		"rate":       rate.Rate,
+	logger.ForContext(ctx, s.logger).Debug("Exchange rates found", map[string]interface{}{
+		"keys":    len(keys),
+		"lookups": len(distinct),
 	})
+	return results
+}
+
+// findRate looks up the rate of a key
+func (s *ConversionService) findRate(ctx context.Context, key RateKey) RateResult {
+	rate, err := s.exchangeRepo.FindRate(ctx, key.Currency, key.Date)
+	if err != nil {
+		return RateResult{Err: rateError(err)}
+	}
+	return RateResult{Rate: rate}
+}
+
+// rateError wraps the error of a rate lookup, marking failures other than there
+// being no applicable rate with ErrRateServiceUnavailable
+func rateError(err error) error {
+	if errors.Is(err, repository.ErrNoExchangeRate) || errors.Is(err, repository.ErrRateOutOfRange) {
+		return fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	return fmt.Errorf("failed to get exchange rate: %w: %w", ErrRateServiceUnavailable, err)
+}
+
+// ConvertTransactions converts a batch of transactions, looking up the rate of
+// each distinct currency and transaction date once rather than once per
+// conversion. Results are in the order of the requests.
+func (s *ConversionService) ConvertTransactions(ctx context.Context, requests []ConversionRequest) []ConversionResult {
+	ctx, span := tracing.Start(ctx, "ConversionService.ConvertTransactions",
+		trace.WithAttributes(attribute.Int("conversions", len(requests))))
+	defer span.End()
+
+	keys := make([]RateKey, len(requests))
+	for i, req := range requests {
+		keys[i] = NewRateKey(req.Currency, req.Transaction.Date)
+	}
+	rates := s.FindRates(ctx, keys)
+
+	results := make([]ConversionResult, len(requests))
+	for i, req := range requests {
+		rate := rates[keys[i]]
+		if rate.Err != nil {
+			results[i].Err = rate.Err
+			continue
+		}
+		results[i].Converted, results[i].Err = s.convert(ctx, req.Transaction, req.Currency, rate.Rate)
+	}
+	return results
+}
 
-	// Calculate converted amount
-	convertedAmount := tx.Amount * rate.Rate
+// convert applies a rate to a transaction and records the conversion in the
+// audit log
+func (s *ConversionService) convert(ctx context.Context, tx *entity.Transaction, currency string, rate *entity.ExchangeRate) (*ConvertedTransaction, error) {
+	log := logger.ForContext(ctx, s.logger)
 
-	// Round to two decimal places
-	convertedAmount = math.Round(convertedAmount*100) / 100
+	converted := newConvertedTransaction(tx, currency, rate)
 
-	s.logger.Info("Conversion completed", map[string]interface{}{
-		"request_id":       requestID,
-		"id":               id,
+	log.Info("Conversion completed", map[string]interface{}{
+		"id":               tx.ID,
 		"currency":         currency,
 		"original_amount":  tx.Amount,
 		"exchange_rate":    rate.Rate,
-		"converted_amount": convertedAmount,
+		"converted_amount": converted.ConvertedAmount,
 		"rate_date":        rate.Date.Format("2006-01-02"),
 	})
 
+	// A conversion is only served once it has been recorded
+	err := s.audit.Record(ctx, entity.AuditTransactionConverted, tx.ID, map[string]string{
+		"currency":         currency,
+		"original_amount":  strconv.FormatFloat(tx.Amount, 'f', 2, 64),
+		"exchange_rate":    strconv.FormatFloat(rate.Rate, 'f', -1, 64),
+		"converted_amount": strconv.FormatFloat(converted.ConvertedAmount, 'f', 2, 64),
+		"rate_date":        rate.Date.Format("2006-01-02"),
+	})
+	if err != nil {
+		log.Error("Failed to record conversion in the audit log", map[string]interface{}{
+			"id":    tx.ID,
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to record conversion: %w", err)
+	}
+
+	return converted, nil
+}
+
+// newConvertedTransaction applies a rate to a transaction
+func newConvertedTransaction(tx *entity.Transaction, currency string, rate *entity.ExchangeRate) *ConvertedTransaction {
 	return &ConvertedTransaction{
 		ID:              tx.ID,
 		Description:     tx.Description,
@@ -121,7 +282,12 @@ func (s *ConversionService) GetTransactionInCurrency(ctx context.Context, id, cu
 		OriginalAmount:  tx.Amount,
 		Currency:        currency,
 		ExchangeRate:    rate.Rate,
-		ConvertedAmount: convertedAmount,
+		ConvertedAmount: convertAmount(tx.Amount, rate.Rate),
 		RateDate:        rate.Date,
-	}, nil
+	}
+}
+
+// convertAmount applies a rate to an amount, rounding to the cent
+func convertAmount(amount, rate float64) float64 {
+	return math.Round(amount*rate*100) / 100
 }
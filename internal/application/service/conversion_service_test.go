@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,6 +24,11 @@ func (m *MockTransactionRepository) Store(ctx context.Context, tx *entity.Transa
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StoreWithEvents(ctx context.Context, tx *entity.Transaction, events []*entity.DomainEvent, audit *entity.AuditEvent) (string, error) {
+	args := m.Called(ctx, tx, events, audit)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByID(ctx context.Context, id string) (*entity.Transaction, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -31,6 +37,47 @@ func (m *MockTransactionRepository) FindByID(ctx context.Context, id string) (*e
 	return args.Get(0).(*entity.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) List(ctx context.Context, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) ListAfter(ctx context.Context, after string, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, after, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) ListByDate(ctx context.Context, from, to time.Time, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, from, to, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) ListByDateAfter(ctx context.Context, from, to time.Time, after *entity.Transaction, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, from, to, after, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) PlaceLegalHold(ctx context.Context, id string, hold entity.LegalHold, event repository.EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error) {
+	args := m.Called(ctx, id, hold, event, audit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReleaseLegalHold(ctx context.Context, id string, event repository.EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error) {
+	args := m.Called(ctx, id, event, audit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListLegalHolds(ctx context.Context, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, fn)
+	return args.Error(0)
+}
+
 // MockExchangeRateRepository is a mock implementation of the exchange rate repository
 type MockExchangeRateRepository struct {
 	mock.Mock
@@ -75,8 +122,8 @@ func TestGetTransactionInCurrency(t *testing.T) {
 		}
 
 		// Mock expectations
-		repo.On("FindByID", ctx, txID).Return(tx, nil).Once()
-		exchangeRepo.On("FindRate", ctx, currency, tx.Date).Return(rate, nil).Once()
+		repo.On("FindByID", mock.Anything, txID).Return(tx, nil).Once()
+		exchangeRepo.On("FindRate", mock.Anything, currency, tx.Date).Return(rate, nil).Once()
 
 		// Execute
 		result, err := service.GetTransactionInCurrency(ctx, txID, currency)
@@ -102,7 +149,7 @@ func TestGetTransactionInCurrency(t *testing.T) {
 		currency := "EUR"
 
 		// Mock expectations
-		repo.On("FindByID", ctx, txID).Return(nil, errors.New("transaction not found")).Once()
+		repo.On("FindByID", mock.Anything, txID).Return(nil, errors.New("transaction not found")).Once()
 
 		// Execute
 		result, err := service.GetTransactionInCurrency(ctx, txID, currency)
@@ -128,9 +175,9 @@ func TestGetTransactionInCurrency(t *testing.T) {
 		}
 
 		// Mock expectations
-		repo.On("FindByID", ctx, txID).Return(tx, nil).Once()
-		exchangeRepo.On("FindRate", ctx, currency, tx.Date).
-			Return(nil, errors.New("no exchange rate available")).Once()
+		repo.On("FindByID", mock.Anything, txID).Return(tx, nil).Once()
+		exchangeRepo.On("FindRate", mock.Anything, currency, tx.Date).
+			Return(nil, repository.ErrNoExchangeRate).Once()
 
 		// Execute
 		result, err := service.GetTransactionInCurrency(ctx, txID, currency)
@@ -163,8 +210,8 @@ func TestGetTransactionInCurrency(t *testing.T) {
 		}
 
 		// Mock expectations
-		repo.On("FindByID", ctx, txID).Return(tx, nil).Once()
-		exchangeRepo.On("FindRate", ctx, currency, tx.Date).Return(rate, nil).Once()
+		repo.On("FindByID", mock.Anything, txID).Return(tx, nil).Once()
+		exchangeRepo.On("FindRate", mock.Anything, currency, tx.Date).Return(rate, nil).Once()
 
 		// Execute
 		result, err := service.GetTransactionInCurrency(ctx, txID, currency)
@@ -178,3 +225,43 @@ func TestGetTransactionInCurrency(t *testing.T) {
 		exchangeRepo.AssertExpectations(t)
 	})
 }
+
+func TestConvertTransactions(t *testing.T) {
+	exchangeRepo := new(MockExchangeRateRepository)
+	service := NewConversionService(new(MockTransactionRepository), exchangeRepo, logger.NewJSONLogger(nil, logger.InfoLevel))
+
+	jan := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC)
+	txs := []*entity.Transaction{
+		{ID: "a", Date: jan, Amount: 100},
+		{ID: "b", Date: jan, Amount: 10},
+		{ID: "c", Date: feb, Amount: 20},
+	}
+
+	// Each currency and date is looked up once however many transactions share it
+	exchangeRepo.On("FindRate", mock.Anything, "EUR", jan).
+		Return(&entity.ExchangeRate{Currency: "EUR", Date: jan, Rate: 0.9}, nil).Once()
+	exchangeRepo.On("FindRate", mock.Anything, "EUR", feb).
+		Return(&entity.ExchangeRate{Currency: "EUR", Date: feb, Rate: 0.8}, nil).Once()
+	exchangeRepo.On("FindRate", mock.Anything, "XYZ", jan).
+		Return(nil, repository.ErrNoExchangeRate).Once()
+
+	results := service.ConvertTransactions(context.Background(), []ConversionRequest{
+		{Transaction: txs[0], Currency: "EUR"},
+		{Transaction: txs[1], Currency: "EUR"},
+		{Transaction: txs[2], Currency: "EUR"},
+		{Transaction: txs[0], Currency: "XYZ"},
+		{Transaction: txs[1], Currency: "XYZ"},
+	})
+
+	assert.Len(t, results, 5)
+	assert.Equal(t, 90.0, results[0].Converted.ConvertedAmount)
+	assert.Equal(t, 9.0, results[1].Converted.ConvertedAmount)
+	assert.Equal(t, 16.0, results[2].Converted.ConvertedAmount)
+	assert.Equal(t, feb, results[2].Converted.RateDate)
+	for _, result := range results[3:] {
+		assert.Nil(t, result.Converted)
+		assert.ErrorContains(t, result.Err, "failed to get exchange rate: no exchange rate available")
+	}
+	exchangeRepo.AssertExpectations(t)
+}
@@ -0,0 +1,85 @@
+// Package service internal/application/service/errors.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+)
+
+var (
+	// ErrRateServiceUnavailable is wrapped by conversion errors whose exchange rate
+	// could not be looked up, as opposed to there being no applicable rate
+	ErrRateServiceUnavailable = errors.New("exchange rate service unavailable")
+
+	// ErrInvalidReport is wrapped by summary report errors caused by the filter
+	ErrInvalidReport = errors.New("invalid report filter")
+)
+
+// ErrorKind classifies the errors services return, so that every API reports an
+// error the same way
+type ErrorKind int
+
+// Kinds of service error
+const (
+	ErrorInternal ErrorKind = iota
+	ErrorCanceled
+	ErrorDeadlineExceeded
+	ErrorInvalid
+	ErrorNotFound
+	ErrorExpired
+	ErrorNoExchangeRate
+	ErrorRateOutOfRange
+	ErrorRateServiceUnavailable
+)
+
+// Classify returns the kind of an error returned by a service
+func Classify(err error) ErrorKind {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorDeadlineExceeded
+	case errors.Is(err, entity.ErrValidation), errors.Is(err, ErrInvalidReport):
+		return ErrorInvalid
+	case errors.Is(err, repository.ErrTransactionExpired):
+		return ErrorExpired
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		return ErrorNotFound
+	case errors.Is(err, repository.ErrNoExchangeRate):
+		return ErrorNoExchangeRate
+	case errors.Is(err, repository.ErrRateOutOfRange):
+		return ErrorRateOutOfRange
+	case errors.Is(err, ErrRateServiceUnavailable):
+		return ErrorRateServiceUnavailable
+	default:
+		return ErrorInternal
+	}
+}
+
+// RateErrorMessage describes an error of kind ErrorNoExchangeRate or
+// ErrorRateOutOfRange to a client, naming the lookback window the exchange rate
+// repository applied. It returns an empty string for other errors.
+func RateErrorMessage(err error) string {
+	window, span := "the lookback window", "the lookback window"
+	var lookback *repository.LookbackError
+	if errors.As(err, &lookback) && lookback.Months > 0 {
+		window = fmt.Sprintf("%d months", lookback.Months)
+		span = fmt.Sprintf("the %d-month window", lookback.Months)
+		if lookback.Months == 1 {
+			window = "1 month"
+		}
+	}
+
+	switch Classify(err) {
+	case ErrorNoExchangeRate:
+		return fmt.Sprintf("no exchange rate is available within %s before the transaction date for the currency", window)
+	case ErrorRateOutOfRange:
+		return fmt.Sprintf("the available exchange rate is outside %s prior to the transaction date", span)
+	default:
+		return ""
+	}
+}
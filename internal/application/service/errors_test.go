@@ -0,0 +1,53 @@
+// internal/application/service/errors_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorKind
+	}{
+		{rateError(context.Canceled), ErrorCanceled},
+		{fmt.Errorf("failed to get transaction: %w", context.DeadlineExceeded), ErrorDeadlineExceeded},
+		{entity.ErrCategoryTooLong, ErrorInvalid},
+		{(&entity.LegalHold{}).Validate(), ErrorInvalid},
+		{fmt.Errorf("%w: report period starts after it ends", ErrInvalidReport), ErrorInvalid},
+		{fmt.Errorf("failed to retrieve transaction: %w", repository.ErrTransactionExpired), ErrorExpired},
+		{fmt.Errorf("failed to retrieve transaction: %w", repository.ErrTransactionNotFound), ErrorNotFound},
+		{rateError(fmt.Errorf("%w for EUR", repository.ErrNoExchangeRate)), ErrorNoExchangeRate},
+		{rateError(fmt.Errorf("%w: rate date 2022-01-01", repository.ErrRateOutOfRange)), ErrorRateOutOfRange},
+		{rateError(errors.New("connection refused")), ErrorRateServiceUnavailable},
+		{errors.New("failed to store transaction: disk full"), ErrorInternal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Classify(tt.err), tt.err.Error())
+	}
+}
+
+func TestRateErrorMessage(t *testing.T) {
+	lookback := func(months int, err error) error {
+		return rateError(&repository.LookbackError{Months: months, Err: err})
+	}
+
+	assert.Equal(t, "no exchange rate is available within 3 months before the transaction date for the currency",
+		RateErrorMessage(lookback(3, repository.ErrNoExchangeRate)))
+	assert.Equal(t, "no exchange rate is available within 1 month before the transaction date for the currency",
+		RateErrorMessage(lookback(1, repository.ErrNoExchangeRate)))
+	assert.Equal(t, "the available exchange rate is outside the 12-month window prior to the transaction date",
+		RateErrorMessage(lookback(12, repository.ErrRateOutOfRange)))
+
+	// Without a window the message stays neutral
+	assert.Equal(t, "the available exchange rate is outside the lookback window prior to the transaction date",
+		RateErrorMessage(rateError(repository.ErrRateOutOfRange)))
+	assert.Empty(t, RateErrorMessage(errors.New("disk full")))
+}
@@ -0,0 +1,201 @@
+// Package service internal/application/service/export_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExportFilter selects the transactions of an export and the currency they are
+// converted to. Zero values do not filter, and an empty Currency does not convert.
+type ExportFilter struct {
+	// From and To bound the transaction dates, inclusive
+	From     time.Time
+	To       time.Time
+	Currency string
+}
+
+// Matches reports whether tx passes the filter
+func (f ExportFilter) Matches(tx *entity.Transaction) bool {
+	if !f.From.IsZero() && tx.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && tx.Date.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// ExportRow is a transaction of an export with its conversion
+type ExportRow struct {
+	Transaction *entity.Transaction
+	// Converted is the transaction in the filter's currency, nil when the filter
+	// has no currency or the conversion failed
+	Converted *ConvertedTransaction
+	// ConversionErr is why the conversion failed
+	ConversionErr error
+}
+
+// exportChunkSize is how many transactions an export reads at a time. Rates are
+// looked up and rows written between reads, so no read stays open while the
+// Treasury API or the client is slow.
+const exportChunkSize = 500
+
+// errChunkFull stops a chunk's read once it holds exportChunkSize transactions
+var errChunkFull = errors.New("chunk full")
+
+// ExportService exports the tenant's transactions with their conversions
+type ExportService struct {
+	txRepo      repository.TransactionRepository
+	conversions *ConversionService
+	logger      logger.Logger
+}
+
+// NewExportService creates a new export service converting transactions with
+// conversions
+func NewExportService(txRepo repository.TransactionRepository, conversions *ConversionService, log logger.Logger) *ExportService {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &ExportService{
+		txRepo:      txRepo,
+		conversions: conversions,
+		logger:      log,
+	}
+}
+
+// Export calls fn with each of the tenant's transactions that match the filter, in
+// date order when the filter bounds the dates and ID order otherwise. Each distinct
+// rate is looked up once per export. A failed conversion is reported in its row
+// rather than ending the export, which stops at the first error fn returns. The
+// export, rather than each row, is recorded in the audit log once it ends. Export
+// returns the number of rows.
+func (s *ExportService) Export(ctx context.Context, filter ExportFilter, fn func(*ExportRow) error) (int, error) {
+	ctx, span := tracing.Start(ctx, "ExportService.Export",
+		trace.WithAttributes(attribute.String("currency", filter.Currency)))
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	rates := make(map[RateKey]RateResult)
+	rows := 0
+	err := s.readChunks(ctx, filter, func(chunk []*entity.Transaction) error {
+		for _, tx := range chunk {
+			if !filter.Matches(tx) {
+				continue
+			}
+
+			row := &ExportRow{Transaction: tx}
+			if filter.Currency != "" {
+				key := NewRateKey(filter.Currency, tx.Date)
+				rate, ok := rates[key]
+				if !ok {
+					rate = s.conversions.findRate(ctx, key)
+					rates[key] = rate
+				}
+				if rate.Err != nil {
+					row.ConversionErr = rate.Err
+				} else {
+					row.Converted = newConvertedTransaction(tx, filter.Currency, rate.Rate)
+				}
+			}
+
+			rows++
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	span.SetAttributes(attribute.Int("rows", rows))
+	s.record(ctx, filter, rows, err)
+	if err != nil {
+		log.Error("Export failed", map[string]interface{}{
+			"rows":  rows,
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return rows, err
+	}
+
+	log.Info("Transactions exported", map[string]interface{}{
+		"rows":     rows,
+		"currency": filter.Currency,
+		"lookups":  len(rates),
+	})
+	return rows, nil
+}
+
+// readChunks calls fn with the transactions of the filter's date range, or all of
+// the tenant's when it has none, exportChunkSize at a time. Each chunk is read in
+// its own read, which is closed before fn is called.
+func (s *ExportService) readChunks(ctx context.Context, filter ExportFilter, fn func([]*entity.Transaction) error) error {
+	var after *entity.Transaction
+	for {
+		chunk := make([]*entity.Transaction, 0, exportChunkSize)
+		collect := func(tx *entity.Transaction) error {
+			chunk = append(chunk, tx)
+			if len(chunk) == exportChunkSize {
+				return errChunkFull
+			}
+			return nil
+		}
+
+		var err error
+		if !filter.From.IsZero() || !filter.To.IsZero() {
+			err = s.txRepo.ListByDateAfter(ctx, filter.From, filter.To, after, collect)
+		} else {
+			afterID := ""
+			if after != nil {
+				afterID = after.ID
+			}
+			err = s.txRepo.ListAfter(ctx, afterID, collect)
+		}
+		if err != nil && !errors.Is(err, errChunkFull) {
+			return err
+		}
+
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		if len(chunk) < exportChunkSize {
+			return nil
+		}
+		after = chunk[len(chunk)-1]
+	}
+}
+
+// record appends the export to the audit log. The rows have already been served,
+// so a failure is logged rather than returned.
+func (s *ExportService) record(ctx context.Context, filter ExportFilter, rows int, exportErr error) {
+	details := map[string]string{
+		"rows":     strconv.Itoa(rows),
+		"complete": strconv.FormatBool(exportErr == nil),
+	}
+	if filter.Currency != "" {
+		details["currency"] = filter.Currency
+	}
+	if !filter.From.IsZero() {
+		details["from"] = filter.From.Format("2006-01-02")
+	}
+	if !filter.To.IsZero() {
+		details["to"] = filter.To.Format("2006-01-02")
+	}
+
+	if err := s.conversions.audit.Record(ctx, entity.AuditTransactionsExported, "", details); err != nil {
+		logger.ForContext(ctx, s.logger).Error("Failed to record export in the audit log", map[string]interface{}{
+			"rows":  rows,
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// internal/application/service/export_service_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportService(t *testing.T) {
+	jan := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+	txs := []*entity.Transaction{
+		{ID: "a", Date: jan, Amount: 10},
+		{ID: "b", Date: feb, Amount: 20},
+		{ID: "c", Date: feb, Amount: 30},
+		{ID: "d", Date: mar, Amount: 40},
+	}
+
+	newService := func() (*ExportService, *mocks.MockTransactionRepository, *mocks.MockExchangeRateRepository) {
+		log := logger.NewJSONLogger(nil, logger.InfoLevel)
+		txRepo := new(mocks.MockTransactionRepository)
+		rates := new(mocks.MockExchangeRateRepository)
+		txRepo.On("ListAfter", mock.Anything, "", mock.Anything).Return(txs, nil)
+		txRepo.On("ListByDateAfter", mock.Anything, mock.Anything, mock.Anything, (*entity.Transaction)(nil), mock.Anything).
+			Return(txs, nil)
+		return NewExportService(txRepo, NewConversionService(txRepo, rates, log), log), txRepo, rates
+	}
+
+	t.Run("Date range without conversion", func(t *testing.T) {
+		exports, _, rates := newService()
+
+		var ids []string
+		rows, err := exports.Export(context.Background(), ExportFilter{From: feb, To: mar}, func(row *ExportRow) error {
+			assert.Nil(t, row.Converted)
+			assert.NoError(t, row.ConversionErr)
+			ids = append(ids, row.Transaction.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, rows)
+		assert.Equal(t, []string{"b", "c", "d"}, ids)
+		rates.AssertNotCalled(t, "FindRate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Each rate is looked up once", func(t *testing.T) {
+		exports, _, rates := newService()
+		rates.On("FindRate", mock.Anything, "EUR", jan).
+			Return(&entity.ExchangeRate{Currency: "EUR", Date: jan, Rate: 0.9}, nil).Once()
+		rates.On("FindRate", mock.Anything, "EUR", feb).
+			Return(&entity.ExchangeRate{Currency: "EUR", Date: feb, Rate: 0.5}, nil).Once()
+		rates.On("FindRate", mock.Anything, "EUR", mar).
+			Return(nil, fmt.Errorf("%w for EUR", repository.ErrNoExchangeRate)).Once()
+
+		var converted []float64
+		var failed []string
+		rows, err := exports.Export(context.Background(), ExportFilter{Currency: "EUR"}, func(row *ExportRow) error {
+			if row.ConversionErr != nil {
+				failed = append(failed, row.Transaction.ID)
+				assert.ErrorContains(t, row.ConversionErr, "no exchange rate available")
+				return nil
+			}
+			converted = append(converted, row.Converted.ConvertedAmount)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 4, rows)
+		assert.Equal(t, []float64{9, 10, 15}, converted)
+		assert.Equal(t, []string{"d"}, failed)
+		rates.AssertExpectations(t)
+	})
+
+	t.Run("Large exports are read in chunks", func(t *testing.T) {
+		log := logger.NewJSONLogger(nil, logger.InfoLevel)
+		txRepo := new(mocks.MockTransactionRepository)
+		many := make([]*entity.Transaction, exportChunkSize+2)
+		for i := range many {
+			many[i] = &entity.Transaction{ID: fmt.Sprintf("tx-%04d", i), Date: jan, Amount: 1}
+		}
+		txRepo.On("ListAfter", mock.Anything, "", mock.Anything).Return(many, nil).Once()
+		txRepo.On("ListAfter", mock.Anything, many[exportChunkSize-1].ID, mock.Anything).
+			Return(many[exportChunkSize:], nil).Once()
+		exports := NewExportService(txRepo, NewConversionService(txRepo, new(mocks.MockExchangeRateRepository), log), log)
+
+		var ids []string
+		rows, err := exports.Export(context.Background(), ExportFilter{}, func(row *ExportRow) error {
+			ids = append(ids, row.Transaction.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, len(many), rows)
+		assert.Equal(t, many[len(many)-1].ID, ids[len(ids)-1])
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("Writer errors stop the export", func(t *testing.T) {
+		exports, _, _ := newService()
+		rows, err := exports.Export(context.Background(), ExportFilter{}, func(*ExportRow) error {
+			return errors.New("connection reset")
+		})
+		assert.EqualError(t, err, "connection reset")
+		assert.Equal(t, 1, rows)
+	})
+
+	t.Run("The export is recorded once", func(t *testing.T) {
+		log := logger.NewJSONLogger(nil, logger.InfoLevel)
+		txRepo := new(mocks.MockTransactionRepository)
+		rates := new(mocks.MockExchangeRateRepository)
+		auditRepo := new(mocks.MockAuditRepository)
+		txRepo.On("ListByDateAfter", mock.Anything, feb, time.Time{}, (*entity.Transaction)(nil), mock.Anything).
+			Return(txs, nil).Once()
+		rates.On("FindRate", mock.Anything, "EUR", mock.Anything).
+			Return(&entity.ExchangeRate{Currency: "EUR", Date: jan, Rate: 0.9}, nil)
+		auditRepo.On("Append", mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+			return event.Action == entity.AuditTransactionsExported && event.TransactionID == "" &&
+				event.Details["rows"] == "3" && event.Details["currency"] == "EUR" &&
+				event.Details["from"] == "2023-02-15" && event.Details["complete"] == "true"
+		})).Return(nil).Once()
+		exports := NewExportService(txRepo, NewConversionServiceWithAudit(txRepo, rates, NewAuditService(auditRepo, log), log), log)
+
+		rows, err := exports.Export(context.Background(), ExportFilter{From: feb, Currency: "EUR"}, func(row *ExportRow) error {
+			assert.NotNil(t, row.Converted)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, rows)
+		auditRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,430 @@
+// Package service internal/application/service/report_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Report groupings
+const (
+	GroupByMonth    = "month"
+	GroupByCategory = "category"
+)
+
+// MaxReportMonths is the longest period a summary report covers
+const MaxReportMonths = 120
+
+// ReportFilter selects the transactions a summary report covers and how they are
+// grouped
+type ReportFilter struct {
+	// From and To are the first and last months covered. A zero To is the current
+	// month; a zero From is the eleventh month before To.
+	From, To time.Time
+	// GroupBy is GroupByMonth or GroupByCategory
+	GroupBy string
+	// Currency, if set, adds summaries of the amounts converted to it, each
+	// transaction at the rate applicable on its date
+	Currency string
+}
+
+// Summary describes a set of amounts
+type Summary struct {
+	Count   int
+	Sum     float64
+	Min     float64
+	Max     float64
+	Average float64
+}
+
+// ReportGroup summarises the transactions of a month or category
+type ReportGroup struct {
+	// Key is the month, as YYYY-MM, or the category, which is empty for the
+	// transactions without one
+	Key string
+	USD Summary
+	// Converted summarises the transactions that could be converted, and
+	// Unconverted counts those that could not; both are only set when converting
+	Converted   *Summary
+	Unconverted int
+}
+
+// SummaryReport is the result of a summary report
+type SummaryReport struct {
+	GroupBy  string
+	Currency string
+	From, To time.Time
+	Groups   []ReportGroup
+}
+
+// ReportConfig controls the caching of a ReportService
+type ReportConfig struct {
+	// CacheTTL is the longest a month's summary is cached; zero disables caching
+	CacheTTL time.Duration
+	// CacheSize is the most month summaries cached at once
+	CacheSize int
+}
+
+// ReportService summarises transactions by month or category. Each month is read
+// through the date index and its summary cached, per tenant and currency, until a
+// transaction of the month is created or has its legal hold changed, or until the
+// first of its transactions expires.
+type ReportService struct {
+	txRepo      repository.TransactionRepository
+	conversions *ConversionService
+	config      ReportConfig
+	logger      logger.Logger
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[monthKey]map[string]*monthSummary
+	// size is the number of summaries in cache
+	size int
+	// generation counts invalidations, so a summary read while a transaction
+	// changed is not cached
+	generation uint64
+}
+
+// monthKey identifies a month of a tenant's transactions
+type monthKey struct {
+	tenantID string
+	month    string
+}
+
+// monthSummary is the cached summary of a month, by category
+type monthSummary struct {
+	categories map[string]*groupTotals
+	expiresAt  time.Time
+}
+
+// groupTotals accumulates the amounts of a group
+type groupTotals struct {
+	usd         totals
+	converted   totals
+	unconverted int
+}
+
+// totals accumulates a set of amounts
+type totals struct {
+	count    int
+	sum      float64
+	min, max float64
+}
+
+// NewReportService creates a new report service
+func NewReportService(txRepo repository.TransactionRepository, conversions *ConversionService, config ReportConfig, log logger.Logger) *ReportService {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &ReportService{
+		txRepo:      txRepo,
+		conversions: conversions,
+		config:      config,
+		logger:      log,
+		now:         time.Now,
+		cache:       make(map[monthKey]map[string]*monthSummary),
+	}
+}
+
+// Summary reports the count, sum, minimum, maximum and average amount of each
+// group of the tenant's transactions dated in the filter's months. Groups without
+// transactions are left out.
+func (s *ReportService) Summary(ctx context.Context, filter ReportFilter) (*SummaryReport, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.Summary",
+		trace.WithAttributes(attribute.String("group_by", filter.GroupBy), attribute.String("currency", filter.Currency)))
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	if filter.GroupBy != GroupByMonth && filter.GroupBy != GroupByCategory {
+		err := fmt.Errorf("%w: unknown grouping %q", ErrInvalidReport, filter.GroupBy)
+		tracing.SetError(span, err)
+		return nil, err
+	}
+	from, to := reportMonths(filter.From, filter.To, s.now())
+	if from.After(to) {
+		err := fmt.Errorf("%w: report period starts after it ends", ErrInvalidReport)
+		tracing.SetError(span, err)
+		return nil, err
+	}
+	if months := monthsBetween(from, to) + 1; months > MaxReportMonths {
+		err := fmt.Errorf("%w: report period of %d months exceeds %d months", ErrInvalidReport, months, MaxReportMonths)
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	tenantID := tenant.IDFromContext(ctx)
+	rates := make(map[RateKey]RateResult)
+	report := &SummaryReport{GroupBy: filter.GroupBy, Currency: filter.Currency, From: from, To: to}
+	byCategory := make(map[string]*groupTotals)
+	cached := 0
+
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		summary, hit, err := s.month(ctx, monthKey{tenantID: tenantID, month: month.Format("2006-01")}, month, filter.Currency, rates)
+		if err != nil {
+			log.Error("Failed to summarise transactions", map[string]interface{}{
+				"month": month.Format("2006-01"),
+				"error": err.Error(),
+			})
+			tracing.SetError(span, err)
+			return nil, err
+		}
+		if hit {
+			cached++
+		}
+
+		if filter.GroupBy == GroupByCategory {
+			for category, group := range summary.categories {
+				if byCategory[category] == nil {
+					byCategory[category] = &groupTotals{}
+				}
+				byCategory[category].merge(group)
+			}
+			continue
+		}
+
+		var monthTotals groupTotals
+		for _, group := range summary.categories {
+			monthTotals.merge(group)
+		}
+		if monthTotals.usd.count > 0 {
+			report.Groups = append(report.Groups, monthTotals.group(month.Format("2006-01"), filter.Currency))
+		}
+	}
+
+	if filter.GroupBy == GroupByCategory {
+		for category, group := range byCategory {
+			report.Groups = append(report.Groups, group.group(category, filter.Currency))
+		}
+		sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].Key < report.Groups[j].Key })
+	}
+
+	log.Info("Summary report built", map[string]interface{}{
+		"group_by":      filter.GroupBy,
+		"currency":      filter.Currency,
+		"from":          from.Format("2006-01"),
+		"to":            to.Format("2006-01"),
+		"groups":        len(report.Groups),
+		"cached_months": cached,
+	})
+
+	return report, nil
+}
+
+// Invalidate drops the cached summaries of the month of tx, which has changed
+func (s *ReportService) Invalidate(ctx context.Context, tx *entity.Transaction) {
+	if s == nil {
+		return
+	}
+
+	key := monthKey{tenantID: tenant.IDFromContext(ctx), month: tx.Date.Format("2006-01")}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	s.size -= len(s.cache[key])
+	delete(s.cache, key)
+}
+
+// month returns the summary of the transactions of a month, from the cache if it
+// holds one, and whether it did. Rates looked up are added to rates.
+func (s *ReportService) month(ctx context.Context, key monthKey, start time.Time, currency string, rates map[RateKey]RateResult) (*monthSummary, bool, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	summary := s.cache[key][currency]
+	generation := s.generation
+	s.mu.Unlock()
+	if summary != nil && now.Before(summary.expiresAt) {
+		return summary, true, nil
+	}
+
+	var txs []*entity.Transaction
+	err := s.txRepo.ListByDate(ctx, start, start.AddDate(0, 1, -1), func(tx *entity.Transaction) error {
+		txs = append(txs, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if currency != "" {
+		var missing []RateKey
+		for _, tx := range txs {
+			rateKey := NewRateKey(currency, tx.Date)
+			if _, ok := rates[rateKey]; !ok {
+				missing = append(missing, rateKey)
+			}
+		}
+		for rateKey, result := range s.conversions.FindRates(ctx, missing) {
+			rates[rateKey] = result
+		}
+	}
+
+	summary = &monthSummary{
+		categories: make(map[string]*groupTotals),
+		expiresAt:  now.Add(s.config.CacheTTL),
+	}
+	cacheable := s.config.CacheTTL > 0
+	for _, tx := range txs {
+		group := summary.categories[tx.Category]
+		if group == nil {
+			group = &groupTotals{}
+			summary.categories[tx.Category] = group
+		}
+		group.usd.add(tx.Amount)
+
+		if expiresAt := tx.ExpiresAt(); !tx.Held() && !expiresAt.IsZero() && expiresAt.Before(summary.expiresAt) {
+			summary.expiresAt = expiresAt
+		}
+
+		if currency == "" {
+			continue
+		}
+		rate := rates[NewRateKey(currency, tx.Date)]
+		if rate.Err != nil {
+			// Rates may yet be published, or the rate service recover
+			group.unconverted++
+			cacheable = false
+			continue
+		}
+		group.converted.add(convertAmount(tx.Amount, rate.Rate.Rate))
+	}
+
+	if cacheable {
+		s.store(key, currency, summary, generation)
+	}
+	return summary, false, nil
+}
+
+// store caches the summary of a month read at generation, unless a transaction
+// has changed since. A full cache first drops the summaries that have expired,
+// then any other.
+func (s *ReportService) store(key monthKey, currency string, summary *monthSummary, generation uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generation != generation || s.config.CacheSize <= 0 {
+		return
+	}
+	if s.size >= s.config.CacheSize {
+		now := s.now()
+		for k, summaries := range s.cache {
+			for c, cached := range summaries {
+				if !now.Before(cached.expiresAt) {
+					delete(summaries, c)
+					s.size--
+				}
+			}
+			if len(summaries) == 0 {
+				delete(s.cache, k)
+			}
+		}
+	}
+	if s.size >= s.config.CacheSize {
+		for k, summaries := range s.cache {
+			s.size -= len(summaries)
+			delete(s.cache, k)
+			break
+		}
+	}
+
+	if s.cache[key] == nil {
+		s.cache[key] = make(map[string]*monthSummary)
+	}
+	if s.cache[key][currency] == nil {
+		s.size++
+	}
+	s.cache[key][currency] = summary
+}
+
+// reportMonths returns the first days of the first and last months of a report
+// period, filling in the defaults of a zero from or to
+func reportMonths(from, to, now time.Time) (time.Time, time.Time) {
+	if to.IsZero() {
+		to = now.UTC()
+	}
+	to = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if from.IsZero() {
+		return to.AddDate(0, -11, 0), to
+	}
+	return time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC), to
+}
+
+// monthsBetween returns the number of months from the month of from to the month
+// of to
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+}
+
+// add adds an amount to the totals
+func (t *totals) add(amount float64) {
+	if t.count == 0 || amount < t.min {
+		t.min = amount
+	}
+	if t.count == 0 || amount > t.max {
+		t.max = amount
+	}
+	t.count++
+	t.sum += amount
+}
+
+// merge adds the amounts of other to the totals
+func (t *totals) merge(other totals) {
+	if other.count == 0 {
+		return
+	}
+	if t.count == 0 || other.min < t.min {
+		t.min = other.min
+	}
+	if t.count == 0 || other.max > t.max {
+		t.max = other.max
+	}
+	t.count += other.count
+	t.sum += other.sum
+}
+
+// summary describes the totals, rounding the sum and average to the cent
+func (t totals) summary() Summary {
+	if t.count == 0 {
+		return Summary{}
+	}
+	return Summary{
+		Count:   t.count,
+		Sum:     math.Round(t.sum*100) / 100,
+		Min:     t.min,
+		Max:     t.max,
+		Average: math.Round(t.sum/float64(t.count)*100) / 100,
+	}
+}
+
+// merge adds the amounts of other to the group
+func (g *groupTotals) merge(other *groupTotals) {
+	g.usd.merge(other.usd)
+	g.converted.merge(other.converted)
+	g.unconverted += other.unconverted
+}
+
+// group describes the group, with its converted amounts when converting to
+// currency
+func (g *groupTotals) group(key, currency string) ReportGroup {
+	group := ReportGroup{Key: key, USD: g.usd.summary()}
+	if currency != "" {
+		converted := g.converted.summary()
+		group.Converted = &converted
+		group.Unconverted = g.unconverted
+	}
+	return group
+}
@@ -0,0 +1,168 @@
+// internal/application/service/report_service_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportService(t *testing.T) {
+	now := time.Date(2023, 4, 10, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2023, month, d, 0, 0, 0, 0, time.UTC) }
+	months := map[time.Month][]*entity.Transaction{
+		time.January: {
+			{ID: "a", Date: day(1, 5), Amount: 10, Category: "fuel"},
+			{ID: "b", Date: day(1, 20), Amount: 30.01},
+		},
+		time.February: {},
+		time.March: {
+			{ID: "c", Date: day(3, 1), Amount: 5, Category: "fuel"},
+			{ID: "d", Date: day(3, 1), Amount: 7.5, Category: "tolls", TTL: now.Add(10 * time.Minute).Unix()},
+		},
+		time.April: {},
+	}
+
+	newService := func() (*ReportService, *mocks.MockTransactionRepository, *mocks.MockExchangeRateRepository) {
+		log := logger.NewJSONLogger(nil, logger.InfoLevel)
+		txRepo := new(mocks.MockTransactionRepository)
+		rates := new(mocks.MockExchangeRateRepository)
+		for month, txs := range months {
+			txRepo.On("ListByDate", mock.Anything, day(month, 1), day(month+1, 0), mock.Anything).Return(txs, nil)
+		}
+		reports := NewReportService(txRepo, NewConversionService(txRepo, rates, log), ReportConfig{CacheTTL: time.Hour, CacheSize: 100}, log)
+		reports.now = func() time.Time { return now }
+		return reports, txRepo, rates
+	}
+	q1 := ReportFilter{From: day(1, 1), To: day(3, 1)}
+
+	t.Run("Grouped by month", func(t *testing.T) {
+		reports, _, _ := newService()
+		filter := q1
+		filter.GroupBy = GroupByMonth
+
+		report, err := reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, day(1, 1), report.From)
+		assert.Equal(t, day(3, 1), report.To)
+		require.Len(t, report.Groups, 2, "months without transactions are left out")
+		assert.Equal(t, ReportGroup{Key: "2023-01", USD: Summary{Count: 2, Sum: 40.01, Min: 10, Max: 30.01, Average: 20.01}}, report.Groups[0])
+		assert.Equal(t, ReportGroup{Key: "2023-03", USD: Summary{Count: 2, Sum: 12.5, Min: 5, Max: 7.5, Average: 6.25}}, report.Groups[1])
+	})
+
+	t.Run("Grouped by category with conversion", func(t *testing.T) {
+		reports, _, rates := newService()
+		rates.On("FindRate", mock.Anything, "EUR", day(1, 5)).
+			Return(&entity.ExchangeRate{Currency: "EUR", Date: day(1, 1), Rate: 0.5}, nil).Once()
+		rates.On("FindRate", mock.Anything, "EUR", day(1, 20)).
+			Return(&entity.ExchangeRate{Currency: "EUR", Date: day(1, 1), Rate: 0.5}, nil).Once()
+		// Both March transactions share a date, so its rate is looked up once
+		rates.On("FindRate", mock.Anything, "EUR", day(3, 1)).
+			Return(nil, fmt.Errorf("%w for EUR", repository.ErrNoExchangeRate)).Once()
+		filter := q1
+		filter.GroupBy = GroupByCategory
+		filter.Currency = "EUR"
+
+		report, err := reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+		rates.AssertExpectations(t)
+
+		require.Len(t, report.Groups, 3)
+		uncategorized, fuel, tolls := report.Groups[0], report.Groups[1], report.Groups[2]
+		assert.Equal(t, "fuel", fuel.Key)
+		assert.Equal(t, Summary{Count: 2, Sum: 15, Min: 5, Max: 10, Average: 7.5}, fuel.USD)
+		assert.Equal(t, &Summary{Count: 1, Sum: 5, Min: 5, Max: 5, Average: 5}, fuel.Converted)
+		assert.Equal(t, 1, fuel.Unconverted)
+		assert.Equal(t, "tolls", tolls.Key)
+		assert.Equal(t, &Summary{}, tolls.Converted)
+		assert.Equal(t, 1, tolls.Unconverted)
+		assert.Empty(t, uncategorized.Key)
+		assert.Equal(t, &Summary{Count: 1, Sum: 15.01, Min: 15.01, Max: 15.01, Average: 15.01}, uncategorized.Converted)
+	})
+
+	t.Run("Months are cached until a transaction changes", func(t *testing.T) {
+		reports, txRepo, _ := newService()
+		filter := q1
+		filter.GroupBy = GroupByMonth
+
+		_, err := reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+		txRepo.AssertNumberOfCalls(t, "ListByDate", 3)
+
+		// The same months grouped differently come from the cache
+		filter.GroupBy = GroupByCategory
+		_, err = reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+		txRepo.AssertNumberOfCalls(t, "ListByDate", 3)
+
+		reports.Invalidate(context.Background(), &entity.Transaction{Date: day(1, 31)})
+		_, err = reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+		txRepo.AssertNumberOfCalls(t, "ListByDate", 4)
+	})
+
+	t.Run("Months are cached until a transaction expires", func(t *testing.T) {
+		reports, txRepo, _ := newService()
+		filter := q1
+		filter.GroupBy = GroupByMonth
+
+		_, err := reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+
+		// March holds a transaction expiring before the cache entry would
+		now = now.Add(15 * time.Minute)
+		defer func() { now = now.Add(-15 * time.Minute) }()
+		report, err := reports.Summary(context.Background(), filter)
+		require.NoError(t, err)
+		txRepo.AssertNumberOfCalls(t, "ListByDate", 4)
+		assert.Len(t, report.Groups, 2)
+	})
+
+	t.Run("Failed conversions are not cached", func(t *testing.T) {
+		reports, txRepo, rates := newService()
+		rates.On("FindRate", mock.Anything, "EUR", mock.Anything).
+			Return(nil, errors.New("failed to execute request"))
+		filter := ReportFilter{From: day(1, 1), To: day(1, 1), GroupBy: GroupByMonth, Currency: "EUR"}
+
+		for i := 0; i < 2; i++ {
+			report, err := reports.Summary(context.Background(), filter)
+			require.NoError(t, err)
+			require.Len(t, report.Groups, 1)
+			assert.Equal(t, 2, report.Groups[0].Unconverted)
+		}
+		txRepo.AssertNumberOfCalls(t, "ListByDate", 2)
+	})
+
+	t.Run("The period defaults to the last twelve months", func(t *testing.T) {
+		reports, _, _ := newService()
+		report, err := reports.Summary(context.Background(), ReportFilter{GroupBy: GroupByMonth, From: day(1, 1)})
+		require.NoError(t, err)
+		assert.Equal(t, day(4, 1), report.To)
+
+		from, to := reportMonths(time.Time{}, time.Time{}, now)
+		assert.Equal(t, time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, day(4, 1), to)
+	})
+
+	t.Run("Invalid filters", func(t *testing.T) {
+		reports, _, _ := newService()
+		for _, filter := range []ReportFilter{
+			{GroupBy: "week"},
+			{GroupBy: GroupByMonth, From: day(3, 1), To: day(1, 1)},
+			{GroupBy: GroupByMonth, From: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		} {
+			_, err := reports.Summary(context.Background(), filter)
+			assert.Error(t, err, filter)
+		}
+	})
+}
@@ -2,41 +2,98 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
-	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
 	"github.com/google/uuid"
 )
 
+// RetentionPolicy returns how long a transaction created in ctx is kept. A zero
+// duration uses entity.DefaultRetention.
+type RetentionPolicy func(ctx context.Context) time.Duration
+
+// TransactionServiceConfig holds the optional collaborators of a TransactionService
+type TransactionServiceConfig struct {
+	// Retention chooses the retention period of each transaction; nil keeps every
+	// transaction for entity.DefaultRetention
+	Retention RetentionPolicy
+	// Audit records creations and legal holds; nil records nothing
+	Audit *AuditService
+	// PublishEvents adds an event to the outbox with each new transaction and each
+	// legal hold change, for the relay to deliver
+	PublishEvents bool
+	// Reports is told of each new transaction and each legal hold change, so the
+	// cached summaries covering it are recomputed; nil tells nothing
+	Reports *ReportService
+}
+
 // TransactionService handles business logic for transactions
 type TransactionService struct {
-	repo   repository.TransactionRepository
-	logger logger.Logger
+	repo      repository.TransactionRepository
+	retention RetentionPolicy
+	audit     *AuditService
+	publish   bool
+	reports   *ReportService
+	logger    logger.Logger
 }
 
-// NewTransactionService creates a new transaction service
+// NewTransactionService creates a new transaction service that keeps transactions
+// for entity.DefaultRetention
 func NewTransactionService(repo repository.TransactionRepository, log logger.Logger) *TransactionService {
+	return NewTransactionServiceWithConfig(repo, TransactionServiceConfig{}, log)
+}
+
+// NewTransactionServiceWithRetention creates a new transaction service whose
+// retention period is chosen per transaction by retention
+func NewTransactionServiceWithRetention(repo repository.TransactionRepository, retention RetentionPolicy, log logger.Logger) *TransactionService {
+	return NewTransactionServiceWithConfig(repo, TransactionServiceConfig{Retention: retention}, log)
+}
+
+// NewTransactionServiceWithConfig creates a new transaction service with the given
+// collaborators
+func NewTransactionServiceWithConfig(repo repository.TransactionRepository, cfg TransactionServiceConfig, log logger.Logger) *TransactionService {
 	if log == nil {
 		log = logger.GetDefaultLogger()
 	}
+	retention := cfg.Retention
+	if retention == nil {
+		retention = func(context.Context) time.Duration { return entity.DefaultRetention }
+	}
 
 	return &TransactionService{
-		repo:   repo,
-		logger: log,
+		repo:      repo,
+		retention: retention,
+		audit:     cfg.Audit,
+		publish:   cfg.PublishEvents,
+		reports:   cfg.Reports,
+		logger:    log,
 	}
 }
 
-// CreateTransaction creates and stores a new transaction
+// CreateTransaction creates and stores a new uncategorized transaction
 func (s *TransactionService) CreateTransaction(ctx context.Context, desc string, date time.Time, amount float64) (string, error) {
-	requestID := middleware.GetRequestID(ctx)
+	return s.CreateCategorizedTransaction(ctx, desc, "", date, amount)
+}
+
+// CreateCategorizedTransaction creates and stores a new transaction in category,
+// which may be empty
+func (s *TransactionService) CreateCategorizedTransaction(ctx context.Context, desc, category string, date time.Time, amount float64) (string, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.CreateTransaction")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
 
-	s.logger.Info("Creating new transaction", map[string]interface{}{
-		"request_id":  requestID,
+	log.Info("Creating new transaction", map[string]interface{}{
 		"description": desc,
+		"category":    category,
 		"date":        date.Format("2006-01-02"),
 		"amount":      amount,
 	})
@@ -50,64 +107,274 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, desc string,
 	tx := &entity.Transaction{
 		ID:          uuid.New().String(),
 		Description: desc,
+		Category:    strings.TrimSpace(category),
 		Date:        date,
 		Amount:      amount,
 		CreatedAt:   now,
 	}
 
 	// Calculate TTL for data retention
-	tx.CalculateTTL()
+	tx.CalculateTTL(s.retention(ctx))
 
 	// Validate
 	if err := tx.Validate(); err != nil {
-		s.logger.Error("Transaction validation failed", map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
+		log.Error("Transaction validation failed", map[string]interface{}{
+			"error": err.Error(),
 		})
+		tracing.SetError(span, err)
 		return "", err
 	}
 
-	// Store in repository
-	id, err := s.repo.Store(ctx, tx)
+	// The description is left out: audit events outlive the transaction's retention
+	details := map[string]string{
+		"date":       tx.Date.Format("2006-01-02"),
+		"amount":     strconv.FormatFloat(tx.Amount, 'f', 2, 64),
+		"expires_at": tx.ExpiresAt().Format(time.RFC3339),
+	}
+	if tx.Category != "" {
+		details["category"] = tx.Category
+	}
+
+	// Store in repository, with the evidence of its creation
+	id, err := s.store(ctx, tx, s.audit.Event(ctx, entity.AuditTransactionCreated, tx.ID, details))
 	if err != nil {
-		s.logger.Error("Failed to store transaction", map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
+		log.Error("Failed to store transaction", map[string]interface{}{
+			"error": err.Error(),
 		})
+		tracing.SetError(span, err)
 		return "", err
 	}
 
-	s.logger.Info("Transaction created successfully", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	s.reports.Invalidate(ctx, tx)
+
+	log.Info("Transaction created successfully", map[string]interface{}{
+		"id": id,
 	})
 
 	return id, nil
 }
 
+// store saves a new transaction, with its TransactionCreated event when events are
+// published and its audit event when one is recorded
+func (s *TransactionService) store(ctx context.Context, tx *entity.Transaction, audit *entity.AuditEvent) (string, error) {
+	if !s.publish && audit == nil {
+		return s.repo.Store(ctx, tx)
+	}
+
+	var events []*entity.DomainEvent
+	if s.publish {
+		event, err := entity.NewTransactionCreatedEvent(uuid.New().String(), tx)
+		if err != nil {
+			return "", fmt.Errorf("failed to create event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return s.repo.StoreWithEvents(ctx, tx, events, audit)
+}
+
+// updatedEvent returns the function building the TransactionUpdated event for a
+// change, or nil when events are not published
+func (s *TransactionService) updatedEvent(change string) repository.EventFunc {
+	if !s.publish {
+		return nil
+	}
+	return func(tx *entity.Transaction) (*entity.DomainEvent, error) {
+		return entity.NewTransactionUpdatedEvent(uuid.New().String(), tx, change, time.Now().UTC())
+	}
+}
+
 // GetTransaction retrieves a transaction by ID
 func (s *TransactionService) GetTransaction(ctx context.Context, id string) (*entity.Transaction, error) {
-	requestID := middleware.GetRequestID(ctx)
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransaction")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
 
-	s.logger.Info("Retrieving transaction", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log.Info("Retrieving transaction", map[string]interface{}{
+		"id": id,
 	})
 
 	tx, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		s.logger.Error("Failed to retrieve transaction", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
-			"error":      err.Error(),
+		log.Error("Failed to retrieve transaction", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	log.Info("Transaction retrieved successfully", map[string]interface{}{
+		"id": id,
+	})
+
+	return tx, nil
+}
+
+// ListTransactions calls fn for each of the tenant's transactions
+func (s *TransactionService) ListTransactions(ctx context.Context, fn func(*entity.Transaction) error) error {
+	ctx, span := tracing.Start(ctx, "TransactionService.ListTransactions")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	count := 0
+	err := s.repo.List(ctx, func(tx *entity.Transaction) error {
+		count++
+		return fn(tx)
+	})
+	if err != nil {
+		log.Error("Failed to list transactions", map[string]interface{}{
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return err
+	}
+
+	log.Info("Transactions listed", map[string]interface{}{
+		"count": count,
+	})
+
+	return nil
+}
+
+// errPageFull stops a page's scan once it has read past the page
+var errPageFull = errors.New("page full")
+
+// ListTransactionPage returns up to limit of the tenant's transactions whose IDs
+// follow after, in ID order, and the ID to pass as after for the next page, which
+// is empty on the last page. The scan starts at after and stops one transaction
+// past the page.
+func (s *TransactionService) ListTransactionPage(ctx context.Context, after string, limit int) ([]*entity.Transaction, string, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.ListTransactionPage")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	// Read the transaction after the page too, to know whether there is a next page
+	page := make([]*entity.Transaction, 0, limit+1)
+	err := s.repo.ListAfter(ctx, after, func(tx *entity.Transaction) error {
+		page = append(page, tx)
+		if len(page) > limit {
+			return errPageFull
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPageFull) {
+		log.Error("Failed to list transactions", map[string]interface{}{
+			"after": after,
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return nil, "", err
+	}
+
+	if len(page) > limit {
+		page = page[:limit]
+		return page, page[limit-1].ID, nil
+	}
+	return page, "", nil
+}
+
+// PlaceLegalHold puts a transaction under legal hold on behalf of actor, suspending
+// its expiry until the hold is released
+func (s *TransactionService) PlaceLegalHold(ctx context.Context, id, reason, actor string) (*entity.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.PlaceLegalHold")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	hold := entity.LegalHold{
+		Reason:   strings.TrimSpace(reason),
+		PlacedBy: actor,
+		PlacedAt: time.Now().UTC(),
+	}
+	if err := hold.Validate(); err != nil {
+		log.Warn("Legal hold validation failed", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	// The audit event is stored with the hold, so a hold is never placed unrecorded
+	audit := s.audit.Event(ctx, entity.AuditLegalHoldPlaced, id, map[string]string{
+		"reason": hold.Reason,
+	})
+	tx, err := s.repo.PlaceLegalHold(ctx, id, hold, s.updatedEvent(entity.ChangeLegalHoldPlaced), audit)
+	if err != nil {
+		log.Error("Failed to place legal hold", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return nil, err
+	}
+	s.reports.Invalidate(ctx, tx)
+
+	log.Info("Legal hold placed", map[string]interface{}{
+		"id":        id,
+		"placed_by": actor,
+		"reason":    hold.Reason,
+	})
+
+	return tx, nil
+}
+
+// ReleaseLegalHold lifts a transaction's legal hold on behalf of actor, so that it
+// expires at the end of its retention period again
+func (s *TransactionService) ReleaseLegalHold(ctx context.Context, id, actor string) (*entity.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.ReleaseLegalHold")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	audit := s.audit.Event(ctx, entity.AuditLegalHoldReleased, id, map[string]string{
+		"released_by": actor,
+	})
+	tx, err := s.repo.ReleaseLegalHold(ctx, id, s.updatedEvent(entity.ChangeLegalHoldReleased), audit)
+	if err != nil {
+		log.Error("Failed to release legal hold", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
 		})
+		tracing.SetError(span, err)
 		return nil, err
 	}
+	s.reports.Invalidate(ctx, tx)
 
-	s.logger.Info("Transaction retrieved successfully", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log.Info("Legal hold released", map[string]interface{}{
+		"id":          id,
+		"released_by": actor,
 	})
 
 	return tx, nil
 }
+
+// ListLegalHolds calls fn for each of the tenant's transactions under legal hold
+func (s *TransactionService) ListLegalHolds(ctx context.Context, fn func(*entity.Transaction) error) error {
+	ctx, span := tracing.Start(ctx, "TransactionService.ListLegalHolds")
+	defer span.End()
+
+	log := logger.ForContext(ctx, s.logger)
+
+	count := 0
+	err := s.repo.ListLegalHolds(ctx, func(tx *entity.Transaction) error {
+		count++
+		return fn(tx)
+	})
+	if err != nil {
+		log.Error("Failed to list legal holds", map[string]interface{}{
+			"error": err.Error(),
+		})
+		tracing.SetError(span, err)
+		return err
+	}
+
+	log.Info("Legal holds listed", map[string]interface{}{
+		"count": count,
+	})
+
+	return nil
+}
@@ -3,11 +3,13 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
 	"github.com/stretchr/testify/assert"
@@ -27,7 +29,7 @@ func TestCreateTransaction(t *testing.T) {
 		amount := 123.45
 
 		// Mock expectations
-		repo.On("Store", ctx, mock.MatchedBy(func(tx *entity.Transaction) bool {
+		repo.On("Store", mock.Anything, mock.MatchedBy(func(tx *entity.Transaction) bool {
 			return tx.Description == desc && tx.Date == date && tx.Amount == amount
 		})).Return("test-id", nil).Once()
 
@@ -77,7 +79,7 @@ func TestCreateTransaction(t *testing.T) {
 		amount := 123.45
 
 		// Mock expectations
-		repo.On("Store", ctx, mock.Anything).Return("", errors.New("repository error")).Once()
+		repo.On("Store", mock.Anything, mock.Anything).Return("", errors.New("repository error")).Once()
 
 		// Execute
 		id, err := service.CreateTransaction(ctx, desc, date, amount)
@@ -89,3 +91,220 @@ func TestCreateTransaction(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 }
+
+func TestCreateTransactionRetention(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	retention := func(ctx context.Context) time.Duration { return 30 * 24 * time.Hour }
+	service := NewTransactionServiceWithRetention(repo, retention, log)
+
+	var stored *entity.Transaction
+	repo.On("Store", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*entity.Transaction)
+	}).Return("test-id", nil).Once()
+
+	_, err := service.CreateTransaction(context.Background(), "Fuel", time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, stored.CreatedAt.Add(30*24*time.Hour).Unix(), stored.TTL)
+}
+
+func TestListTransactionPage(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	service := NewTransactionService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+	ctx := context.Background()
+	txs := []*entity.Transaction{{ID: "b"}, {ID: "c"}, {ID: "d"}}
+
+	t.Run("Stops one transaction past the page", func(t *testing.T) {
+		repo.On("ListAfter", mock.Anything, "a", mock.Anything).Return(txs, nil).Once()
+
+		page, next, err := service.ListTransactionPage(ctx, "a", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, txs[:2], page)
+		assert.Equal(t, "c", next)
+	})
+
+	t.Run("Last page has no next ID", func(t *testing.T) {
+		repo.On("ListAfter", mock.Anything, "b", mock.Anything).Return(txs[1:], nil).Once()
+
+		page, next, err := service.ListTransactionPage(ctx, "b", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, txs[1:], page)
+		assert.Empty(t, next)
+	})
+
+	t.Run("Repository errors are returned", func(t *testing.T) {
+		repo.On("ListAfter", mock.Anything, "", mock.Anything).Return(nil, errors.New("repository error")).Once()
+
+		_, _, err := service.ListTransactionPage(ctx, "", 2)
+		assert.EqualError(t, err, "repository error")
+	})
+
+	repo.AssertExpectations(t)
+}
+
+func TestPlaceLegalHold(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	service := NewTransactionService(repo, log)
+	ctx := context.Background()
+
+	t.Run("Records reason, actor and time", func(t *testing.T) {
+		held := &entity.Transaction{ID: "test-id"}
+		repo.On("PlaceLegalHold", mock.Anything, "test-id", mock.MatchedBy(func(hold entity.LegalHold) bool {
+			return hold.Reason == "Chargeback dispute" && hold.PlacedBy == "auditor" &&
+				time.Since(hold.PlacedAt) < time.Minute
+		}), mock.Anything, (*entity.AuditEvent)(nil)).Return(held, nil).Once()
+
+		tx, err := service.PlaceLegalHold(ctx, "test-id", "  Chargeback dispute ", "auditor")
+		assert.NoError(t, err)
+		assert.Same(t, held, tx)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Reason is required", func(t *testing.T) {
+		_, err := service.PlaceLegalHold(ctx, "test-id", " ", "auditor")
+		assert.EqualError(t, err, "legal hold reason is required")
+	})
+
+	t.Run("Actor is required", func(t *testing.T) {
+		_, err := service.PlaceLegalHold(ctx, "test-id", "Audit", "")
+		assert.EqualError(t, err, "legal hold actor is required")
+	})
+}
+
+func TestCreateTransactionAudit(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	auditRepo := new(mocks.MockAuditRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	service := NewTransactionServiceWithConfig(repo, TransactionServiceConfig{
+		Audit: NewAuditService(auditRepo, log),
+	}, log)
+	ctx := context.Background()
+
+	t.Run("Creation is recorded with the transaction", func(t *testing.T) {
+		var stored *entity.Transaction
+		repo.On("StoreWithEvents", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+			_, hasDescription := event.Details["description"]
+			return event.Action == entity.AuditTransactionCreated && !hasDescription &&
+				event.Details["amount"] == "12.50" && event.Details["date"] == "2023-04-15"
+		})).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*entity.Transaction)
+			assert.Empty(t, args.Get(2), "no events are published")
+			assert.Equal(t, stored.ID, args.Get(3).(*entity.AuditEvent).TransactionID)
+		}).Return("test-id", nil).Once()
+
+		_, err := service.CreateTransaction(ctx, "Fuel", time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC), 12.5)
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		auditRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure stores neither", func(t *testing.T) {
+		repo.On("StoreWithEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return("", errors.New("store error")).Once()
+
+		_, err := service.CreateTransaction(ctx, "Fuel", time.Now(), 12.5)
+		assert.EqualError(t, err, "store error")
+	})
+}
+
+func TestLegalHoldAudit(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	auditRepo := new(mocks.MockAuditRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	service := NewTransactionServiceWithConfig(repo, TransactionServiceConfig{
+		Audit: NewAuditService(auditRepo, log),
+	}, log)
+	ctx := context.Background()
+	held := &entity.Transaction{ID: "test-id"}
+
+	t.Run("Changes are recorded with the hold", func(t *testing.T) {
+		repo.On("PlaceLegalHold", mock.Anything, "test-id", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+			return event.Action == entity.AuditLegalHoldPlaced && event.TransactionID == "test-id" &&
+				event.Details["reason"] == "Audit"
+		})).Return(held, nil).Once()
+		repo.On("ReleaseLegalHold", mock.Anything, "test-id", mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+			return event.Action == entity.AuditLegalHoldReleased && event.TransactionID == "test-id" &&
+				event.Details["released_by"] == "auditor"
+		})).Return(held, nil).Once()
+
+		_, err := service.PlaceLegalHold(ctx, "test-id", "Audit", "auditor")
+		assert.NoError(t, err)
+		_, err = service.ReleaseLegalHold(ctx, "test-id", "auditor")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		auditRepo.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure stores neither", func(t *testing.T) {
+		repo.On("ReleaseLegalHold", mock.Anything, "test-id", mock.Anything, mock.Anything).
+			Return(nil, repository.ErrNoLegalHold).Once()
+
+		_, err := service.ReleaseLegalHold(ctx, "test-id", "auditor")
+		assert.ErrorIs(t, err, repository.ErrNoLegalHold)
+	})
+}
+
+func TestCreateTransactionPublishesEvent(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	service := NewTransactionServiceWithConfig(repo, TransactionServiceConfig{PublishEvents: true}, log)
+
+	repo.On("StoreWithEvents", mock.Anything, mock.Anything, mock.MatchedBy(func(events []*entity.DomainEvent) bool {
+		if len(events) != 1 {
+			return false
+		}
+		var payload entity.TransactionPayload
+		if err := json.Unmarshal(events[0].Payload, &payload); err != nil {
+			return false
+		}
+		return events[0].Type == entity.EventTransactionCreated && events[0].ID != "" &&
+			events[0].AggregateID == payload.ID && payload.Amount == 12.5 && payload.Date == "2023-04-15"
+	}), (*entity.AuditEvent)(nil)).Return("test-id", nil).Once()
+
+	id, err := service.CreateTransaction(context.Background(), "Fuel", time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC), 12.5)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", id)
+	repo.AssertExpectations(t)
+}
+
+func TestLegalHoldPublishesEvent(t *testing.T) {
+	repo := new(mocks.MockTransactionRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	service := NewTransactionServiceWithConfig(repo, TransactionServiceConfig{PublishEvents: true}, log)
+	held := &entity.Transaction{
+		ID:        "test-id",
+		Date:      time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC),
+		LegalHold: &entity.LegalHold{Reason: "Audit", PlacedBy: "auditor"},
+	}
+
+	// updated reports whether event builds a TransactionUpdated event for change
+	updated := func(change string) interface{} {
+		return mock.MatchedBy(func(event repository.EventFunc) bool {
+			if event == nil {
+				return false
+			}
+			domainEvent, err := event(held)
+			if err != nil {
+				return false
+			}
+			var payload entity.TransactionPayload
+			if err := json.Unmarshal(domainEvent.Payload, &payload); err != nil {
+				return false
+			}
+			return domainEvent.Type == entity.EventTransactionUpdated && domainEvent.AggregateID == "test-id" &&
+				payload.Change == change
+		})
+	}
+
+	repo.On("PlaceLegalHold", mock.Anything, "test-id", mock.Anything, updated(entity.ChangeLegalHoldPlaced), mock.Anything).
+		Return(held, nil).Once()
+	repo.On("ReleaseLegalHold", mock.Anything, "test-id", updated(entity.ChangeLegalHoldReleased), mock.Anything).
+		Return(held, nil).Once()
+
+	_, err := service.PlaceLegalHold(context.Background(), "test-id", "Audit", "auditor")
+	assert.NoError(t, err)
+	_, err = service.ReleaseLegalHold(context.Background(), "test-id", "auditor")
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,91 @@
+// Package service internal/application/service/transaction_stream_service.go
+package service
+
+import (
+	"context"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+)
+
+// streamBatchSize is how many stream entries Since reads at a time
+const streamBatchSize = 100
+
+// StreamFilter selects the transactions a stream client receives. Zero values
+// do not filter.
+type StreamFilter struct {
+	MinAmount float64
+	MaxAmount float64
+}
+
+// Matches reports whether tx passes the filter
+func (f StreamFilter) Matches(tx *entity.Transaction) bool {
+	if f.MinAmount > 0 && tx.Amount < f.MinAmount {
+		return false
+	}
+	if f.MaxAmount > 0 && tx.Amount > f.MaxAmount {
+		return false
+	}
+	return true
+}
+
+// TransactionStreamService reads the stream of transactions TransactionService
+// stores, for clients that follow it as it grows
+type TransactionStreamService struct {
+	repo   repository.TransactionStreamRepository
+	logger logger.Logger
+}
+
+// NewTransactionStreamService creates a new transaction stream service
+func NewTransactionStreamService(repo repository.TransactionStreamRepository, log logger.Logger) *TransactionStreamService {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &TransactionStreamService{
+		repo:   repo,
+		logger: log,
+	}
+}
+
+// Head returns the sequence number of the latest transaction, which a new client
+// follows the stream from
+func (s *TransactionStreamService) Head(ctx context.Context) (uint64, error) {
+	return s.repo.Head(ctx)
+}
+
+// Watch returns a channel that receives a value after transactions are stored.
+// It is closed when ctx is done or watching fails.
+func (s *TransactionStreamService) Watch(ctx context.Context) <-chan struct{} {
+	return s.repo.Watch(ctx)
+}
+
+// Since calls fn for each transaction after the given sequence number that
+// passes the filter, in the order they were stored. It returns the sequence
+// number of the last entry read, matching or not, for the next call to resume
+// after.
+func (s *TransactionStreamService) Since(ctx context.Context, after uint64, filter StreamFilter, fn func(*entity.StreamEntry) error) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "TransactionStreamService.Since")
+	defer span.End()
+
+	for {
+		read := 0
+		err := s.repo.ListAfter(ctx, after, streamBatchSize, func(entry *entity.StreamEntry) error {
+			read++
+			after = entry.Sequence
+			if !filter.Matches(&entry.Transaction) {
+				return nil
+			}
+			return fn(entry)
+		})
+		if err != nil {
+			tracing.SetError(span, err)
+			return after, err
+		}
+		if read < streamBatchSize {
+			return after, nil
+		}
+	}
+}
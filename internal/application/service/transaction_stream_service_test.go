@@ -0,0 +1,92 @@
+// internal/application/service/transaction_stream_service_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamFilterMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter StreamFilter
+		amount float64
+		want   bool
+	}{
+		{"No filter", StreamFilter{}, 0.01, true},
+		{"Below minimum", StreamFilter{MinAmount: 100}, 99.99, false},
+		{"At minimum", StreamFilter{MinAmount: 100}, 100, true},
+		{"Above maximum", StreamFilter{MaxAmount: 50}, 50.01, false},
+		{"Within range", StreamFilter{MinAmount: 10, MaxAmount: 50}, 25, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(&entity.Transaction{Amount: tt.amount}))
+		})
+	}
+}
+
+func TestTransactionStreamServiceSince(t *testing.T) {
+	// Enough entries to take more than one batch, alternating small and large
+	var entries []*entity.StreamEntry
+	for seq := uint64(1); seq <= streamBatchSize+50; seq++ {
+		amount := 5.0
+		if seq%2 == 0 {
+			amount = 500
+		}
+		entries = append(entries, &entity.StreamEntry{Sequence: seq, Transaction: entity.Transaction{Amount: amount}})
+	}
+
+	t.Run("Reads every batch after the sequence number", func(t *testing.T) {
+		repo := new(mocks.MockTransactionStreamRepository)
+		repo.On("ListAfter", mock.Anything, mock.Anything, streamBatchSize, mock.Anything).Return(entries, nil)
+		streams := NewTransactionStreamService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+
+		var sent []uint64
+		last, err := streams.Since(context.Background(), 10, StreamFilter{}, func(entry *entity.StreamEntry) error {
+			sent = append(sent, entry.Sequence)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(streamBatchSize+50), last)
+		require.Len(t, sent, streamBatchSize+40)
+		assert.Equal(t, uint64(11), sent[0])
+		repo.AssertNumberOfCalls(t, "ListAfter", 2)
+	})
+
+	t.Run("Filtered entries still advance the sequence number", func(t *testing.T) {
+		repo := new(mocks.MockTransactionStreamRepository)
+		repo.On("ListAfter", mock.Anything, mock.Anything, streamBatchSize, mock.Anything).Return(entries[:3], nil)
+		streams := NewTransactionStreamService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+
+		var sent []uint64
+		last, err := streams.Since(context.Background(), 1, StreamFilter{MinAmount: 100}, func(entry *entity.StreamEntry) error {
+			sent = append(sent, entry.Sequence)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), last)
+		assert.Equal(t, []uint64{2}, sent)
+	})
+
+	t.Run("Stops at the first error of fn", func(t *testing.T) {
+		repo := new(mocks.MockTransactionStreamRepository)
+		repo.On("ListAfter", mock.Anything, mock.Anything, streamBatchSize, mock.Anything).Return(entries, nil)
+		streams := NewTransactionStreamService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+
+		closed := errors.New("connection closed")
+		_, err := streams.Since(context.Background(), 0, StreamFilter{}, func(*entity.StreamEntry) error {
+			return closed
+		})
+		assert.ErrorIs(t, err, closed)
+		repo.AssertNumberOfCalls(t, "ListAfter", 1)
+	})
+}
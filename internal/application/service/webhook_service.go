@@ -0,0 +1,188 @@
+// Package service internal/application/service/webhook_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"github.com/google/uuid"
+)
+
+// WebhookService manages a tenant's webhook subscriptions and their deliveries
+type WebhookService struct {
+	repo   repository.WebhookRepository
+	logger logger.Logger
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repository.WebhookRepository, log logger.Logger) *WebhookService {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &WebhookService{
+		repo:   repo,
+		logger: log,
+	}
+}
+
+// Subscribe registers a subscription, which must be valid, giving it an ID and a
+// new signing secret. The secret is only ever returned here.
+func (s *WebhookService) Subscribe(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Subscribe")
+	defer span.End()
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	subscription.ID = uuid.New().String()
+	subscription.Secret = "whsec_" + hex.EncodeToString(secret)
+	subscription.CreatedAt = time.Now().UTC()
+
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		tracing.SetError(span, err)
+		return err
+	}
+
+	logger.ForContext(ctx, s.logger).Info("Webhook subscription created", map[string]interface{}{
+		"id":     subscription.ID,
+		"url":    subscription.URL,
+		"events": subscription.Events,
+	})
+	return nil
+}
+
+// GetSubscription returns a subscription of the context's tenant
+func (s *WebhookService) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetSubscription")
+	defer span.End()
+
+	subscription, err := s.repo.GetSubscription(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrSubscriptionNotFound) {
+		tracing.SetError(span, err)
+	}
+	return subscription, err
+}
+
+// ListSubscriptions calls fn for each subscription of the context's tenant
+func (s *WebhookService) ListSubscriptions(ctx context.Context, fn func(*entity.WebhookSubscription) error) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListSubscriptions")
+	defer span.End()
+
+	if err := s.repo.ListSubscriptions(ctx, fn); err != nil {
+		tracing.SetError(span, err)
+		return err
+	}
+	return nil
+}
+
+// Unsubscribe removes a subscription. Deliveries still queued for it are
+// dead-lettered when they come due; its delivery history is kept.
+func (s *WebhookService) Unsubscribe(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Unsubscribe")
+	defer span.End()
+
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		if !errors.Is(err, repository.ErrSubscriptionNotFound) {
+			tracing.SetError(span, err)
+		}
+		return err
+	}
+
+	logger.ForContext(ctx, s.logger).Info("Webhook subscription deleted", map[string]interface{}{
+		"id": id,
+	})
+	return nil
+}
+
+// Deliveries returns up to limit deliveries to a subscription, newest first. A
+// non-empty status only returns deliveries with that status.
+func (s *WebhookService) Deliveries(ctx context.Context, subscriptionID, status string, limit int) ([]*entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Deliveries")
+	defer span.End()
+
+	var deliveries []*entity.WebhookDelivery
+	err := s.repo.ListDeliveries(ctx, subscriptionID, func(delivery *entity.WebhookDelivery) error {
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+		return nil
+	})
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	// The repository lists oldest first
+	for i, j := 0, len(deliveries)-1; i < j; i, j = i+1, j-1 {
+		deliveries[i], deliveries[j] = deliveries[j], deliveries[i]
+	}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// DeadLetters returns the dead deliveries of the context's tenant, oldest first
+func (s *WebhookService) DeadLetters(ctx context.Context) ([]*entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeadLetters")
+	defer span.End()
+
+	var deliveries []*entity.WebhookDelivery
+	err := s.repo.ListDeadLetters(ctx, func(delivery *entity.WebhookDelivery) error {
+		deliveries = append(deliveries, delivery)
+		return nil
+	})
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+// RetryDelivery moves a dead delivery back to the queue with a fresh set of
+// attempts, due at once. It fails with ErrDeliveryNotDead for any other delivery
+// and with ErrSubscriptionNotFound once its subscription has been deleted.
+func (s *WebhookService) RetryDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.RetryDelivery")
+	defer span.End()
+
+	delivery, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != entity.DeliveryDead {
+		return nil, fmt.Errorf("%w: %s", repository.ErrDeliveryNotDead, id)
+	}
+	if _, err := s.repo.GetSubscription(ctx, delivery.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	delivery.Status = entity.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	logger.ForContext(ctx, s.logger).Info("Webhook delivery requeued", map[string]interface{}{
+		"id":              id,
+		"subscription_id": delivery.SubscriptionID,
+	})
+	return delivery, nil
+}
@@ -0,0 +1,119 @@
+// internal/application/service/webhook_service_test.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookServiceSubscribe(t *testing.T) {
+	repo := new(mocks.MockWebhookRepository)
+	webhooks := NewWebhookService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+	repo.On("CreateSubscription", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	first := &entity.WebhookSubscription{URL: "https://example.com/hook", Events: []string{entity.EventTransactionCreated}}
+	require.NoError(t, webhooks.Subscribe(context.Background(), first))
+	second := &entity.WebhookSubscription{URL: "https://example.com/hook", Events: []string{entity.EventTransactionCreated}}
+	require.NoError(t, webhooks.Subscribe(context.Background(), second))
+
+	assert.NotEmpty(t, first.ID)
+	assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, first.Secret)
+	assert.NotEqual(t, first.Secret, second.Secret)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.WithinDuration(t, time.Now(), first.CreatedAt, time.Minute)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookServiceDeliveries(t *testing.T) {
+	repo := new(mocks.MockWebhookRepository)
+	webhooks := NewWebhookService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var stored []*entity.WebhookDelivery
+	for i, status := range []string{entity.DeliveryDelivered, entity.DeliveryDead, entity.DeliveryDelivered, entity.DeliveryPending} {
+		stored = append(stored, &entity.WebhookDelivery{
+			ID:        fmt.Sprintf("d-%d", i),
+			Status:    status,
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	repo.On("ListDeliveries", mock.Anything, "sub-1", mock.Anything).Return(stored, nil)
+
+	t.Run("Newest first, up to the limit", func(t *testing.T) {
+		deliveries, err := webhooks.Deliveries(context.Background(), "sub-1", "", 3)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 3)
+		assert.Equal(t, "d-3", deliveries[0].ID)
+		assert.Equal(t, "d-1", deliveries[2].ID)
+	})
+
+	t.Run("Filtered by status", func(t *testing.T) {
+		deliveries, err := webhooks.Deliveries(context.Background(), "sub-1", entity.DeliveryDelivered, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, "d-2", deliveries[0].ID)
+		assert.Equal(t, "d-0", deliveries[1].ID)
+	})
+}
+
+func TestWebhookServiceRetryDelivery(t *testing.T) {
+	subscription := &entity.WebhookSubscription{ID: "sub-1"}
+	dead := func() *entity.WebhookDelivery {
+		return &entity.WebhookDelivery{
+			ID:             "d-1",
+			SubscriptionID: "sub-1",
+			Status:         entity.DeliveryDead,
+			Attempts:       8,
+			History:        []entity.DeliveryAttempt{{Error: "timeout"}},
+		}
+	}
+
+	t.Run("A dead delivery is queued again with fresh attempts", func(t *testing.T) {
+		repo := new(mocks.MockWebhookRepository)
+		webhooks := NewWebhookService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+		repo.On("GetDelivery", mock.Anything, "d-1").Return(dead(), nil)
+		repo.On("GetSubscription", mock.Anything, "sub-1").Return(subscription, nil)
+		repo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *entity.WebhookDelivery) bool {
+			return d.Status == entity.DeliveryPending && d.Attempts == 0 && len(d.History) == 1 &&
+				!d.NextAttemptAt.After(time.Now())
+		})).Return(nil).Once()
+
+		delivery, err := webhooks.RetryDelivery(context.Background(), "d-1")
+		require.NoError(t, err)
+		assert.Equal(t, entity.DeliveryPending, delivery.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Only dead deliveries can be retried", func(t *testing.T) {
+		repo := new(mocks.MockWebhookRepository)
+		webhooks := NewWebhookService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+		delivered := dead()
+		delivered.Status = entity.DeliveryDelivered
+		repo.On("GetDelivery", mock.Anything, "d-1").Return(delivered, nil)
+
+		_, err := webhooks.RetryDelivery(context.Background(), "d-1")
+		assert.ErrorIs(t, err, repository.ErrDeliveryNotDead)
+		repo.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deliveries of deleted subscriptions cannot be retried", func(t *testing.T) {
+		repo := new(mocks.MockWebhookRepository)
+		webhooks := NewWebhookService(repo, logger.NewJSONLogger(nil, logger.InfoLevel))
+		repo.On("GetDelivery", mock.Anything, "d-1").Return(dead(), nil)
+		repo.On("GetSubscription", mock.Anything, "sub-1").Return(nil, repository.ErrSubscriptionNotFound)
+
+		_, err := webhooks.RetryDelivery(context.Background(), "d-1")
+		assert.ErrorIs(t, err, repository.ErrSubscriptionNotFound)
+		repo.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,47 @@
+// Package entity internal/domain/entity/audit_event.go
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Audit actions recorded against a transaction
+const (
+	AuditTransactionCreated   = "transaction.created"
+	AuditTransactionConverted = "transaction.converted"
+	AuditLegalHoldPlaced      = "legal_hold.placed"
+	AuditLegalHoldReleased    = "legal_hold.released"
+)
+
+// AuditTransactionsExported is recorded once per export, against no transaction
+const AuditTransactionsExported = "transactions.exported"
+
+// AuditEvent is an entry in a tenant's append-only audit log. Each event carries
+// the hash of the one before it, so altering, reordering or removing an event
+// breaks the chain.
+type AuditEvent struct {
+	Sequence      uint64            `json:"sequence"`
+	TenantID      string            `json:"tenant_id"`
+	TransactionID string            `json:"transaction_id"`
+	Action        string            `json:"action"`
+	Actor         string            `json:"actor"`
+	RequestID     string            `json:"request_id,omitempty"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	Details       map[string]string `json:"details,omitempty"`
+	PrevHash      string            `json:"prev_hash"`
+	Hash          string            `json:"hash"`
+}
+
+// ComputeHash returns the hex SHA-256 of the event's JSON encoding without its
+// Hash. The encoding covers PrevHash, which links the event to its predecessor.
+func (e *AuditEvent) ComputeHash() string {
+	unhashed := *e
+	unhashed.Hash = ""
+	// A struct of strings, numbers, times and a string map always encodes
+	data, _ := json.Marshal(unhashed)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
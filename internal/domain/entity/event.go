@@ -0,0 +1,150 @@
+// Package entity internal/domain/entity/event.go
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types
+const (
+	EventTransactionCreated = "TransactionCreated"
+	EventTransactionUpdated = "TransactionUpdated"
+	EventTransactionDeleted = "TransactionDeleted"
+	EventRateSyncCompleted  = "RateSyncCompleted"
+)
+
+// EventTypes lists every domain event type
+var EventTypes = []string{
+	EventTransactionCreated,
+	EventTransactionUpdated,
+	EventTransactionDeleted,
+	EventRateSyncCompleted,
+}
+
+// Changes reported by TransactionUpdated events
+const (
+	ChangeLegalHoldPlaced   = "legal_hold_placed"
+	ChangeLegalHoldReleased = "legal_hold_released"
+)
+
+// DomainEvent records something that happened to a transaction, for delivery to
+// downstream systems. Delivery is at least once, so consumers should use ID to
+// discard duplicates.
+type DomainEvent struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	TenantID    string          `json:"tenant_id"`
+	AggregateID string          `json:"aggregate_id,omitempty"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// TransactionPayload is the payload of TransactionCreated and TransactionUpdated
+// events: the transaction as it is after the change
+type TransactionPayload struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	Category    string     `json:"category,omitempty"`
+	Date        string     `json:"date"`
+	Amount      float64    `json:"amount"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LegalHold   *LegalHold `json:"legal_hold,omitempty"`
+	// Change says what changed, for TransactionUpdated events
+	Change string `json:"change,omitempty"`
+}
+
+// TransactionDeletedPayload is the payload of a TransactionDeleted event
+type TransactionDeletedPayload struct {
+	ID string `json:"id"`
+	// Reason is why the transaction was deleted; transactions are only deleted
+	// when their retention period ends, so it is always "expired"
+	Reason string `json:"reason"`
+}
+
+// RateSyncPayload is the payload of a RateSyncCompleted event
+type RateSyncPayload struct {
+	Currencies []string `json:"currencies"`
+	// Dates is the number of transaction dates rates were fetched for
+	Dates  int `json:"dates"`
+	Synced int `json:"synced"`
+	Failed int `json:"failed"`
+}
+
+// NewTransactionCreatedEvent returns the event announcing tx. The tenant is set
+// when the event is stored.
+func NewTransactionCreatedEvent(id string, tx *Transaction) (*DomainEvent, error) {
+	return newEvent(id, EventTransactionCreated, tx.ID, tx.CreatedAt, transactionPayload(tx, ""))
+}
+
+// NewTransactionUpdatedEvent returns the event announcing a change to tx made at
+// the given time
+func NewTransactionUpdatedEvent(id string, tx *Transaction, change string, at time.Time) (*DomainEvent, error) {
+	return newEvent(id, EventTransactionUpdated, tx.ID, at, transactionPayload(tx, change))
+}
+
+// NewTransactionDeletedEvent returns the event announcing that the transaction
+// with ID transactionID was deleted at the given time
+func NewTransactionDeletedEvent(id, transactionID, reason string, at time.Time) (*DomainEvent, error) {
+	return newEvent(id, EventTransactionDeleted, transactionID, at, TransactionDeletedPayload{
+		ID:     transactionID,
+		Reason: reason,
+	})
+}
+
+// NewRateSyncCompletedEvent returns the event announcing a finished rate sync
+func NewRateSyncCompletedEvent(id string, result RateSyncPayload, at time.Time) (*DomainEvent, error) {
+	return newEvent(id, EventRateSyncCompleted, "", at, result)
+}
+
+// transactionPayload describes tx in an event
+func transactionPayload(tx *Transaction, change string) TransactionPayload {
+	return TransactionPayload{
+		ID:          tx.ID,
+		Description: tx.Description,
+		Category:    tx.Category,
+		Date:        tx.Date.Format("2006-01-02"),
+		Amount:      tx.Amount,
+		CreatedAt:   tx.CreatedAt,
+		LegalHold:   tx.LegalHold,
+		Change:      change,
+	}
+}
+
+// newEvent builds an event with a JSON payload
+func newEvent(id, eventType, aggregateID string, at time.Time, payload interface{}) (*DomainEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DomainEvent{
+		ID:          id,
+		Type:        eventType,
+		AggregateID: aggregateID,
+		OccurredAt:  at,
+		Payload:     data,
+	}, nil
+}
+
+// OutboxEntry is a domain event waiting in the outbox, with its delivery state
+type OutboxEntry struct {
+	Event DomainEvent `json:"event"`
+	// Attempts is the number of failed delivery attempts
+	Attempts int `json:"attempts,omitempty"`
+	// NextAttemptAt is when delivery is next tried; zero means now
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+	// DeliveredTo names the sinks that have accepted the event
+	DeliveredTo []string `json:"delivered_to,omitempty"`
+	LastError   string   `json:"last_error,omitempty"`
+}
+
+// Delivered reports whether the sink has accepted the event
+func (e *OutboxEntry) Delivered(sink string) bool {
+	for _, name := range e.DeliveredTo {
+		if name == sink {
+			return true
+		}
+	}
+	return false
+}
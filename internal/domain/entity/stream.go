@@ -0,0 +1,10 @@
+// Package entity internal/domain/entity/stream.go
+package entity
+
+// StreamEntry is a transaction in its tenant's stream of stored transactions.
+// Sequence numbers increase with each transaction the tenant stores, so a client
+// that remembers the last one it received can resume after it.
+type StreamEntry struct {
+	Sequence    uint64      `json:"sequence"`
+	Transaction Transaction `json:"transaction"`
+}
@@ -2,39 +2,124 @@ package entity
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// ErrValidation is matched, with errors.Is, by every error Validate returns
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError describes why a transaction or legal hold is invalid
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Validation errors of a transaction
+var (
+	ErrDescriptionTooLong = &ValidationError{Message: "description must not exceed 50 characters"}
+	ErrCategoryTooLong    = &ValidationError{Message: fmt.Sprintf("category must not exceed %d characters", MaxCategoryLength)}
+	ErrInvalidAmount      = &ValidationError{Message: "amount must be a positive value"}
+	ErrFutureDate         = &ValidationError{Message: "transaction date cannot be in the future"}
+)
+
 // Transaction represents a purchase transaction
 type Transaction struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	Date        time.Time `json:"date"`
-	Amount      float64   `json:"amount"`
-	CreatedAt   time.Time `json:"created_at"`
-	TTL         int64     `json:"ttl,omitempty"` // Time-to-live for DynamoDB
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id,omitempty"`
+	Description string     `json:"description"`
+	Category    string     `json:"category,omitempty"`
+	Date        time.Time  `json:"date"`
+	Amount      float64    `json:"amount"`
+	CreatedAt   time.Time  `json:"created_at"`
+	TTL         int64      `json:"ttl,omitempty"` // Time-to-live for DynamoDB
+	LegalHold   *LegalHold `json:"legal_hold,omitempty"`
+}
+
+// LegalHold suspends a transaction's expiry while it is under audit or dispute
+type LegalHold struct {
+	Reason   string    `json:"reason"`
+	PlacedBy string    `json:"placed_by"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+// MaxLegalHoldReasonLength is the longest reason a legal hold may record
+const MaxLegalHoldReasonLength = 500
+
+// Validate ensures the hold records why it was placed and by whom
+func (h *LegalHold) Validate() error {
+	if strings.TrimSpace(h.Reason) == "" {
+		return &ValidationError{Message: "legal hold reason is required"}
+	}
+	if len(h.Reason) > MaxLegalHoldReasonLength {
+		return &ValidationError{Message: fmt.Sprintf("legal hold reason must not exceed %d characters", MaxLegalHoldReasonLength)}
+	}
+	if h.PlacedBy == "" {
+		return &ValidationError{Message: "legal hold actor is required"}
+	}
+	return nil
 }
 
+// MaxCategoryLength is the longest category a transaction may have
+const MaxCategoryLength = 50
+
 // Validate ensures the transaction meets all requirements
 func (t *Transaction) Validate() error {
 	if len(t.Description) > 50 {
-		return errors.New("description must not exceed 50 characters")
+		return ErrDescriptionTooLong
+	}
+
+	if len(t.Category) > MaxCategoryLength {
+		return ErrCategoryTooLong
 	}
 
 	if t.Amount <= 0 {
-		return errors.New("amount must be a positive value")
+		return ErrInvalidAmount
 	}
 
 	if t.Date.After(time.Now()) {
-		return errors.New("transaction date cannot be in the future")
+		return ErrFutureDate
 	}
 
 	return nil
 }
 
-// CalculateTTL calculates the TTL for data retention (1 year)
-func (t *Transaction) CalculateTTL() {
-	// Set TTL to one year from creation date
-	oneYearFromNow := t.CreatedAt.AddDate(1, 0, 0)
-	t.TTL = oneYearFromNow.Unix()
+// DefaultRetention is how long a transaction is kept when no retention is configured
+const DefaultRetention = 365 * 24 * time.Hour
+
+// CalculateTTL sets the time the transaction expires, retention after its creation.
+// A zero retention uses DefaultRetention.
+func (t *Transaction) CalculateTTL(retention time.Duration) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	t.TTL = t.CreatedAt.Add(retention).Unix()
+}
+
+// ExpiresAt returns the time the transaction expires, or the zero time if it does not
+func (t *Transaction) ExpiresAt() time.Time {
+	if t.TTL <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(t.TTL, 0).UTC()
+}
+
+// Held reports whether the transaction is under legal hold
+func (t *Transaction) Held() bool {
+	return t.LegalHold != nil
+}
+
+// Expired reports whether the transaction's retention period has passed at now.
+// A transaction under legal hold does not expire until the hold is released.
+func (t *Transaction) Expired(now time.Time) bool {
+	return !t.Held() && t.TTL > 0 && now.Unix() >= t.TTL
 }
@@ -0,0 +1,90 @@
+// Package entity internal/domain/entity/webhook.go
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// DeliveryDead marks a delivery that failed every attempt; it stays in the
+	// dead-letter list until it is retried
+	DeliveryDead = "dead"
+)
+
+// MaxDeliveryAttemptHistory is the number of attempts kept with a delivery
+const MaxDeliveryAttemptHistory = 20
+
+// WebhookSubscription is an endpoint registered by a tenant to receive events.
+// Each request is signed with the subscription's secret.
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks that the subscription has an HTTP(S) URL and known event types
+func (s *WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	if len(s.Events) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	for _, eventType := range s.Events {
+		if !slices.Contains(EventTypes, eventType) {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+// Subscribes reports whether the subscription receives events of eventType
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	return slices.Contains(s.Events, eventType)
+}
+
+// WebhookDelivery is one event on its way to one subscription, with the history
+// of its attempts
+type WebhookDelivery struct {
+	ID             string      `json:"id"`
+	SubscriptionID string      `json:"subscription_id"`
+	TenantID       string      `json:"tenant_id"`
+	Event          DomainEvent `json:"event"`
+	Status         string      `json:"status"`
+	// Attempts counts the attempts since the delivery was enqueued or last retried
+	// from the dead-letter list
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	// History holds the most recent attempts, oldest first
+	History []DeliveryAttempt `json:"history,omitempty"`
+}
+
+// DeliveryAttempt records one attempt to deliver a webhook
+type DeliveryAttempt struct {
+	At         time.Time     `json:"at"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Record adds an attempt to the delivery's history, dropping the oldest beyond
+// MaxDeliveryAttemptHistory
+func (d *WebhookDelivery) Record(attempt DeliveryAttempt) {
+	d.Attempts++
+	d.History = append(d.History, attempt)
+	if over := len(d.History) - MaxDeliveryAttemptHistory; over > 0 {
+		d.History = d.History[over:]
+	}
+}
@@ -0,0 +1,24 @@
+// Package repository internal/domain/repository/audit_repository.go
+package repository
+
+import (
+	"context"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// AuditRepository defines the interface for the append-only audit log. Events are
+// chained per tenant and can never be changed or removed.
+type AuditRepository interface {
+	// Append adds an event to the end of the context tenant's chain, setting its
+	// tenant, sequence number and hashes
+	Append(ctx context.Context, event *entity.AuditEvent) error
+
+	// List calls fn for each event of the context's tenant in sequence order,
+	// stopping at the first error fn returns
+	List(ctx context.Context, fn func(*entity.AuditEvent) error) error
+
+	// ListByTransaction calls fn for each event recorded against a transaction of
+	// the context's tenant in sequence order, stopping at the first error fn returns
+	ListByTransaction(ctx context.Context, transactionID string, fn func(*entity.AuditEvent) error) error
+}
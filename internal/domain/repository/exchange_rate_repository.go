@@ -3,14 +3,36 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
 )
 
+var (
+	// ErrNoExchangeRate is returned when no rate of the currency was published
+	// within the lookback window before the date
+	ErrNoExchangeRate = errors.New("no exchange rate available")
+	// ErrRateOutOfRange is returned when the only rate found falls outside the
+	// lookback window before the date
+	ErrRateOutOfRange = errors.New("exchange rate outside the allowed range")
+)
+
+// LookbackError is returned when no rate applies to a date, with the lookback
+// window the repository applied. Err wraps ErrNoExchangeRate or ErrRateOutOfRange.
+type LookbackError struct {
+	Err    error
+	Months int
+}
+
+func (e *LookbackError) Error() string { return e.Err.Error() }
+
+func (e *LookbackError) Unwrap() error { return e.Err }
+
 // ExchangeRateRepository defines the interface for exchange rate access
 type ExchangeRateRepository interface {
-	// FindRate finds an exchange rate for a specific currency and date
+	// FindRate finds an exchange rate for a specific currency and date. It returns
+	// an error wrapping ErrNoExchangeRate or ErrRateOutOfRange if no rate applies.
 	FindRate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error)
 
 	// StoreRate saves an exchange rate
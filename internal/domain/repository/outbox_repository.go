@@ -0,0 +1,27 @@
+// Package repository internal/domain/repository/outbox_repository.go
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// OutboxRepository defines the interface for reading and updating the outbox of
+// domain events. Events describing a transaction write are added to it by the
+// TransactionRepository in the same Badger transaction as the write.
+type OutboxRepository interface {
+	// Add adds events that accompany no transaction write, for the context's tenant
+	Add(ctx context.Context, events ...*entity.DomainEvent) error
+
+	// Due returns up to limit entries whose next delivery attempt is due at now,
+	// oldest first
+	Due(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEntry, error)
+
+	// Update saves an entry's delivery state
+	Update(ctx context.Context, entry *entity.OutboxEntry) error
+
+	// Delete removes an entry once every sink has accepted its event
+	Delete(ctx context.Context, entry *entity.OutboxEntry) error
+}
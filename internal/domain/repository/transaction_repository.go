@@ -2,15 +2,77 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
 )
 
+var (
+	// ErrTransactionNotFound is returned when no transaction has the requested ID
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionExpired is returned for a transaction whose retention period has
+	// passed, whether or not it has been purged yet
+	ErrTransactionExpired = errors.New("transaction expired")
+	// ErrLegalHoldExists is returned when placing a hold on a transaction that is
+	// already under legal hold
+	ErrLegalHoldExists = errors.New("transaction already under legal hold")
+	// ErrNoLegalHold is returned when releasing a hold on a transaction that is not
+	// under legal hold
+	ErrNoLegalHold = errors.New("transaction not under legal hold")
+)
+
+// EventFunc builds the event announcing a change to a transaction. Repositories
+// call it with the changed transaction inside the write that makes the change, so
+// the event is added to the outbox atomically with it.
+type EventFunc func(transaction *entity.Transaction) (*entity.DomainEvent, error)
+
 // TransactionRepository defines the interface for transaction storage
 type TransactionRepository interface {
 	// Store saves a transaction and returns its ID
 	Store(ctx context.Context, transaction *entity.Transaction) (string, error)
 
-	// FindByID retrieves a transaction by its unique identifier
+	// StoreWithEvents saves a transaction, adds events to the outbox and appends a
+	// non-nil audit event to the audit log atomically: either all are stored or none
+	// is
+	StoreWithEvents(ctx context.Context, transaction *entity.Transaction, events []*entity.DomainEvent, audit *entity.AuditEvent) (string, error)
+
+	// FindByID retrieves a transaction by its unique identifier. It returns an error
+	// wrapping ErrTransactionNotFound or ErrTransactionExpired if it is unavailable.
 	FindByID(ctx context.Context, id string) (*entity.Transaction, error)
+
+	// List calls fn for each transaction of the context's tenant, stopping at the
+	// first error fn returns
+	List(ctx context.Context, fn func(*entity.Transaction) error) error
+
+	// ListAfter calls fn for each transaction of the context's tenant whose ID sorts
+	// after after, in ID order, stopping at the first error fn returns. An empty
+	// after starts from the first transaction.
+	ListAfter(ctx context.Context, after string, fn func(*entity.Transaction) error) error
+
+	// ListByDate calls fn for each transaction of the context's tenant dated from
+	// from to to, both inclusive, in date order, stopping at the first error fn
+	// returns. A zero from or to leaves that end of the range open.
+	ListByDate(ctx context.Context, from, to time.Time, fn func(*entity.Transaction) error) error
+
+	// ListByDateAfter is ListByDate resuming after the transaction after, which an
+	// earlier listing of the range returned. Transactions of a date are listed in ID
+	// order. A nil after starts from from.
+	ListByDateAfter(ctx context.Context, from, to time.Time, after *entity.Transaction, fn func(*entity.Transaction) error) error
+
+	// PlaceLegalHold puts a transaction under legal hold, suspending its expiry, and
+	// returns the held transaction. It returns an error wrapping ErrLegalHoldExists
+	// if the transaction is already held. A non-nil event adds its event to the
+	// outbox, and a non-nil audit is appended to the audit log, with the change.
+	PlaceLegalHold(ctx context.Context, id string, hold entity.LegalHold, event EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error)
+
+	// ReleaseLegalHold lifts a transaction's legal hold, restoring its expiry, and
+	// returns the released transaction. It returns an error wrapping ErrNoLegalHold
+	// if the transaction is not held. A non-nil event adds its event to the outbox,
+	// and a non-nil audit is appended to the audit log, with the change.
+	ReleaseLegalHold(ctx context.Context, id string, event EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error)
+
+	// ListLegalHolds calls fn for each held transaction of the context's tenant,
+	// stopping at the first error fn returns
+	ListLegalHolds(ctx context.Context, fn func(*entity.Transaction) error) error
 }
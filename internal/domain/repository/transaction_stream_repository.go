@@ -0,0 +1,25 @@
+// Package repository internal/domain/repository/transaction_stream_repository.go
+package repository
+
+import (
+	"context"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// TransactionStreamRepository reads the stream of stored transactions. Entries
+// are added by the transaction repository as each transaction is stored.
+// Everything is scoped to the context's tenant.
+type TransactionStreamRepository interface {
+	// Head returns the sequence number of the latest entry, or 0 if there is none
+	Head(ctx context.Context) (uint64, error)
+
+	// ListAfter calls fn for up to limit entries with a sequence number above
+	// after, in sequence order, stopping at the first error fn returns. fn is
+	// called once the entries are read, so it may write to a slow client.
+	ListAfter(ctx context.Context, after uint64, limit int, fn func(*entity.StreamEntry) error) error
+
+	// Watch returns a channel that receives a value after entries are added. The
+	// channel is closed when ctx is done or watching fails.
+	Watch(ctx context.Context) <-chan struct{}
+}
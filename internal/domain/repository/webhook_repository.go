@@ -0,0 +1,63 @@
+// Package repository internal/domain/repository/webhook_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+var (
+	// ErrSubscriptionNotFound is returned when the context's tenant has no webhook
+	// subscription with the requested ID
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrDeliveryNotFound is returned when the context's tenant has no webhook
+	// delivery with the requested ID
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrDeliveryNotDead is returned when retrying a delivery that is not in the
+	// dead-letter list
+	ErrDeliveryNotDead = errors.New("webhook delivery is not dead-lettered")
+)
+
+// WebhookRepository defines the interface for webhook subscriptions and their
+// deliveries. Everything but Due is scoped to the context's tenant.
+type WebhookRepository interface {
+	// CreateSubscription saves a new subscription
+	CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error
+
+	// GetSubscription returns a subscription, or an error wrapping
+	// ErrSubscriptionNotFound
+	GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+
+	// ListSubscriptions calls fn for each subscription, stopping at the first error
+	// fn returns
+	ListSubscriptions(ctx context.Context, fn func(*entity.WebhookSubscription) error) error
+
+	// DeleteSubscription removes a subscription, or returns an error wrapping
+	// ErrSubscriptionNotFound. Its delivery history is kept.
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// Enqueue saves new pending deliveries atomically. Deliveries whose ID is
+	// already stored are skipped, so enqueueing an event twice is harmless.
+	Enqueue(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+
+	// Due returns up to limit pending deliveries of any tenant whose next attempt is
+	// due at now
+	Due(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+
+	// UpdateDelivery saves a delivery's status and history
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// GetDelivery returns a delivery, or an error wrapping ErrDeliveryNotFound
+	GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+
+	// ListDeliveries calls fn for each delivery to a subscription, stopping at the
+	// first error fn returns
+	ListDeliveries(ctx context.Context, subscriptionID string, fn func(*entity.WebhookDelivery) error) error
+
+	// ListDeadLetters calls fn for each dead delivery, stopping at the first error
+	// fn returns
+	ListDeadLetters(ctx context.Context, fn func(*entity.WebhookDelivery) error) error
+}
@@ -8,12 +8,21 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/cache"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -21,22 +30,61 @@ const (
 	exchangeRatePath = "/v1/accounting/od/rates_of_exchange"
 )
 
+// ClientConfig holds the Treasury API client settings
+type ClientConfig struct {
+	// BaseURL is the fiscal data API root
+	BaseURL string
+	// Timeout bounds each HTTP request
+	Timeout time.Duration
+	// MaxRetries is the number of attempts made for each lookup
+	MaxRetries int
+	// LookbackMonths is how far before the purchase date a rate may be taken from
+	LookbackMonths int
+	// CacheTTL is how long fetched rates are cached
+	CacheTTL time.Duration
+}
+
+// DefaultClientConfig returns the settings used by NewTreasuryAPIClient
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		BaseURL:        treasuryBaseURL,
+		Timeout:        10 * time.Second,
+		MaxRetries:     3,
+		LookbackMonths: 6,
+		CacheTTL:       24 * time.Hour,
+	}
+}
+
 // TreasuryAPIClient is a client for the Treasury API
 type TreasuryAPIClient struct {
-	baseURL    string
-	httpClient *http.Client
-	cache      *cache.ExchangeRateCache
-	logger     logger.Logger
+	baseURL        string
+	httpClient     *http.Client
+	cache          *cache.ExchangeRateCache
+	logger         logger.Logger
+	metrics        metrics.Metrics
+	maxRetries     int
+	lookbackMonths int
+	lastSuccess    atomic.Int64
+	lastFailure    atomic.Int64
 }
 
 // Ensure TreasuryAPIClient implements the ExchangeRateProvider interface
 var _ db.ExchangeRateProvider = (*TreasuryAPIClient)(nil)
 
-// NewTreasuryAPIClient creates a new Treasury API client
+// NewTreasuryAPIClient creates a new Treasury API client with the default settings
 func NewTreasuryAPIClient(log logger.Logger) *TreasuryAPIClient {
+	return NewTreasuryAPIClientWithConfig(DefaultClientConfig(), log, nil)
+}
+
+// NewTreasuryAPIClientWithConfig creates a new Treasury API client with the given settings
+func NewTreasuryAPIClientWithConfig(config ClientConfig, log logger.Logger, m metrics.Metrics) *TreasuryAPIClient {
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
 	// Create default HTTP client with circuit breaker configuration
 	httpClient := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout: config.Timeout,
 		Transport: &http.Transport{
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 20,
@@ -45,12 +93,87 @@ func NewTreasuryAPIClient(log logger.Logger) *TreasuryAPIClient {
 		},
 	}
 
+	rateCache := cache.NewExchangeRateCache()
+	if config.CacheTTL > 0 {
+		rateCache.SetExpiration(config.CacheTTL)
+	}
+
 	return &TreasuryAPIClient{
-		baseURL:    treasuryBaseURL,
-		httpClient: httpClient,
-		cache:      cache.NewExchangeRateCache(),
-		logger:     log,
+		baseURL:        config.BaseURL,
+		httpClient:     httpClient,
+		cache:          rateCache,
+		logger:         log,
+		metrics:        m,
+		maxRetries:     config.MaxRetries,
+		lookbackMonths: config.LookbackMonths,
+	}
+}
+
+// Cache returns the client's exchange rate cache
+func (c *TreasuryAPIClient) Cache() *cache.ExchangeRateCache {
+	return c.cache
+}
+
+// doRequest sends a single attempt inside a client span. The span's W3C trace
+// context is injected into the outbound headers.
+func (c *TreasuryAPIClient) doRequest(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+	ctx, span := tracing.Start(ctx, "GET "+exchangeRatePath, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.Int("http.request.resend_count", attempt-1),
+	))
+	defer span.End()
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	return resp, nil
+}
+
+// LastSuccess returns when the API last answered a lookup, or the zero time
+func (c *TreasuryAPIClient) LastSuccess() time.Time {
+	return unixNanoTime(c.lastSuccess.Load())
+}
+
+// LastFailure returns when a lookup last failed to get an answer from the API, or the zero time
+func (c *TreasuryAPIClient) LastFailure() time.Time {
+	return unixNanoTime(c.lastFailure.Load())
+}
+
+// unixNanoTime converts a stored timestamp, treating zero as unset
+func unixNanoTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
 	}
+	return time.Unix(0, n)
+}
+
+// Ping checks that the API is reachable and answering. It requests a single
+// record so that it is cheap for both sides.
+func (c *TreasuryAPIClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+exchangeRatePath+"?page[size]=1", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("treasury API unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("treasury API returned status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 // TreasuryResponse represents the response structure from the Treasury API
@@ -75,32 +198,56 @@ type TreasuryResponse struct {
 }
 
 // FetchExchangeRate retrieves the exchange rate from the Treasury API
-func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error) {
-	requestID := ctx.Value("request_id")
-	if requestID == nil {
-		requestID = "unknown"
-	}
+func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency string, date time.Time) (result *entity.ExchangeRate, err error) {
+	ctx, span := tracing.Start(ctx, "TreasuryAPIClient.FetchExchangeRate", trace.WithAttributes(
+		attribute.String("currency", currency),
+		attribute.String("date", date.Format("2006-01-02")),
+	))
+	defer func() {
+		tracing.SetError(span, err)
+		span.End()
+	}()
+
+	requestID := middleware.GetRequestID(ctx)
+	log := logger.ForContext(ctx, c.logger)
 
 	// Log request details
-	c.logger.Info("Fetching exchange rate", map[string]interface{}{
-		"request_id": requestID,
-		"currency":   currency,
-		"date":       date.Format("2006-01-02"),
+	log.Info("Fetching exchange rate", map[string]interface{}{
+		"currency": currency,
+		"date":     date.Format("2006-01-02"),
 	})
 
 	// Check cache first
-	if cachedRate := c.cache.Get(currency, date); cachedRate != nil {
-		c.logger.Info("Cache hit for exchange rate", map[string]interface{}{
-			"request_id": requestID,
-			"currency":   currency,
-			"date":       date.Format("2006-01-02"),
-			"rate":       cachedRate.Rate,
+	cachedRate := c.cache.Get(currency, date)
+	span.SetAttributes(attribute.Bool("cache.hit", cachedRate != nil))
+	if cachedRate != nil {
+		c.metrics.IncCounter(metrics.CacheRequestsTotal, map[string]string{"result": "hit"})
+		log.Info("Cache hit for exchange rate", map[string]interface{}{
+			"currency": currency,
+			"date":     date.Format("2006-01-02"),
+			"rate":     cachedRate.Rate,
 		})
 		return cachedRate, nil
 	}
+	c.metrics.IncCounter(metrics.CacheRequestsTotal, map[string]string{"result": "miss"})
+
+	// Record the outcome of every lookup that reaches the API
+	outcome := ""
+	defer func() {
+		if outcome == "" {
+			outcome = metrics.Outcome(err)
+		}
+		switch outcome {
+		case "error":
+			c.lastFailure.Store(time.Now().UnixNano())
+		default:
+			c.lastSuccess.Store(time.Now().UnixNano())
+		}
+		c.metrics.IncCounter(metrics.TreasuryRequestsTotal, map[string]string{"outcome": outcome})
+	}()
 
-	// Calculate the date 6 months before the purchase date
-	sixMonthsAgo := date.AddDate(0, -6, 0)
+	// Calculate the earliest rate date allowed before the purchase date
+	windowStart := date.AddDate(0, -c.lookbackMonths, 0)
 
 	// Build request URL with appropriate filters based on the official API documentation
 	reqURL := fmt.Sprintf("%s%s?filter=currency:eq:%s,record_date:lte:%s,record_date:gte:%s&sort=-record_date&limit=1",
@@ -108,39 +255,39 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 		exchangeRatePath,
 		url.QueryEscape(currency),
 		date.Format("2006-01-02"),
-		sixMonthsAgo.Format("2006-01-02"))
+		windowStart.Format("2006-01-02"))
 
-	c.logger.Debug("Treasury API request URL", map[string]interface{}{
-		"request_id": requestID,
-		"url":        reqURL,
+	log.Debug("Treasury API request URL", map[string]interface{}{
+		"url": reqURL,
 	})
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		c.logger.Error("Failed to create request", map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
+		log.Error("Failed to create request", map[string]interface{}{
+			"error": err.Error(),
 		})
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add headers
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("X-Request-ID", fmt.Sprintf("%v", requestID))
+	req.Header.Add("X-Request-ID", requestID)
 
 	// Execute request with retry logic
 	var resp *http.Response
-	maxRetries := 3
+	maxRetries := c.maxRetries
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		startTime := time.Now()
-		resp, err = c.httpClient.Do(req)
+		resp, err = c.doRequest(ctx, req, attempt)
 		duration := time.Since(startTime)
+		c.metrics.ObserveDuration(metrics.TreasuryRequestDuration, duration, map[string]string{
+			"outcome": metrics.Outcome(err),
+		})
 
 		// Log request metrics
-		c.logger.Info("API request metrics", map[string]interface{}{
-			"request_id":   requestID,
+		log.Info("API request metrics", map[string]interface{}{
 			"attempt":      attempt,
 			"duration_ms":  duration.Milliseconds(),
 			"success":      err == nil,
@@ -153,10 +300,11 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 		}
 
 		if attempt < maxRetries {
+			c.metrics.IncCounter(metrics.TreasuryRetriesTotal, nil)
+
 			// Wait with exponential backoff before retrying
 			backoffTime := time.Duration(attempt*attempt) * time.Second
-			c.logger.Warn("Request failed, retrying", map[string]interface{}{
-				"request_id":  requestID,
+			log.Warn("Request failed, retrying", map[string]interface{}{
 				"attempt":     attempt,
 				"max_retries": maxRetries,
 				"error":       err.Error(),
@@ -167,20 +315,18 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 			// Create a new request for the retry
 			req, err = http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 			if err != nil {
-				c.logger.Error("Failed to create request for retry", map[string]interface{}{
-					"request_id": requestID,
-					"error":      err.Error(),
+				log.Error("Failed to create request for retry", map[string]interface{}{
+					"error": err.Error(),
 				})
 				return nil, fmt.Errorf("failed to create request for retry: %w", err)
 			}
 			req.Header.Add("Accept", "application/json")
-			req.Header.Add("X-Request-ID", fmt.Sprintf("%v", requestID))
+			req.Header.Add("X-Request-ID", requestID)
 		}
 	}
 
 	if err != nil {
-		c.logger.Error("Failed to execute request after multiple attempts", map[string]interface{}{
-			"request_id":  requestID,
+		log.Error("Failed to execute request after multiple attempts", map[string]interface{}{
 			"max_retries": maxRetries,
 			"error":       err.Error(),
 		})
@@ -190,9 +336,8 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 	defer func() {
 		closeErr := resp.Body.Close()
 		if closeErr != nil {
-			c.logger.Warn("Error closing response body", map[string]interface{}{
-				"request_id": requestID,
-				"error":      closeErr.Error(),
+			log.Warn("Error closing response body", map[string]interface{}{
+				"error": closeErr.Error(),
 			})
 		}
 	}()
@@ -200,23 +345,20 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 	// Read the response body
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.logger.Error("Failed to read response body", map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
+		log.Error("Failed to read response body", map[string]interface{}{
+			"error": err.Error(),
 		})
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	c.logger.Debug("Treasury API response", map[string]interface{}{
-		"request_id":  requestID,
+	log.Debug("Treasury API response", map[string]interface{}{
 		"status_code": resp.StatusCode,
 		"body_size":   len(bodyBytes),
 	})
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		c.logger.Error("API returned error status", map[string]interface{}{
-			"request_id":  requestID,
+		log.Error("API returned error status", map[string]interface{}{
 			"status_code": resp.StatusCode,
 			"body":        string(bodyBytes),
 		})
@@ -226,31 +368,31 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 	// Parse response
 	var treasuryResp TreasuryResponse
 	if err := json.Unmarshal(bodyBytes, &treasuryResp); err != nil {
-		c.logger.Error("Failed to decode response", map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
+		log.Error("Failed to decode response", map[string]interface{}{
+			"error": err.Error(),
 		})
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	// Check if any data was returned
 	if len(treasuryResp.Data) == 0 {
-		c.logger.Warn("No exchange rate data available", map[string]interface{}{
-			"request_id": requestID,
-			"currency":   currency,
-			"date":       date.Format("2006-01-02"),
-			"date_from":  sixMonthsAgo.Format("2006-01-02"),
+		outcome = "no_rate"
+		log.Warn("No exchange rate data available", map[string]interface{}{
+			"currency":  currency,
+			"date":      date.Format("2006-01-02"),
+			"date_from": windowStart.Format("2006-01-02"),
 		})
-		return nil, fmt.Errorf("no exchange rate available within 6 months of %s for currency %s",
+		return nil, &repository.LookbackError{Months: c.lookbackMonths, Err: fmt.Errorf("%w within %d months of %s for currency %s",
+			repository.ErrNoExchangeRate,
+			c.lookbackMonths,
 			date.Format("2006-01-02"),
-			currency)
+			currency)}
 	}
 
 	// Parse the exchange rate and date
 	rateData := treasuryResp.Data[0]
 
-	c.logger.Debug("Rate data retrieved", map[string]interface{}{
-		"request_id":     requestID,
+	log.Debug("Rate data retrieved", map[string]interface{}{
 		"currency":       rateData.CurrencyDesc,
 		"country":        rateData.CountryName,
 		"date":           rateData.RecordDate,
@@ -261,8 +403,7 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 	// Parse rate with better error handling
 	var rate float64
 	if _, err := fmt.Sscanf(rateData.ExchangeRate, "%f", &rate); err != nil {
-		c.logger.Error("Failed to parse exchange rate", map[string]interface{}{
-			"request_id": requestID,
+		log.Error("Failed to parse exchange rate", map[string]interface{}{
 			"rate_value": rateData.ExchangeRate,
 			"error":      err.Error(),
 		})
@@ -271,9 +412,8 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 
 	// Validate the rate is positive
 	if rate <= 0 {
-		c.logger.Error("Invalid exchange rate value", map[string]interface{}{
-			"request_id": requestID,
-			"rate":       rate,
+		log.Error("Invalid exchange rate value", map[string]interface{}{
+			"rate": rate,
 		})
 		return nil, fmt.Errorf("invalid exchange rate value: %f", rate)
 	}
@@ -281,28 +421,27 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 	// Parse date
 	rateDate, err := time.Parse("2006-01-02", rateData.RecordDate)
 	if err != nil {
-		c.logger.Error("Failed to parse rate date", map[string]interface{}{
-			"request_id": requestID,
-			"date":       rateData.RecordDate,
-			"error":      err.Error(),
+		log.Error("Failed to parse rate date", map[string]interface{}{
+			"date":  rateData.RecordDate,
+			"error": err.Error(),
 		})
 		return nil, fmt.Errorf("failed to parse rate date '%s': %w", rateData.RecordDate, err)
 	}
 
-	// Double-check that the rate date is within the required 6-month window
-	if rateDate.Before(sixMonthsAgo) || rateDate.After(date) {
-		c.logger.Error("Exchange rate date outside allowed range", map[string]interface{}{
-			"request_id":           requestID,
-			"rate_date":            rateDate.Format("2006-01-02"),
-			"transaction_date":     date.Format("2006-01-02"),
-			"six_months_prior":     sixMonthsAgo.Format("2006-01-02"),
-			"days_before_tx":       date.Sub(rateDate).Hours() / 24,
-			"days_after_six_month": rateDate.Sub(sixMonthsAgo).Hours() / 24,
+	// Double-check that the rate date is within the required lookback window
+	if rateDate.Before(windowStart) || rateDate.After(date) {
+		log.Error("Exchange rate date outside allowed range", map[string]interface{}{
+			"rate_date":         rateDate.Format("2006-01-02"),
+			"transaction_date":  date.Format("2006-01-02"),
+			"window_start":      windowStart.Format("2006-01-02"),
+			"days_before_tx":    date.Sub(rateDate).Hours() / 24,
+			"days_after_window": rateDate.Sub(windowStart).Hours() / 24,
 		})
-		return nil, fmt.Errorf("exchange rate date %s is outside the allowed range (must be between %s and %s inclusive)",
+		return nil, &repository.LookbackError{Months: c.lookbackMonths, Err: fmt.Errorf("%w: rate date %s must be between %s and %s inclusive",
+			repository.ErrRateOutOfRange,
 			rateDate.Format("2006-01-02"),
-			sixMonthsAgo.Format("2006-01-02"),
-			date.Format("2006-01-02"))
+			windowStart.Format("2006-01-02"),
+			date.Format("2006-01-02"))}
 	}
 
 	// Create exchange rate entity
@@ -314,8 +453,7 @@ func (c *TreasuryAPIClient) FetchExchangeRate(ctx context.Context, currency stri
 
 	// Store in cache
 	c.cache.Put(exchangeRate, date)
-	c.logger.Info("Cached exchange rate", map[string]interface{}{
-		"request_id":       requestID,
+	log.Info("Cached exchange rate", map[string]interface{}{
 		"currency":         currency,
 		"transaction_date": date.Format("2006-01-02"),
 		"rate_date":        rateDate.Format("2006-01-02"),
@@ -2,14 +2,24 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestFetchExchangeRate(t *testing.T) {
@@ -84,6 +94,148 @@ func TestFetchExchangeRate(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// recordingMetrics counts counter increments by name and label values
+type recordingMetrics struct {
+	counters     map[string]int
+	observations map[string]int
+	mutex        sync.Mutex
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{counters: map[string]int{}, observations: map[string]int{}}
+}
+
+func (m *recordingMetrics) IncCounter(name string, labels map[string]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.counters[name+labelString(labels)]++
+}
+
+func (m *recordingMetrics) ObserveDuration(name string, _ time.Duration, labels map[string]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.observations[name+labelString(labels)]++
+}
+
+func labelString(labels map[string]string) string {
+	var parts []string
+	for _, v := range labels {
+		parts = append(parts, v)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func TestFetchExchangeRateMetrics(t *testing.T) {
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Query().Get("filter"), "currency:eq:XYZ") {
+			w.Write([]byte(`{"data": []}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"currency": "Euro", "exchange_rate": "0.85", "record_date": "2023-04-10"}]}`))
+	}))
+	defer mockServer.Close()
+
+	config := DefaultClientConfig()
+	config.BaseURL = mockServer.URL
+	config.MaxRetries = 1
+	m := newRecordingMetrics()
+	client := NewTreasuryAPIClientWithConfig(config, log, m)
+
+	ctx := context.Background()
+	date := time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	// First lookup misses the cache and calls the API, the second is served from cache
+	_, err := client.FetchExchangeRate(ctx, "EUR", date)
+	assert.NoError(t, err)
+	_, err = client.FetchExchangeRate(ctx, "EUR", date)
+	assert.NoError(t, err)
+
+	// An empty result is recorded separately from transport errors
+	_, err = client.FetchExchangeRate(ctx, "XYZ", date)
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, m.counters[metrics.CacheRequestsTotal+"{miss}"])
+	assert.Equal(t, 1, m.counters[metrics.CacheRequestsTotal+"{hit}"])
+	assert.Equal(t, 1, m.counters[metrics.TreasuryRequestsTotal+"{success}"])
+	assert.Equal(t, 1, m.counters[metrics.TreasuryRequestsTotal+"{no_rate}"])
+	assert.Equal(t, 2, m.observations[metrics.TreasuryRequestDuration+"{success}"])
+	assert.Zero(t, m.counters[metrics.TreasuryRetriesTotal+"{}"])
+}
+
+func TestFetchExchangeRatePropagatesTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	var traceparent string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"data": [{"currency": "Euro", "exchange_rate": "0.85", "record_date": "2023-04-10"}]}`))
+	}))
+	defer mockServer.Close()
+
+	config := DefaultClientConfig()
+	config.BaseURL = mockServer.URL
+	client := NewTreasuryAPIClientWithConfig(config, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.FetchExchangeRate(ctx, "EUR", time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC))
+	parent.End()
+	require.NoError(t, err)
+
+	// The outbound request continues the caller's trace
+	traceID := parent.SpanContext().TraceID().String()
+	assert.Contains(t, traceparent, "00-"+traceID+"-")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "GET "+exchangeRatePath, spans[0].Name())
+	assert.Equal(t, "TreasuryAPIClient.FetchExchangeRate", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, traceparent, spans[0].SpanContext().SpanID().String())
+}
+
+func TestFetchExchangeRatePropagatesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewJSONLogger(&buf, logger.InfoLevel)
+
+	var outboundID string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outboundID = r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"data": [{"currency": "Euro", "exchange_rate": "0.85", "record_date": "2023-04-10"}]}`))
+	}))
+	defer mockServer.Close()
+
+	config := DefaultClientConfig()
+	config.BaseURL = mockServer.URL
+	client := NewTreasuryAPIClientWithConfig(config, log, nil)
+
+	// Run the lookup inside a request that has passed through RequestIDMiddleware
+	var err error
+	handler := middleware.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err = client.FetchExchangeRate(r.Context(), "EUR", time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC))
+	}))
+	req := httptest.NewRequest("GET", "/transactions/abc/convert", nil)
+	req.Header.Set("X-Request-ID", "req-789")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "req-789", outboundID)
+	assert.NotContains(t, buf.String(), `"request_id":"unknown"`)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		assert.Contains(t, line, `"request_id":"req-789"`)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && s[0:len(substr)] == substr
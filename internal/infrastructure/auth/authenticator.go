@@ -0,0 +1,143 @@
+// Package auth internal/infrastructure/auth/authenticator.go
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrMissingToken is returned when the request carries no bearer token
+	ErrMissingToken = errors.New("missing bearer token")
+	// ErrInvalidToken is returned when the token fails signature or claim validation
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Principal is the authenticated identity extracted from a verified token
+type Principal struct {
+	Subject string
+	Roles   []string
+	Claims  map[string]interface{}
+}
+
+// HasRole reports whether the principal has been granted the given role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Config holds the token validation settings
+type Config struct {
+	// Issuer is the expected "iss" claim
+	Issuer string
+	// Audience is the expected "aud" claim
+	Audience string
+	// RolesClaim is the claim holding the granted roles. It may be a JSON
+	// array or a space-separated string (as with the OAuth2 "scope" claim).
+	RolesClaim string
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat
+	Leeway time.Duration
+	// Algorithms restricts the accepted signing algorithms
+	Algorithms []string
+}
+
+// DefaultAlgorithms are the asymmetric signing algorithms accepted by default
+var DefaultAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Authenticator verifies bearer tokens and builds principals from their claims
+type Authenticator struct {
+	keys   KeySource
+	config Config
+}
+
+// NewAuthenticator creates a new authenticator verifying tokens against the given keys
+func NewAuthenticator(keys KeySource, config Config) *Authenticator {
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = DefaultAlgorithms
+	}
+
+	return &Authenticator{
+		keys:   keys,
+		config: config,
+	}
+}
+
+// Authenticate verifies the token's signature, issuer, audience and expiry and
+// returns the authenticated principal
+func (a *Authenticator) Authenticate(ctx context.Context, tokenString string) (*Principal, error) {
+	if tokenString == "" {
+		return nil, ErrMissingToken
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(a.config.Algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(a.config.Leeway),
+	}
+	if a.config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.config.Issuer))
+	}
+	if a.config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.config.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.Key(ctx, kid)
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return &Principal{
+		Subject: subject,
+		Roles:   extractRoles(claims[a.config.RolesClaim]),
+		Claims:  claims,
+	}, nil
+}
+
+// extractRoles reads roles from either a JSON array or a space-separated string claim
+func extractRoles(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		roles := make([]string, 0, len(v))
+		for _, r := range v {
+			if s, ok := r.(string); ok && s != "" {
+				roles = append(roles, s)
+			}
+		}
+		return roles
+	default:
+		return nil
+	}
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value
+func BearerToken(header string) string {
+	const prefix = "bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
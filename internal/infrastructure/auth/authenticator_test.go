@@ -0,0 +1,179 @@
+// internal/infrastructure/auth/authenticator_test.go
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWKS returns a JWKS document containing the public half of key
+func testJWKS(t *testing.T, kid string, key *rsa.PrivateKey) []byte {
+	t.Helper()
+
+	doc := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	return data
+}
+
+// signToken signs the claims with key using RS256
+func signToken(t *testing.T, kid string, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, testJWKS(t, "key-1", key), 0600))
+
+	keySet, err := LoadJWKSFile(path)
+	require.NoError(t, err)
+
+	authenticator := NewAuthenticator(keySet, Config{
+		Issuer:   "https://gateway.example.com",
+		Audience: "wex-tag",
+	})
+	ctx := context.Background()
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "user-123",
+			"iss":   "https://gateway.example.com",
+			"aud":   "wex-tag",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"roles": []string{"transactions:read", "transactions:write"},
+		}
+	}
+
+	t.Run("Valid token", func(t *testing.T) {
+		principal, err := authenticator.Authenticate(ctx, signToken(t, "key-1", key, validClaims()))
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", principal.Subject)
+		assert.True(t, principal.HasRole("transactions:write"))
+		assert.False(t, principal.HasRole("admin"))
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		_, err := authenticator.Authenticate(ctx, "")
+		assert.ErrorIs(t, err, ErrMissingToken)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := authenticator.Authenticate(ctx, signToken(t, "key-1", key, claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong issuer", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "https://evil.example.com"
+		_, err := authenticator.Authenticate(ctx, signToken(t, "key-1", key, claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "another-service"
+		_, err := authenticator.Authenticate(ctx, signToken(t, "key-1", key, claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Unknown signing key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = authenticator.Authenticate(ctx, signToken(t, "key-1", otherKey, validClaims()))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Scope string roles", func(t *testing.T) {
+		scoped := NewAuthenticator(keySet, Config{RolesClaim: "scope"})
+		claims := validClaims()
+		claims["scope"] = "transactions:read openid"
+		principal, err := scoped.Authenticate(ctx, signToken(t, "key-1", key, claims))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"transactions:read", "openid"}, principal.Roles)
+	})
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(testJWKS(t, "remote-key", key))
+	}))
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(server.URL, nil)
+
+	found, err := keySet.Key(context.Background(), "remote-key")
+	assert.NoError(t, err)
+	assert.Equal(t, &key.PublicKey, found)
+
+	// A second lookup is served from the cached set
+	_, err = keySet.Key(context.Background(), "remote-key")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	_, err = keySet.Key(context.Background(), "missing-key")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestPolicy(t *testing.T) {
+	policy := NewPolicy().
+		Public("GET /health").
+		Require("POST /transactions", "transactions:write")
+
+	reader := &Principal{Subject: "reader", Roles: []string{"transactions:read"}}
+	writer := &Principal{Subject: "writer", Roles: []string{"transactions:write"}}
+
+	assert.True(t, policy.IsPublic("GET /health"))
+	assert.False(t, policy.IsPublic("POST /transactions"))
+	assert.False(t, policy.Authorize("POST /transactions", reader))
+	assert.True(t, policy.Authorize("POST /transactions", writer))
+	assert.True(t, policy.Authorize("GET /other", reader))
+}
+
+func TestBearerToken(t *testing.T) {
+	assert.Equal(t, "abc", BearerToken("Bearer abc"))
+	assert.Equal(t, "abc", BearerToken("bearer abc"))
+	assert.Equal(t, "", BearerToken("Basic abc"))
+	assert.Equal(t, "", BearerToken(""))
+}
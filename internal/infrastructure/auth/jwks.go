@@ -0,0 +1,299 @@
+// Package auth internal/infrastructure/auth/jwks.go
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+)
+
+// ErrKeyNotFound is returned when no key in the set matches the token's key ID
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// KeySource provides the public keys used to verify token signatures
+type KeySource interface {
+	// Key returns the public key with the given key ID
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jwk represents a single JSON Web Key as defined in RFC 7517
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// jwkSet represents a JSON Web Key Set document
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// ParseJWKS parses a JWKS document into a map of key ID to public key.
+// Keys that are not intended for signatures are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no signing keys")
+	}
+
+	return keys, nil
+}
+
+// publicKey converts the JWK into a crypto.PublicKey
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+// decodeBigInt decodes a base64url-encoded big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("value is empty")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// StaticKeySet is a KeySource backed by a fixed set of keys, typically loaded from a file
+type StaticKeySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+// NewStaticKeySet creates a key set from already parsed keys
+func NewStaticKeySet(keys map[string]crypto.PublicKey) *StaticKeySet {
+	return &StaticKeySet{keys: keys}
+}
+
+// LoadJWKSFile reads and parses a JWKS document from the local filesystem
+func LoadJWKSFile(path string) (*StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStaticKeySet(keys), nil
+}
+
+// Key returns the public key with the given key ID
+func (s *StaticKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	return lookupKey(s.keys, kid)
+}
+
+// RemoteKeySet is a KeySource that fetches a JWKS document over HTTP and
+// refreshes it periodically or when an unknown key ID is presented
+type RemoteKeySet struct {
+	url             string
+	httpClient      *http.Client
+	refreshInterval time.Duration
+	minRefreshDelay time.Duration
+	logger          logger.Logger
+
+	mutex       sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastFetched time.Time
+}
+
+// NewRemoteKeySet creates a key set that is loaded from the given JWKS URL
+func NewRemoteKeySet(url string, log logger.Logger) *RemoteKeySet {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &RemoteKeySet{
+		url:             url,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		refreshInterval: 1 * time.Hour,
+		minRefreshDelay: 30 * time.Second,
+		logger:          log,
+	}
+}
+
+// Key returns the public key with the given key ID, refreshing the set if needed
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mutex.RLock()
+	keys := s.keys
+	stale := time.Since(s.lastFetched) > s.refreshInterval
+	s.mutex.RUnlock()
+
+	if keys != nil && !stale {
+		if key, err := lookupKey(keys, kid); err == nil {
+			return key, nil
+		}
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		// Fall back to the keys we already have if the refresh failed
+		if keys != nil {
+			return lookupKey(keys, kid)
+		}
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return lookupKey(s.keys, kid)
+}
+
+// refresh fetches the JWKS document, throttled to avoid hammering the issuer
+func (s *RemoteKeySet) refresh(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.keys != nil && time.Since(s.lastFetched) < s.minRefreshDelay {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		s.logger.Error("Failed to fetch JWKS", map[string]interface{}{
+			"url":   s.url,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		s.logger.Error("JWKS endpoint returned error status", map[string]interface{}{
+			"url":         s.url,
+			"status_code": resp.StatusCode,
+		})
+		return fmt.Errorf("JWKS endpoint returned error status: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS response: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.lastFetched = time.Now()
+
+	s.logger.Info("JWKS refreshed", map[string]interface{}{
+		"url":       s.url,
+		"key_count": len(keys),
+	})
+
+	return nil
+}
+
+// lookupKey finds a key by ID. An empty ID is accepted when the set holds a single key.
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, kid)
+}
@@ -0,0 +1,55 @@
+// Package auth internal/infrastructure/auth/policy.go
+package auth
+
+// Policy maps routes to the roles required to access them. Routes are
+// identified by method and path template, e.g. "POST /transactions".
+type Policy struct {
+	public map[string]bool
+	roles  map[string][]string
+}
+
+// NewPolicy creates an empty policy. Routes without a rule require an
+// authenticated principal but no specific role.
+func NewPolicy() *Policy {
+	return &Policy{
+		public: make(map[string]bool),
+		roles:  make(map[string][]string),
+	}
+}
+
+// Public marks a route as accessible without authentication
+func (p *Policy) Public(route string) *Policy {
+	p.public[route] = true
+	return p
+}
+
+// Require sets the roles for a route. A principal needs any one of them.
+func (p *Policy) Require(route string, roles ...string) *Policy {
+	p.roles[route] = roles
+	return p
+}
+
+// IsPublic reports whether the route can be accessed without authentication
+func (p *Policy) IsPublic(route string) bool {
+	return p.public[route]
+}
+
+// Authorize reports whether the principal may access the route
+func (p *Policy) Authorize(route string, principal *Principal) bool {
+	required, ok := p.roles[route]
+	if !ok || len(required) == 0 {
+		return true
+	}
+
+	for _, role := range required {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredRoles returns the roles configured for a route
+func (p *Policy) RequiredRoles(route string) []string {
+	return p.roles[route]
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -102,3 +103,18 @@ func (c *ExchangeRateCache) CleanExpired() int {
 
 	return count
 }
+
+// RunJanitor removes expired entries at the given interval until ctx is cancelled
+func (c *ExchangeRateCache) RunJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CleanExpired()
+		}
+	}
+}
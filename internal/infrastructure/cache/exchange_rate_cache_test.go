@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -55,3 +56,28 @@ func TestExchangeRateCache(t *testing.T) {
 	cache.Clear()
 	assert.Equal(t, 0, cache.Size())
 }
+
+func TestRunJanitor(t *testing.T) {
+	cache := NewExchangeRateCache()
+	cache.SetExpiration(time.Millisecond)
+
+	date := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	cache.Put(&entity.ExchangeRate{Currency: "EUR", Date: date, Rate: 0.85}, date)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cache.RunJanitor(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return cache.Size() == 0 }, time.Second, 5*time.Millisecond)
+
+	// The janitor returns once the context is cancelled
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not stop after cancellation")
+	}
+}
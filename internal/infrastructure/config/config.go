@@ -0,0 +1,571 @@
+// Package config internal/infrastructure/config/config.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/ratelimit"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+)
+
+// Config is the complete application configuration
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Treasury  TreasuryConfig  `yaml:"treasury"`
+	Log       LogConfig       `yaml:"log"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Tenancy   TenancyConfig   `yaml:"tenancy"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Health    HealthConfig    `yaml:"health"`
+	Retention RetentionConfig `yaml:"retention"`
+	Outbox    OutboxConfig    `yaml:"outbox"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks"`
+	Stream    StreamConfig    `yaml:"stream"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	GraphQL   GraphQLConfig   `yaml:"graphql"`
+	Reports   ReportsConfig   `yaml:"reports"`
+}
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Host              string        `yaml:"host"`
+	Port              int           `yaml:"port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`
+	MaxBodyBytes      int64         `yaml:"max_body_bytes"`
+}
+
+// Addr returns the listen address
+func (c ServerConfig) Addr() string {
+	return c.Host + ":" + strconv.Itoa(c.Port)
+}
+
+// DatabaseConfig holds the BadgerDB settings
+type DatabaseConfig struct {
+	Path       string `yaml:"path"`
+	SyncWrites bool   `yaml:"sync_writes"`
+}
+
+// TreasuryConfig holds the Treasury API client settings
+type TreasuryConfig struct {
+	BaseURL        string        `yaml:"base_url"`
+	Timeout        time.Duration `yaml:"timeout"`
+	MaxRetries     int           `yaml:"max_retries"`
+	LookbackMonths int           `yaml:"lookback_months"`
+	CacheTTL       time.Duration `yaml:"cache_ttl"`
+}
+
+// LogConfig holds the logger settings. Components overrides Level for named
+// components in "component=level" form. Backend selects the built-in JSON logger
+// or log/slog; Format applies to the slog backend only. Redact lists the
+// "field:action" rules applied to every entry, and RedactKey keys hashed values.
+// Async moves writes to a background goroutine with a BufferSize-entry buffer whose
+// Overflow policy is block or drop. Sampling is enabled when SampleInitial is set.
+type LogConfig struct {
+	Level            string        `yaml:"level"`
+	Components       string        `yaml:"components"`
+	Backend          string        `yaml:"backend"`
+	Format           string        `yaml:"format"`
+	Redact           string        `yaml:"redact"`
+	RedactKey        string        `yaml:"redact_key" secret:"true"`
+	Async            bool          `yaml:"async"`
+	BufferSize       int           `yaml:"buffer_size"`
+	Overflow         string        `yaml:"overflow"`
+	SampleInitial    int           `yaml:"sample_initial"`
+	SampleThereafter int           `yaml:"sample_thereafter"`
+	SampleInterval   time.Duration `yaml:"sample_interval"`
+}
+
+// AuthConfig holds the JWT authentication settings. Authentication is enabled
+// when either JWKSFile or JWKSURL is set; running without it takes Disabled.
+type AuthConfig struct {
+	Disabled   bool          `yaml:"disabled"`
+	JWKSFile   string        `yaml:"jwks_file"`
+	JWKSURL    string        `yaml:"jwks_url" secret:"url"`
+	Issuer     string        `yaml:"issuer"`
+	Audience   string        `yaml:"audience"`
+	RolesClaim string        `yaml:"roles_claim"`
+	Leeway     time.Duration `yaml:"leeway"`
+}
+
+// Enabled reports whether a JWKS source has been configured
+func (c AuthConfig) Enabled() bool {
+	return c.JWKSFile != "" || c.JWKSURL != ""
+}
+
+// TenancyConfig holds the multi-tenant settings. Tenants may be listed inline
+// or loaded from a separate JSON file.
+type TenancyConfig struct {
+	Claim   string          `yaml:"claim"`
+	File    string          `yaml:"file"`
+	Tenants []tenant.Config `yaml:"tenants"`
+}
+
+// Registry builds the tenant registry from the inline list or the tenants file.
+// Without either the service runs in single-tenant mode.
+func (c TenancyConfig) Registry() (*tenant.Registry, error) {
+	if c.File != "" {
+		return tenant.LoadRegistryFile(c.File)
+	}
+	return tenant.NewRegistry(c.Tenants...)
+}
+
+// RateLimitConfig holds the per-route-class limits in "rate:burst" form
+type RateLimitConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Write   string `yaml:"write"`
+	Read    string `yaml:"read"`
+	Convert string `yaml:"convert"`
+}
+
+// MetricsConfig holds the Prometheus endpoint settings
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+// TracingConfig holds the OpenTelemetry span export settings
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint" secret:"url"`
+	File        string  `yaml:"file"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// HealthConfig holds the readiness check settings
+type HealthConfig struct {
+	CheckTimeout     time.Duration `yaml:"check_timeout"`
+	TreasuryInterval time.Duration `yaml:"treasury_interval"`
+	RateMaxAge       time.Duration `yaml:"rate_max_age"`
+	MinFreeBytes     int64         `yaml:"min_free_bytes"`
+}
+
+// RetentionConfig holds the transaction retention settings. Days applies to
+// tenants that do not set retention_days. ExpiredStatus chooses whether lookups of
+// expired transactions answer gone (410) or not_found (404); gone is reported for
+// GoneDays after expiry. Expired records are purged every PurgeInterval.
+type RetentionConfig struct {
+	Days          int           `yaml:"days"`
+	ExpiredStatus string        `yaml:"expired_status"`
+	GoneDays      int           `yaml:"gone_days"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// Period returns the service-wide retention period
+func (c RetentionConfig) Period() time.Duration {
+	return time.Duration(c.Days) * 24 * time.Hour
+}
+
+// GonePeriod returns how long after expiry a transaction is reported as gone
+func (c RetentionConfig) GonePeriod() time.Duration {
+	return time.Duration(c.GoneDays) * 24 * time.Hour
+}
+
+// OutboxConfig holds the event outbox settings. Each new transaction is recorded
+// as a TransactionCreated event and relayed to the webhook URL and the NDJSON
+// file that are set; with neither set and webhooks disabled no events are
+// recorded. Failed deliveries are retried after RetryInitial, doubling up to
+// RetryMax.
+type OutboxConfig struct {
+	WebhookURL     string        `yaml:"webhook_url" secret:"url"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout"`
+	File           string        `yaml:"file"`
+	PollInterval   time.Duration `yaml:"poll_interval"`
+	BatchSize      int           `yaml:"batch_size"`
+	RetryInitial   time.Duration `yaml:"retry_initial"`
+	RetryMax       time.Duration `yaml:"retry_max"`
+}
+
+// Enabled reports whether any event sink is configured
+func (c OutboxConfig) Enabled() bool {
+	return c.WebhookURL != "" || c.File != ""
+}
+
+// WebhooksConfig holds the settings of tenant webhook subscriptions. Events are
+// fanned out from the outbox to each subscription and sent with a signature;
+// failed deliveries are retried after RetryInitial, doubling up to RetryMax, and
+// dead-lettered after MaxAttempts. Delivered webhooks are kept for
+// HistoryRetention.
+type WebhooksConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	Timeout          time.Duration `yaml:"timeout"`
+	PollInterval     time.Duration `yaml:"poll_interval"`
+	BatchSize        int           `yaml:"batch_size"`
+	RetryInitial     time.Duration `yaml:"retry_initial"`
+	RetryMax         time.Duration `yaml:"retry_max"`
+	MaxAttempts      int           `yaml:"max_attempts"`
+	HistoryRetention time.Duration `yaml:"history_retention"`
+	// AllowPrivateNetworks lets subscriptions reach loopback and private
+	// addresses; leave it off unless every tenant is trusted
+	AllowPrivateNetworks bool `yaml:"allow_private_networks"`
+}
+
+// StreamConfig holds the settings of the transaction event stream. Idle streams
+// are sent a heartbeat every HeartbeatInterval so proxies keep them open.
+type StreamConfig struct {
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+}
+
+// GRPCConfig holds the settings of the gRPC API, which listens on Port of the
+// server's host
+type GRPCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
+// GraphQLConfig holds the settings of the GraphQL endpoint. MaxDepth limits the
+// nesting of queries.
+type GraphQLConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	MaxDepth int  `yaml:"max_depth"`
+}
+
+// ReportsConfig holds the settings of summary reports. Each month's summary is
+// cached for at most CacheTTL, and at most CacheSize summaries are cached.
+type ReportsConfig struct {
+	CacheTTL  time.Duration `yaml:"cache_ttl"`
+	CacheSize int           `yaml:"cache_size"`
+}
+
+// GRPCAddr returns the gRPC listen address
+func (c *Config) GRPCAddr() string {
+	return c.Server.Host + ":" + strconv.Itoa(c.GRPC.Port)
+}
+
+// PublishEvents reports whether domain events are recorded in the outbox, which
+// they are while an outbox sink is configured or webhooks are enabled
+func (c *Config) PublishEvents() bool {
+	return c.Outbox.Enabled() || c.Webhooks.Enabled
+}
+
+// Default returns the configuration used when nothing else is specified
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			MaxBodyBytes:      1 << 20,
+		},
+		Database: DatabaseConfig{
+			Path: "./data",
+		},
+		Treasury: TreasuryConfig{
+			BaseURL:        "https://api.fiscaldata.treasury.gov/services/api/fiscal_service",
+			Timeout:        10 * time.Second,
+			MaxRetries:     3,
+			LookbackMonths: 6,
+			CacheTTL:       24 * time.Hour,
+		},
+		Log: LogConfig{
+			Level:          "INFO",
+			Backend:        "builtin",
+			Format:         "json",
+			Redact:         logger.DefaultRedactionRules,
+			Async:          true,
+			BufferSize:     8192,
+			Overflow:       "block",
+			SampleInterval: time.Second,
+		},
+		Auth: AuthConfig{
+			RolesClaim: "roles",
+			Leeway:     30 * time.Second,
+		},
+		Tenancy: TenancyConfig{
+			Claim: "tenant",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Write:   "5:10",
+			Read:    "20:40",
+			Convert: "2:5",
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			Endpoint:    "http://localhost:4318",
+			File:        "./traces.json",
+			ServiceName: "wex-tag-transaction-system",
+			SampleRatio: 1,
+		},
+		Health: HealthConfig{
+			CheckTimeout:     2 * time.Second,
+			TreasuryInterval: 30 * time.Second,
+			RateMaxAge:       15 * time.Minute,
+			MinFreeBytes:     100 << 20,
+		},
+		Retention: RetentionConfig{
+			Days:          365,
+			ExpiredStatus: "gone",
+			GoneDays:      90,
+			PurgeInterval: time.Hour,
+		},
+		Outbox: OutboxConfig{
+			WebhookTimeout: 5 * time.Second,
+			PollInterval:   time.Second,
+			BatchSize:      100,
+			RetryInitial:   time.Second,
+			RetryMax:       5 * time.Minute,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:          true,
+			Timeout:          10 * time.Second,
+			PollInterval:     time.Second,
+			BatchSize:        100,
+			RetryInitial:     10 * time.Second,
+			RetryMax:         time.Hour,
+			MaxAttempts:      8,
+			HistoryRetention: 7 * 24 * time.Hour,
+		},
+		Stream: StreamConfig{
+			HeartbeatInterval: 15 * time.Second,
+		},
+		GRPC: GRPCConfig{
+			Enabled: true,
+			Port:    9090,
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:  true,
+			MaxDepth: 10,
+		},
+		Reports: ReportsConfig{
+			CacheTTL:  time.Hour,
+			CacheSize: 10000,
+		},
+	}
+}
+
+// Validate checks the configuration and reports every problem found
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.GRPC.Enabled {
+		if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+			add("grpc.port must be between 1 and 65535, got %d", c.GRPC.Port)
+		} else if c.GRPC.Port == c.Server.Port {
+			add("grpc.port must differ from server.port")
+		}
+	}
+	if c.GraphQL.Enabled && c.GraphQL.MaxDepth < 1 {
+		add("graphql.max_depth must be positive, got %d", c.GraphQL.MaxDepth)
+	}
+	if c.Reports.CacheTTL < 0 {
+		add("reports.cache_ttl must not be negative")
+	}
+	if c.Reports.CacheSize < 1 {
+		add("reports.cache_size must be positive, got %d", c.Reports.CacheSize)
+	}
+	for name, d := range map[string]time.Duration{
+		"server.read_timeout":        c.Server.ReadTimeout,
+		"server.read_header_timeout": c.Server.ReadHeaderTimeout,
+		"server.write_timeout":       c.Server.WriteTimeout,
+		"server.idle_timeout":        c.Server.IdleTimeout,
+		"server.shutdown_timeout":    c.Server.ShutdownTimeout,
+		"treasury.timeout":           c.Treasury.Timeout,
+		"health.check_timeout":       c.Health.CheckTimeout,
+		"health.treasury_interval":   c.Health.TreasuryInterval,
+		"health.rate_max_age":        c.Health.RateMaxAge,
+		"retention.purge_interval":   c.Retention.PurgeInterval,
+		"outbox.webhook_timeout":     c.Outbox.WebhookTimeout,
+		"outbox.poll_interval":       c.Outbox.PollInterval,
+		"outbox.retry_initial":       c.Outbox.RetryInitial,
+		"outbox.retry_max":           c.Outbox.RetryMax,
+		"webhooks.timeout":           c.Webhooks.Timeout,
+		"webhooks.poll_interval":     c.Webhooks.PollInterval,
+		"webhooks.retry_initial":     c.Webhooks.RetryInitial,
+		"webhooks.retry_max":         c.Webhooks.RetryMax,
+		"webhooks.history_retention": c.Webhooks.HistoryRetention,
+		"stream.heartbeat_interval":  c.Stream.HeartbeatInterval,
+	} {
+		if d <= 0 {
+			add("%s must be positive, got %s", name, d)
+		}
+	}
+	if c.Server.MaxHeaderBytes <= 0 {
+		add("server.max_header_bytes must be positive")
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		add("server.max_body_bytes must be positive")
+	}
+
+	if c.Health.MinFreeBytes < 0 {
+		add("health.min_free_bytes must not be negative")
+	}
+
+	if c.Retention.Days < 1 {
+		add("retention.days must be at least 1, got %d", c.Retention.Days)
+	}
+	switch c.Retention.ExpiredStatus {
+	case "gone", "not_found":
+	default:
+		add("retention.expired_status must be gone or not_found, got %q", c.Retention.ExpiredStatus)
+	}
+	if c.Retention.GoneDays < 0 {
+		add("retention.gone_days must not be negative")
+	}
+
+	if c.Outbox.WebhookURL != "" {
+		if u, err := url.Parse(c.Outbox.WebhookURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("outbox.webhook_url must be an absolute URL, got %q", c.Outbox.WebhookURL)
+		}
+	}
+	if c.Outbox.BatchSize < 1 {
+		add("outbox.batch_size must be at least 1, got %d", c.Outbox.BatchSize)
+	}
+	if c.Outbox.RetryMax < c.Outbox.RetryInitial {
+		add("outbox.retry_max must not be less than outbox.retry_initial")
+	}
+
+	if c.Webhooks.BatchSize < 1 {
+		add("webhooks.batch_size must be at least 1, got %d", c.Webhooks.BatchSize)
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		add("webhooks.max_attempts must be at least 1, got %d", c.Webhooks.MaxAttempts)
+	}
+	if c.Webhooks.RetryMax < c.Webhooks.RetryInitial {
+		add("webhooks.retry_max must not be less than webhooks.retry_initial")
+	}
+
+	if c.Database.Path == "" {
+		add("database.path is required")
+	}
+
+	if u, err := url.Parse(c.Treasury.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		add("treasury.base_url must be an absolute URL, got %q", c.Treasury.BaseURL)
+	}
+	if c.Treasury.MaxRetries < 1 {
+		add("treasury.max_retries must be at least 1, got %d", c.Treasury.MaxRetries)
+	}
+	if c.Treasury.LookbackMonths < 1 {
+		add("treasury.lookback_months must be at least 1, got %d", c.Treasury.LookbackMonths)
+	}
+	if c.Treasury.CacheTTL < 0 {
+		add("treasury.cache_ttl must not be negative")
+	}
+
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		add("log.level must be one of DEBUG, INFO, WARN, ERROR, FATAL, got %q", c.Log.Level)
+	}
+	if _, err := logger.ParseComponentLevels(c.Log.Components); err != nil {
+		add("log.components: %v", err)
+	}
+	switch c.Log.Backend {
+	case "builtin", "slog":
+	default:
+		add("log.backend must be builtin or slog, got %q", c.Log.Backend)
+	}
+	switch c.Log.Format {
+	case "json":
+	case "text":
+		if c.Log.Backend != "slog" {
+			add("log.format text requires log.backend slog")
+		}
+	default:
+		add("log.format must be json or text, got %q", c.Log.Format)
+	}
+	if rules, err := logger.ParseRedactionRules(c.Log.Redact); err != nil {
+		add("log.redact: %v", err)
+	} else if rules.Hashes() && c.Log.RedactKey == "" {
+		// Unkeyed hashes of short values such as descriptions can be reversed by guessing
+		add("log.redact_key is required when log.redact hashes a field")
+	}
+	if c.Log.Async && c.Log.BufferSize <= 0 {
+		add("log.buffer_size must be positive")
+	}
+	switch logger.OverflowPolicy(c.Log.Overflow) {
+	case logger.OverflowBlock, logger.OverflowDrop:
+	default:
+		add("log.overflow must be block or drop, got %q", c.Log.Overflow)
+	}
+	if c.Log.SampleInitial < 0 || c.Log.SampleThereafter < 0 {
+		add("log.sample_initial and log.sample_thereafter must not be negative")
+	}
+	if c.Log.SampleInitial > 0 && c.Log.SampleInterval <= 0 {
+		add("log.sample_interval must be positive when sampling is enabled")
+	}
+
+	switch {
+	case c.Auth.JWKSFile != "" && c.Auth.JWKSURL != "":
+		add("auth.jwks_file and auth.jwks_url are mutually exclusive")
+	case c.Auth.Disabled && c.Auth.Enabled():
+		add("auth.disabled cannot be set with a JWKS source")
+	case !c.Auth.Disabled && !c.Auth.Enabled():
+		// Every route, admin ones included, would be served to anyone
+		add("auth.jwks_file or auth.jwks_url is required unless auth.disabled is set")
+	}
+	if c.Auth.JWKSURL != "" {
+		if u, err := url.Parse(c.Auth.JWKSURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("auth.jwks_url must be an absolute URL")
+		}
+	}
+	if c.Auth.Leeway < 0 {
+		add("auth.leeway must not be negative")
+	}
+
+	if c.Tenancy.File != "" && len(c.Tenancy.Tenants) > 0 {
+		add("tenancy.file and tenancy.tenants are mutually exclusive")
+	}
+	if _, err := tenant.NewRegistry(c.Tenancy.Tenants...); err != nil {
+		add("tenancy.tenants: %v", err)
+	}
+
+	for name, value := range map[string]string{
+		"rate_limit.write":   c.RateLimit.Write,
+		"rate_limit.read":    c.RateLimit.Read,
+		"rate_limit.convert": c.RateLimit.Convert,
+	} {
+		if _, err := ratelimit.ParseLimit(value); err != nil {
+			add("%s: %v", name, err)
+		}
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		add("metrics.path must start with /, got %q", c.Metrics.Path)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			add("tracing.endpoint must be an absolute URL")
+		}
+	case "file":
+		if c.Tracing.File == "" {
+			add("tracing.file is required for the file exporter")
+		}
+	default:
+		add("tracing.exporter must be one of none, otlp, stdout, file, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	return errors.Join(errs...)
+}
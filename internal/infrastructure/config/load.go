@@ -0,0 +1,269 @@
+// Package config internal/infrastructure/config/load.go
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// binding connects a configuration field to an environment variable and a command-line flag
+type binding struct {
+	env    string
+	flag   string
+	usage  string
+	target interface{}
+}
+
+// bindings lists every setting that can be overridden from the environment or flags
+func bindings(c *Config) []binding {
+	return []binding{
+		{"SERVER_HOST", "host", "listen host", &c.Server.Host},
+		{"PORT", "port", "listen port", &c.Server.Port},
+		{"SERVER_READ_TIMEOUT", "read-timeout", "maximum time to read a request", &c.Server.ReadTimeout},
+		{"SERVER_READ_HEADER_TIMEOUT", "read-header-timeout", "maximum time to read request headers", &c.Server.ReadHeaderTimeout},
+		{"SERVER_WRITE_TIMEOUT", "write-timeout", "maximum time to write a response", &c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", "idle-timeout", "keep-alive idle timeout", &c.Server.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", "shutdown-timeout", "time allowed to drain requests on shutdown", &c.Server.ShutdownTimeout},
+		{"SERVER_MAX_HEADER_BYTES", "max-header-bytes", "maximum request header size", &c.Server.MaxHeaderBytes},
+		{"SERVER_MAX_BODY_BYTES", "max-body-bytes", "maximum request body size", &c.Server.MaxBodyBytes},
+
+		{"DB_PATH", "db-path", "BadgerDB data directory", &c.Database.Path},
+		{"DB_SYNC_WRITES", "db-sync-writes", "sync every write to disk", &c.Database.SyncWrites},
+
+		{"TREASURY_BASE_URL", "treasury-base-url", "Treasury fiscal data API base URL", &c.Treasury.BaseURL},
+		{"TREASURY_TIMEOUT", "treasury-timeout", "Treasury API request timeout", &c.Treasury.Timeout},
+		{"TREASURY_MAX_RETRIES", "treasury-max-retries", "Treasury API attempts per lookup", &c.Treasury.MaxRetries},
+		{"TREASURY_LOOKBACK_MONTHS", "treasury-lookback-months", "months before the purchase date a rate may come from", &c.Treasury.LookbackMonths},
+		{"TREASURY_CACHE_TTL", "treasury-cache-ttl", "exchange rate cache expiry", &c.Treasury.CacheTTL},
+
+		{"LOG_LEVEL", "log-level", "log level (DEBUG, INFO, WARN, ERROR, FATAL)", &c.Log.Level},
+		{"LOG_COMPONENTS", "log-components", "per-component log levels (api=DEBUG,...)", &c.Log.Components},
+		{"LOG_BACKEND", "log-backend", "logger implementation (builtin, slog)", &c.Log.Backend},
+		{"LOG_FORMAT", "log-format", "slog output format (json, text)", &c.Log.Format},
+		{"LOG_REDACT", "log-redact", "log field redaction rules (field:mask|hash|drop,...)", &c.Log.Redact},
+		{"LOG_REDACT_KEY", "log-redact-key", "key for hashed log fields", &c.Log.RedactKey},
+		{"LOG_ASYNC", "log-async", "write logs from a background goroutine", &c.Log.Async},
+		{"LOG_BUFFER_SIZE", "log-buffer-size", "entries buffered by the async writer", &c.Log.BufferSize},
+		{"LOG_OVERFLOW", "log-overflow", "full buffer policy (block, drop)", &c.Log.Overflow},
+		{"LOG_SAMPLE_INITIAL", "log-sample-initial", "debug/info entries per message per interval before sampling (0 disables)", &c.Log.SampleInitial},
+		{"LOG_SAMPLE_THEREAFTER", "log-sample-thereafter", "write every Nth entry after the initial ones (0 drops them)", &c.Log.SampleThereafter},
+		{"LOG_SAMPLE_INTERVAL", "log-sample-interval", "sampling interval", &c.Log.SampleInterval},
+
+		{"AUTH_DISABLED", "auth-disabled", "serve every route without authentication", &c.Auth.Disabled},
+		{"AUTH_JWKS_FILE", "auth-jwks-file", "path to a local JWKS document", &c.Auth.JWKSFile},
+		{"AUTH_JWKS_URL", "auth-jwks-url", "URL of a JWKS document", &c.Auth.JWKSURL},
+		{"AUTH_ISSUER", "auth-issuer", "expected token issuer", &c.Auth.Issuer},
+		{"AUTH_AUDIENCE", "auth-audience", "expected token audience", &c.Auth.Audience},
+		{"AUTH_ROLES_CLAIM", "auth-roles-claim", "claim holding the granted roles", &c.Auth.RolesClaim},
+		{"AUTH_LEEWAY", "auth-leeway", "clock skew tolerated when validating tokens", &c.Auth.Leeway},
+
+		{"TENANT_CLAIM", "tenant-claim", "token claim holding the tenant ID", &c.Tenancy.Claim},
+		{"TENANTS_FILE", "tenants-file", "JSON file listing the tenants", &c.Tenancy.File},
+
+		{"RATE_LIMIT_ENABLED", "rate-limit-enabled", "enable per-client rate limiting", &c.RateLimit.Enabled},
+		{"RATE_LIMIT_WRITE", "rate-limit-write", "write route limit as rate:burst", &c.RateLimit.Write},
+		{"RATE_LIMIT_READ", "rate-limit-read", "read route limit as rate:burst", &c.RateLimit.Read},
+		{"RATE_LIMIT_CONVERT", "rate-limit-convert", "conversion route limit as rate:burst", &c.RateLimit.Convert},
+
+		{"METRICS_ENABLED", "metrics-enabled", "expose Prometheus metrics", &c.Metrics.Enabled},
+		{"METRICS_PATH", "metrics-path", "path of the Prometheus metrics endpoint", &c.Metrics.Path},
+
+		{"TRACING_EXPORTER", "tracing-exporter", "span exporter (none, otlp, stdout, file)", &c.Tracing.Exporter},
+		{"TRACING_ENDPOINT", "tracing-endpoint", "OTLP/HTTP collector URL", &c.Tracing.Endpoint},
+		{"TRACING_FILE", "tracing-file", "span output file for the file exporter", &c.Tracing.File},
+		{"TRACING_SERVICE_NAME", "tracing-service-name", "service name reported in spans", &c.Tracing.ServiceName},
+		{"TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "fraction of new traces to sample", &c.Tracing.SampleRatio},
+
+		{"HEALTH_CHECK_TIMEOUT", "health-check-timeout", "time limit for each readiness check", &c.Health.CheckTimeout},
+		{"HEALTH_TREASURY_INTERVAL", "health-treasury-interval", "how long a Treasury reachability result is reused", &c.Health.TreasuryInterval},
+		{"HEALTH_RATE_MAX_AGE", "health-rate-max-age", "how long rate lookups may fail before readiness degrades", &c.Health.RateMaxAge},
+		{"HEALTH_MIN_FREE_BYTES", "health-min-free-bytes", "free disk space required under the database path", &c.Health.MinFreeBytes},
+
+		{"RETENTION_DAYS", "retention-days", "days transactions are kept unless their tenant sets retention_days", &c.Retention.Days},
+		{"RETENTION_EXPIRED_STATUS", "retention-expired-status", "response for expired transactions (gone, not_found)", &c.Retention.ExpiredStatus},
+		{"RETENTION_GONE_DAYS", "retention-gone-days", "days after expiry a transaction is reported as gone", &c.Retention.GoneDays},
+		{"RETENTION_PURGE_INTERVAL", "retention-purge-interval", "interval between purges of expired transactions", &c.Retention.PurgeInterval},
+
+		{"OUTBOX_WEBHOOK_URL", "outbox-webhook-url", "URL events are POSTed to", &c.Outbox.WebhookURL},
+		{"OUTBOX_WEBHOOK_TIMEOUT", "outbox-webhook-timeout", "time limit for each webhook delivery", &c.Outbox.WebhookTimeout},
+		{"OUTBOX_FILE", "outbox-file", "NDJSON file events are appended to", &c.Outbox.File},
+		{"OUTBOX_POLL_INTERVAL", "outbox-poll-interval", "interval between reads of the outbox", &c.Outbox.PollInterval},
+		{"OUTBOX_BATCH_SIZE", "outbox-batch-size", "most events relayed per read", &c.Outbox.BatchSize},
+		{"OUTBOX_RETRY_INITIAL", "outbox-retry-initial", "delay before the first redelivery of a failed event", &c.Outbox.RetryInitial},
+		{"OUTBOX_RETRY_MAX", "outbox-retry-max", "longest delay between redeliveries", &c.Outbox.RetryMax},
+
+		{"WEBHOOKS_ENABLED", "webhooks-enabled", "let tenants subscribe to events with webhooks", &c.Webhooks.Enabled},
+		{"WEBHOOKS_TIMEOUT", "webhooks-timeout", "time limit for each webhook request", &c.Webhooks.Timeout},
+		{"WEBHOOKS_POLL_INTERVAL", "webhooks-poll-interval", "interval between reads of the webhook queue", &c.Webhooks.PollInterval},
+		{"WEBHOOKS_BATCH_SIZE", "webhooks-batch-size", "most webhooks sent per read", &c.Webhooks.BatchSize},
+		{"WEBHOOKS_RETRY_INITIAL", "webhooks-retry-initial", "delay before the first retry of a failed webhook", &c.Webhooks.RetryInitial},
+		{"WEBHOOKS_RETRY_MAX", "webhooks-retry-max", "longest delay between webhook retries", &c.Webhooks.RetryMax},
+		{"WEBHOOKS_MAX_ATTEMPTS", "webhooks-max-attempts", "failed attempts before a webhook is dead-lettered", &c.Webhooks.MaxAttempts},
+		{"WEBHOOKS_HISTORY_RETENTION", "webhooks-history-retention", "how long delivered webhooks are kept", &c.Webhooks.HistoryRetention},
+		{"WEBHOOKS_ALLOW_PRIVATE_NETWORKS", "webhooks-allow-private-networks", "allow webhook URLs on loopback and private addresses", &c.Webhooks.AllowPrivateNetworks},
+
+		{"STREAM_HEARTBEAT_INTERVAL", "stream-heartbeat-interval", "interval between heartbeats on idle transaction streams", &c.Stream.HeartbeatInterval},
+
+		{"GRPC_ENABLED", "grpc-enabled", "serve the gRPC API", &c.GRPC.Enabled},
+		{"GRPC_PORT", "grpc-port", "gRPC listen port", &c.GRPC.Port},
+
+		{"GRAPHQL_ENABLED", "graphql-enabled", "serve the GraphQL endpoint", &c.GraphQL.Enabled},
+		{"GRAPHQL_MAX_DEPTH", "graphql-max-depth", "deepest nesting allowed in GraphQL queries", &c.GraphQL.MaxDepth},
+
+		{"REPORTS_CACHE_TTL", "reports-cache-ttl", "longest a month's report summary is cached (0 disables caching)", &c.Reports.CacheTTL},
+		{"REPORTS_CACHE_SIZE", "reports-cache-size", "most month summaries cached for reports", &c.Reports.CacheSize},
+	}
+}
+
+// Load builds the configuration with the following precedence, lowest first:
+// built-in defaults, the YAML config file, environment variables, command-line flags.
+// The config file is named by the -config flag or the CONFIG_FILE variable.
+func Load(name string, args []string, getenv func(string) string) (*Config, error) {
+	cfg, rest, err := LoadArgs(name, args, getenv)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("invalid command line: unexpected argument %q", rest[0])
+	}
+	return cfg, nil
+}
+
+// LoadArgs is Load for commands that take arguments of their own. Flags are parsed
+// up to the first non-flag argument, which is returned with everything after it.
+func LoadArgs(name string, args []string, getenv func(string) string) (*Config, []string, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	cfg := Default()
+	binds := bindings(cfg)
+
+	// Flags are parsed into a separate set of values so they can be applied last
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFile := fs.String("config", getenv("CONFIG_FILE"), "path to a YAML config file")
+	flagValues := make(map[string]*string, len(binds))
+	for _, b := range binds {
+		flagValues[b.flag] = fs.String(b.flag, "", fmt.Sprintf("%s (env %s)", b.usage, b.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("invalid command line: %w", err)
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, b := range binds {
+		if value, ok := lookupEnv(getenv, b.env); ok {
+			if err := setValue(b.target, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", b.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		value, ok := flagValues[f.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		for _, b := range binds {
+			if b.flag == f.Name {
+				if err := setValue(b.target, *value); err != nil {
+					flagErr = fmt.Errorf("invalid -%s: %w", f.Name, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// Usage writes the list of flags and their environment variables to w
+func Usage(w io.Writer) {
+	fmt.Fprintf(w, "  -config string\n\tpath to a YAML config file (env CONFIG_FILE)\n")
+	for _, b := range bindings(Default()) {
+		fmt.Fprintf(w, "  -%s\n\t%s (env %s)\n", b.flag, b.usage, b.env)
+	}
+}
+
+// loadFile merges a YAML (or JSON) document into cfg. Unknown keys are rejected.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// lookupEnv returns the value of an environment variable if it is set and non-empty
+func lookupEnv(getenv func(string) string, key string) (string, bool) {
+	value := getenv(key)
+	return value, value != ""
+}
+
+// setValue parses a string into the field pointed to by target
+func setValue(target interface{}, value string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*t = n
+	case *int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*t = n
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*t = f
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*t = b
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*t = d
+	default:
+		return fmt.Errorf("unsupported setting type %T", target)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package config internal/infrastructure/config/redact.go
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redacted replaces secret values in the printed configuration
+const redacted = "[REDACTED]"
+
+// Redacted returns the configuration as a map suitable for logging. Fields tagged
+// `secret:"true"` are replaced entirely; fields tagged `secret:"url"` keep the URL
+// but have their credentials and query values masked.
+func (c *Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(*c))
+}
+
+// redactStruct converts a struct into a map keyed by yaml field names
+func redactStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		out[name] = redactValue(v.Field(i), field.Tag.Get("secret"))
+	}
+
+	return out
+}
+
+// redactValue converts a single value, applying the secret policy to strings
+func redactValue(v reflect.Value, secret string) interface{} {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return redactStruct(v)
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = redactValue(v.Index(i), secret)
+		}
+		return items
+	case reflect.Map:
+		items := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			items[iter.Key().String()] = redactValue(iter.Value(), secret)
+		}
+		return items
+	case reflect.String:
+		return redactString(v.String(), secret)
+	default:
+		return v.Interface()
+	}
+}
+
+// redactString applies a secret policy to a string value
+func redactString(s, secret string) string {
+	if s == "" {
+		return s
+	}
+
+	switch secret {
+	case "true":
+		return redacted
+	case "url":
+		return redactURL(s)
+	default:
+		return s
+	}
+}
+
+// redactURL masks the password and query values of a URL
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return redacted
+	}
+
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, "xxxxx")
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
@@ -0,0 +1,241 @@
+// Package db internal/infrastructure/db/badger_audit_repository.go
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// auditEventKey builds the key of a tenant's audit event. Sequence numbers are
+// zero-padded so that key order is sequence order.
+func auditEventKey(tenantID string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("t:%s:audit:%020d", tenantID, sequence))
+}
+
+// auditHeadKey builds the key holding the last event of a tenant's audit chain
+func auditHeadKey(tenantID string) []byte {
+	return []byte("t:" + tenantID + ":audit-head")
+}
+
+// auditTransactionKey builds the key of the index entry linking a transaction to
+// one of its audit events
+func auditTransactionKey(tenantID, transactionID string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("t:%s:audit-tx:%s:%020d", tenantID, transactionID, sequence))
+}
+
+// auditTransactionPrefix is the key prefix of a transaction's audit index entries
+func auditTransactionPrefix(tenantID, transactionID string) []byte {
+	return []byte("t:" + tenantID + ":audit-tx:" + transactionID + ":")
+}
+
+// auditMu serialises audit appends, whether made by BadgerAuditRepository or with a
+// transaction by BadgerTransactionRepository, so each one extends the chain head
+// it read
+var auditMu sync.Mutex
+
+// appendAuditEvent adds event to the end of its tenant's chain, setting its
+// tenant, sequence number and hashes. Callers hold auditMu.
+func appendAuditEvent(txn *badger.Txn, tenantID string, event *entity.AuditEvent, now time.Time) error {
+	var head entity.AuditEvent
+	item, err := txn.Get(auditHeadKey(tenantID))
+	switch {
+	case errors.Is(err, badger.ErrKeyNotFound):
+		// First event of the tenant's chain
+	case err != nil:
+		return err
+	default:
+		if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &head) }); err != nil {
+			return fmt.Errorf("failed to decode audit chain head: %w", err)
+		}
+	}
+
+	event.TenantID = tenantID
+	event.Sequence = head.Sequence + 1
+	event.PrevHash = head.Hash
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = now.UTC()
+	}
+	event.Hash = event.ComputeHash()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	if err := txn.Set(auditEventKey(tenantID, event.Sequence), data); err != nil {
+		return err
+	}
+	if event.TransactionID != "" {
+		if err := txn.Set(auditTransactionKey(tenantID, event.TransactionID, event.Sequence), nil); err != nil {
+			return err
+		}
+	}
+	return txn.Set(auditHeadKey(tenantID), data)
+}
+
+// BadgerAuditRepository implements the audit repository interface using BadgerDB.
+// Events are written without a TTL and are never rewritten; neither retention nor
+// the purge job touches them.
+type BadgerAuditRepository struct {
+	db      *badger.DB
+	logger  logger.Logger
+	metrics metrics.Metrics
+	now     func() time.Time
+}
+
+// NewBadgerAuditRepository creates a new BadgerDB audit repository
+func NewBadgerAuditRepository(db *badger.DB, log logger.Logger, m metrics.Metrics) repository.AuditRepository {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return &BadgerAuditRepository{
+		db:      db,
+		logger:  log,
+		metrics: m,
+		now:     time.Now,
+	}
+}
+
+// observe records the latency of a database operation
+func (r *BadgerAuditRepository) observe(operation string, err error, start time.Time) {
+	r.metrics.ObserveDuration(metrics.DBOperationDuration, time.Since(start), map[string]string{
+		"operation": operation,
+		"outcome":   metrics.Outcome(err),
+	})
+}
+
+// Append adds an event to the end of the context tenant's chain
+func (r *BadgerAuditRepository) Append(ctx context.Context, event *entity.AuditEvent) error {
+	ctx, span := tracing.Start(ctx, "BadgerAuditRepository.Append")
+	defer span.End()
+
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	start := time.Now()
+	err := r.db.Update(func(txn *badger.Txn) error {
+		return appendAuditEvent(txn, tenantID, event, r.now())
+	})
+	r.observe("audit_append", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		log.Error("Failed to append audit event", map[string]interface{}{
+			"action":         event.Action,
+			"transaction_id": event.TransactionID,
+			"error":          err.Error(),
+		})
+		return fmt.Errorf("failed to append audit event: %w", err)
+	}
+
+	log.Debug("Audit event appended", map[string]interface{}{
+		"action":         event.Action,
+		"transaction_id": event.TransactionID,
+		"sequence":       event.Sequence,
+	})
+	return nil
+}
+
+// List calls fn for each event of the context's tenant in sequence order
+func (r *BadgerAuditRepository) List(ctx context.Context, fn func(*entity.AuditEvent) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerAuditRepository.List")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+	prefix := []byte("t:" + tenantID + ":audit:")
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true, PrefetchSize: 100})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			event, err := decodeAuditEvent(it.Item())
+			if err != nil {
+				return err
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("audit_list", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		return fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return nil
+}
+
+// ListByTransaction calls fn for each event recorded against a transaction of the
+// context's tenant in sequence order
+func (r *BadgerAuditRepository) ListByTransaction(ctx context.Context, transactionID string, fn func(*entity.AuditEvent) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerAuditRepository.ListByTransaction")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+	prefix := auditTransactionPrefix(tenantID, transactionID)
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			sequence, err := strconv.ParseUint(string(it.Item().Key()[len(prefix):]), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid audit index key %s: %w", it.Item().Key(), err)
+			}
+
+			item, err := txn.Get(auditEventKey(tenantID, sequence))
+			if err != nil {
+				return fmt.Errorf("failed to read audit event %d: %w", sequence, err)
+			}
+			event, err := decodeAuditEvent(item)
+			if err != nil {
+				return err
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("audit_list_by_transaction", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		return fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return nil
+}
+
+// decodeAuditEvent decodes a stored audit event
+func decodeAuditEvent(item *badger.Item) (*entity.AuditEvent, error) {
+	var event entity.AuditEvent
+	if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &event) }); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", item.Key(), err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,123 @@
+// internal/infrastructure/db/badger_audit_repository_test.go
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerAuditRepository(t *testing.T) {
+	badgerDB := openTestDB(t)
+	repo := NewBadgerAuditRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	// list returns the events passed to fn by a listing
+	list := func(listing func(fn func(*entity.AuditEvent) error) error) []*entity.AuditEvent {
+		var events []*entity.AuditEvent
+		require.NoError(t, listing(func(event *entity.AuditEvent) error {
+			events = append(events, event)
+			return nil
+		}))
+		return events
+	}
+
+	for _, id := range []string{"tx-1", "tx-2", "tx-1"} {
+		require.NoError(t, repo.Append(acmeCtx, &entity.AuditEvent{TransactionID: id, Action: entity.AuditTransactionConverted}))
+	}
+	require.NoError(t, repo.Append(context.Background(), &entity.AuditEvent{TransactionID: "tx-1", Action: entity.AuditTransactionCreated}))
+
+	t.Run("Events are chained in sequence", func(t *testing.T) {
+		events := list(func(fn func(*entity.AuditEvent) error) error { return repo.List(acmeCtx, fn) })
+		require.Len(t, events, 3)
+
+		prevHash := ""
+		for i, event := range events {
+			assert.Equal(t, uint64(i+1), event.Sequence)
+			assert.Equal(t, "acme", event.TenantID)
+			assert.Equal(t, prevHash, event.PrevHash)
+			assert.Equal(t, event.ComputeHash(), event.Hash)
+			assert.False(t, event.OccurredAt.IsZero())
+			prevHash = event.Hash
+		}
+	})
+
+	t.Run("Each tenant has its own chain", func(t *testing.T) {
+		events := list(func(fn func(*entity.AuditEvent) error) error { return repo.List(context.Background(), fn) })
+		require.Len(t, events, 1)
+		assert.Equal(t, uint64(1), events[0].Sequence)
+		assert.Empty(t, events[0].PrevHash)
+	})
+
+	t.Run("Events are listed by transaction", func(t *testing.T) {
+		events := list(func(fn func(*entity.AuditEvent) error) error { return repo.ListByTransaction(acmeCtx, "tx-1", fn) })
+		require.Len(t, events, 2)
+		assert.Equal(t, uint64(1), events[0].Sequence)
+		assert.Equal(t, uint64(3), events[1].Sequence)
+
+		assert.Empty(t, list(func(fn func(*entity.AuditEvent) error) error { return repo.ListByTransaction(acmeCtx, "tx", fn) }))
+	})
+
+	t.Run("Concurrent appends extend the chain", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, repo.Append(acmeCtx, &entity.AuditEvent{TransactionID: "tx-3", Action: entity.AuditTransactionConverted}))
+			}()
+		}
+		wg.Wait()
+
+		events := list(func(fn func(*entity.AuditEvent) error) error { return repo.List(acmeCtx, fn) })
+		require.Len(t, events, 23)
+		for i := 1; i < len(events); i++ {
+			assert.Equal(t, events[i-1].Hash, events[i].PrevHash)
+		}
+	})
+
+	t.Run("Events stored with a transaction extend the chain", func(t *testing.T) {
+		txRepo := NewBadgerTransactionRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+		tx := &entity.Transaction{ID: "tx-4", Description: "Fuel", Date: time.Now().UTC(), Amount: 10}
+		tx.CreatedAt = time.Now().UTC()
+		tx.CalculateTTL(entity.DefaultRetention)
+
+		_, err := txRepo.StoreWithEvents(acmeCtx, tx, nil, &entity.AuditEvent{TransactionID: "tx-4", Action: entity.AuditTransactionCreated})
+		require.NoError(t, err)
+		require.NoError(t, repo.Append(acmeCtx, &entity.AuditEvent{TransactionID: "tx-4", Action: entity.AuditTransactionConverted}))
+
+		events := list(func(fn func(*entity.AuditEvent) error) error { return repo.ListByTransaction(acmeCtx, "tx-4", fn) })
+		require.Len(t, events, 2)
+		assert.Equal(t, uint64(24), events[0].Sequence)
+		assert.Equal(t, entity.AuditTransactionCreated, events[0].Action)
+		assert.Equal(t, events[0].Hash, events[1].PrevHash)
+	})
+
+	t.Run("Events stored with a legal hold change commit with it", func(t *testing.T) {
+		txRepo := NewBadgerTransactionRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+		_, err := txRepo.Store(acmeCtx, &entity.Transaction{ID: "tx-5", Description: "Fuel", Date: time.Now().UTC(), Amount: 10})
+		require.NoError(t, err)
+		hold := entity.LegalHold{Reason: "Audit", PlacedBy: "auditor", PlacedAt: time.Now().UTC()}
+		placed := func() *entity.AuditEvent {
+			return &entity.AuditEvent{TransactionID: "tx-5", Action: entity.AuditLegalHoldPlaced}
+		}
+
+		_, err = txRepo.PlaceLegalHold(acmeCtx, "tx-5", hold, nil, placed())
+		require.NoError(t, err)
+		_, err = txRepo.PlaceLegalHold(acmeCtx, "tx-5", hold, nil, placed())
+		require.ErrorIs(t, err, repository.ErrLegalHoldExists)
+
+		// The rejected change recorded nothing
+		events := list(func(fn func(*entity.AuditEvent) error) error { return repo.ListByTransaction(acmeCtx, "tx-5", fn) })
+		require.Len(t, events, 1)
+		assert.Equal(t, entity.AuditLegalHoldPlaced, events[0].Action)
+	})
+}
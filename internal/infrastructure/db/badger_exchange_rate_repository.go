@@ -0,0 +1,140 @@
+// Package db internal/infrastructure/db/badger_exchange_rate_repository.go
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/dgraph-io/badger/v3"
+)
+
+const (
+	// exchangeRatePrefix is the key prefix for stored exchange rate lookups
+	exchangeRatePrefix = "rate:"
+
+	// recentRateWindow is how close to today a lookup date must be for its answer
+	// to be treated as provisional: a rate published later may still supersede it
+	recentRateWindow = 31 * 24 * time.Hour
+
+	// recentRateTTL is how long a provisional answer is kept
+	recentRateTTL = 24 * time.Hour
+)
+
+// exchangeRateKey builds the storage key for the rate that applies on date
+func exchangeRateKey(currency string, date time.Time) []byte {
+	return []byte(exchangeRatePrefix + strings.ToUpper(currency) + ":" + date.Format("2006-01-02"))
+}
+
+// BadgerExchangeRateRepository keeps the answers of another exchange rate
+// repository in BadgerDB, keyed by currency and lookup date, so conversions of
+// stored transactions survive restarts and do not depend on the Treasury API once
+// their rates are known.
+type BadgerExchangeRateRepository struct {
+	db     *badger.DB
+	next   repository.ExchangeRateRepository
+	logger logger.Logger
+	now    func() time.Time
+}
+
+// NewBadgerExchangeRateRepository creates a repository that reads through to next
+func NewBadgerExchangeRateRepository(db *badger.DB, next repository.ExchangeRateRepository, log logger.Logger) *BadgerExchangeRateRepository {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &BadgerExchangeRateRepository{
+		db:     db,
+		next:   next,
+		logger: log,
+		now:    time.Now,
+	}
+}
+
+// FindRate returns the stored rate for currency on date, asking the next
+// repository and storing its answer when there is none
+func (r *BadgerExchangeRateRepository) FindRate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error) {
+	log := logger.ForContext(ctx, r.logger)
+
+	rate, err := r.get(currency, date)
+	if err != nil {
+		return nil, err
+	}
+	if rate != nil {
+		log.Debug("Stored exchange rate found", map[string]interface{}{
+			"currency":  currency,
+			"date":      date.Format("2006-01-02"),
+			"rate_date": rate.Date.Format("2006-01-02"),
+		})
+		return rate, nil
+	}
+
+	rate, err = r.next.FindRate(ctx, currency, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Answers for recent dates may be superseded by a rate published later
+	var ttl time.Duration
+	if r.now().Sub(date) < recentRateWindow {
+		ttl = recentRateTTL
+	}
+	if err := r.put(currency, date, rate, ttl); err != nil {
+		// The rate is still usable; it will be fetched again next time
+		log.Warn("Failed to store exchange rate", map[string]interface{}{
+			"currency": currency,
+			"date":     date.Format("2006-01-02"),
+			"error":    err.Error(),
+		})
+	}
+
+	return rate, nil
+}
+
+// StoreRate saves a rate as the answer for its own record date
+func (r *BadgerExchangeRateRepository) StoreRate(ctx context.Context, rate *entity.ExchangeRate) error {
+	return r.put(rate.Currency, rate.Date, rate, 0)
+}
+
+// get reads a stored rate, returning nil if there is none
+func (r *BadgerExchangeRateRepository) get(currency string, date time.Time) (*entity.ExchangeRate, error) {
+	var rate *entity.ExchangeRate
+	err := r.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(exchangeRateKey(currency, date))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			rate = &entity.ExchangeRate{}
+			return json.Unmarshal(val, rate)
+		})
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored exchange rate: %w", err)
+	}
+	return rate, nil
+}
+
+// put stores a rate as the answer for currency on date. A zero ttl keeps it.
+func (r *BadgerExchangeRateRepository) put(currency string, date time.Time, rate *entity.ExchangeRate, ttl time.Duration) error {
+	data, err := json.Marshal(rate)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exchange rate: %w", err)
+	}
+
+	entry := badger.NewEntry(exchangeRateKey(currency, date), data)
+	if ttl > 0 {
+		entry = entry.WithTTL(ttl)
+	}
+	return r.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(entry)
+	})
+}
@@ -0,0 +1,90 @@
+// internal/infrastructure/db/badger_exchange_rate_repository_test.go
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerExchangeRateRepository(t *testing.T) {
+	badgerDB := openTestDB(t)
+	next := new(mocks.MockExchangeRateRepository)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	repo := NewBadgerExchangeRateRepository(badgerDB, next, log)
+	repo.now = func() time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+
+	// expiry returns the TTL Badger recorded for a stored lookup
+	expiry := func(currency string, date time.Time) uint64 {
+		var expiresAt uint64
+		require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+			item, err := txn.Get(exchangeRateKey(currency, date))
+			if err != nil {
+				return err
+			}
+			expiresAt = item.ExpiresAt()
+			return nil
+		}))
+		return expiresAt
+	}
+
+	t.Run("Lookups are stored and reused", func(t *testing.T) {
+		date := time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC)
+		expected := &entity.ExchangeRate{Currency: "EUR", Date: date.AddDate(0, 0, -14), Rate: 0.91}
+		next.On("FindRate", ctx, "EUR", date).Return(expected, nil).Once()
+
+		rate, err := repo.FindRate(ctx, "EUR", date)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Rate, rate.Rate)
+
+		// Served from Badger; the mock would fail a second call
+		rate, err = repo.FindRate(ctx, "eur", date)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Rate, rate.Rate)
+		assert.True(t, expected.Date.Equal(rate.Date))
+		assert.Zero(t, expiry("EUR", date), "settled rates are kept")
+
+		next.AssertExpectations(t)
+	})
+
+	t.Run("Recent lookups expire", func(t *testing.T) {
+		date := time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)
+		next.On("FindRate", ctx, "CAD", date).
+			Return(&entity.ExchangeRate{Currency: "CAD", Date: date, Rate: 1.35}, nil).Once()
+
+		_, err := repo.FindRate(ctx, "CAD", date)
+		require.NoError(t, err)
+		assert.NotZero(t, expiry("CAD", date))
+	})
+
+	t.Run("Failures are not stored", func(t *testing.T) {
+		date := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+		next.On("FindRate", ctx, "XYZ", date).Return(nil, errors.New("no rate")).Twice()
+
+		_, err := repo.FindRate(ctx, "XYZ", date)
+		assert.Error(t, err)
+		_, err = repo.FindRate(ctx, "XYZ", date)
+		assert.Error(t, err)
+
+		next.AssertExpectations(t)
+	})
+
+	t.Run("StoreRate keys by rate date", func(t *testing.T) {
+		date := time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.StoreRate(ctx, &entity.ExchangeRate{Currency: "GBP", Date: date, Rate: 0.83}))
+
+		rate, err := repo.FindRate(ctx, "GBP", date)
+		require.NoError(t, err)
+		assert.Equal(t, 0.83, rate.Rate)
+	})
+}
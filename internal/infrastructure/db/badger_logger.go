@@ -0,0 +1,49 @@
+// Package db internal/infrastructure/db/badger_logger.go
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// badgerLogger adapts logger.Logger to Badger's printf-style logger
+type badgerLogger struct {
+	logger logger.Logger
+}
+
+// NewBadgerLogger returns a Badger logger that writes into the application log
+// with a "component" field of "badger"
+func NewBadgerLogger(log logger.Logger) badger.Logger {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	return &badgerLogger{logger: log.WithField("component", "badger")}
+}
+
+// Errorf logs a Badger error
+func (b *badgerLogger) Errorf(format string, args ...interface{}) {
+	b.logger.Error(badgerMessage(format, args), nil)
+}
+
+// Warningf logs a Badger warning
+func (b *badgerLogger) Warningf(format string, args ...interface{}) {
+	b.logger.Warn(badgerMessage(format, args), nil)
+}
+
+// Infof logs a Badger informational message
+func (b *badgerLogger) Infof(format string, args ...interface{}) {
+	b.logger.Info(badgerMessage(format, args), nil)
+}
+
+// Debugf logs a Badger debug message
+func (b *badgerLogger) Debugf(format string, args ...interface{}) {
+	b.logger.Debug(badgerMessage(format, args), nil)
+}
+
+// badgerMessage formats a message, dropping the trailing newline Badger adds
+func badgerMessage(format string, args []interface{}) string {
+	return strings.TrimSpace(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,30 @@
+// internal/infrastructure/db/badger_logger_test.go
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewBadgerLogger(logger.NewJSONLogger(&buf, logger.InfoLevel))
+
+	log.Infof("Replaying file id: %d at offset: %d\n", 1, 0)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "Replaying file id: 1 at offset: 0", entry["message"])
+	assert.Equal(t, "badger", entry["component"])
+
+	// Badger debug output is subject to the application level
+	buf.Reset()
+	log.Debugf("Debug detail\n")
+	assert.Empty(t, buf.String())
+}
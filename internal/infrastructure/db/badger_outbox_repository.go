@@ -0,0 +1,190 @@
+// Package db internal/infrastructure/db/badger_outbox_repository.go
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// outboxPrefix is the key prefix of the outbox. The outbox is shared by all
+// tenants; each event carries its own tenant.
+const outboxPrefix = "outbox:"
+
+// outboxKey builds the key of an event in the outbox. The zero-padded time puts
+// events in the order they occurred.
+func outboxKey(event *entity.DomainEvent) []byte {
+	return []byte(fmt.Sprintf("%s%020d:%s", outboxPrefix, event.OccurredAt.UnixNano(), event.ID))
+}
+
+// newOutboxEntry returns the Badger entry adding event to the outbox
+func newOutboxEntry(event *entity.DomainEvent) (*badger.Entry, error) {
+	data, err := json.Marshal(entity.OutboxEntry{Event: *event})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return badger.NewEntry(outboxKey(event), data), nil
+}
+
+// BadgerOutboxRepository implements the outbox repository interface using BadgerDB
+type BadgerOutboxRepository struct {
+	db      *badger.DB
+	logger  logger.Logger
+	metrics metrics.Metrics
+}
+
+// NewBadgerOutboxRepository creates a new BadgerDB outbox repository
+func NewBadgerOutboxRepository(db *badger.DB, log logger.Logger, m metrics.Metrics) repository.OutboxRepository {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return &BadgerOutboxRepository{
+		db:      db,
+		logger:  log,
+		metrics: m,
+	}
+}
+
+// observe records the latency of a database operation
+func (r *BadgerOutboxRepository) observe(operation string, err error, start time.Time) {
+	r.metrics.ObserveDuration(metrics.DBOperationDuration, time.Since(start), map[string]string{
+		"operation": operation,
+		"outcome":   metrics.Outcome(err),
+	})
+}
+
+// Add adds events to the outbox for the context's tenant
+func (r *BadgerOutboxRepository) Add(ctx context.Context, events ...*entity.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "BadgerOutboxRepository.Add")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+	entries := make([]*badger.Entry, 0, len(events))
+	for _, event := range events {
+		event.TenantID = tenantID
+		entry, err := newOutboxEntry(event)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	start := time.Now()
+	err := r.db.Update(func(txn *badger.Txn) error {
+		for _, entry := range entries {
+			if err := txn.SetEntry(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("outbox_add", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to add events to outbox", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to add events to outbox: %w", err)
+	}
+	return nil
+}
+
+// Due returns up to limit entries whose next delivery attempt is due at now
+func (r *BadgerOutboxRepository) Due(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEntry, error) {
+	ctx, span := tracing.Start(ctx, "BadgerOutboxRepository.Due")
+	defer span.End()
+
+	var due []*entity.OutboxEntry
+	prefix := []byte(outboxPrefix)
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true, PrefetchSize: 100})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix) && len(due) < limit; it.Next() {
+			var entry entity.OutboxEntry
+			if err := it.Item().Value(func(val []byte) error { return json.Unmarshal(val, &entry) }); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", it.Item().Key(), err)
+			}
+			if entry.NextAttemptAt.After(now) {
+				continue
+			}
+			due = append(due, &entry)
+		}
+		return nil
+	})
+	r.observe("outbox_due", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to read outbox", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	return due, nil
+}
+
+// Update saves an entry's delivery state
+func (r *BadgerOutboxRepository) Update(ctx context.Context, entry *entity.OutboxEntry) error {
+	ctx, span := tracing.Start(ctx, "BadgerOutboxRepository.Update")
+	defer span.End()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox entry: %w", err)
+	}
+
+	start := time.Now()
+	err = r.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(outboxKey(&entry.Event), data)
+	})
+	r.observe("outbox_update", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to update outbox entry", map[string]interface{}{
+			"event_id": entry.Event.ID,
+			"error":    err.Error(),
+		})
+		return fmt.Errorf("failed to update outbox entry: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an entry from the outbox
+func (r *BadgerOutboxRepository) Delete(ctx context.Context, entry *entity.OutboxEntry) error {
+	ctx, span := tracing.Start(ctx, "BadgerOutboxRepository.Delete")
+	defer span.End()
+
+	start := time.Now()
+	err := r.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(outboxKey(&entry.Event))
+	})
+	r.observe("outbox_delete", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to delete outbox entry", map[string]interface{}{
+			"event_id": entry.Event.ID,
+			"error":    err.Error(),
+		})
+		return fmt.Errorf("failed to delete outbox entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// internal/infrastructure/db/badger_outbox_repository_test.go
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerOutboxRepository(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	txRepo := NewBadgerTransactionRepository(badgerDB, log, nil)
+	repo := NewBadgerOutboxRepository(badgerDB, log, nil)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// store saves a transaction together with its created event
+	store := func(id string, createdAt time.Time) {
+		tx := &entity.Transaction{
+			ID:          id,
+			Description: "Fuel",
+			Date:        time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+			Amount:      42.5,
+			CreatedAt:   createdAt,
+		}
+		event, err := entity.NewTransactionCreatedEvent("event-"+id, tx)
+		require.NoError(t, err)
+		_, err = txRepo.StoreWithEvents(acmeCtx, tx, []*entity.DomainEvent{event}, nil)
+		require.NoError(t, err)
+	}
+	store("tx-2", now.Add(-time.Minute))
+	store("tx-1", now.Add(-2*time.Minute))
+
+	t.Run("Events are stored with the transaction in the order they occurred", func(t *testing.T) {
+		_, err := txRepo.FindByID(acmeCtx, "tx-1")
+		require.NoError(t, err)
+
+		due, err := repo.Due(context.Background(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assert.Equal(t, "event-tx-1", due[0].Event.ID)
+		assert.Equal(t, "event-tx-2", due[1].Event.ID)
+		assert.Equal(t, "acme", due[0].Event.TenantID)
+		assert.Equal(t, "tx-1", due[0].Event.AggregateID)
+		assert.Equal(t, entity.EventTransactionCreated, due[0].Event.Type)
+		assert.Zero(t, due[0].Attempts)
+	})
+
+	t.Run("Limit caps the batch", func(t *testing.T) {
+		due, err := repo.Due(context.Background(), now, 1)
+		require.NoError(t, err)
+		assert.Len(t, due, 1)
+	})
+
+	t.Run("Entries are not due before their next attempt", func(t *testing.T) {
+		due, err := repo.Due(context.Background(), now, 10)
+		require.NoError(t, err)
+		entry := due[0]
+		entry.Attempts = 1
+		entry.NextAttemptAt = now.Add(time.Minute)
+		entry.DeliveredTo = []string{"file"}
+		require.NoError(t, repo.Update(context.Background(), entry))
+
+		due, err = repo.Due(context.Background(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, "event-tx-2", due[0].Event.ID)
+
+		due, err = repo.Due(context.Background(), now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assert.Equal(t, 1, due[0].Attempts)
+		assert.True(t, due[0].Delivered("file"))
+	})
+
+	t.Run("Deleted entries are gone", func(t *testing.T) {
+		due, err := repo.Due(context.Background(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		for _, entry := range due {
+			require.NoError(t, repo.Delete(context.Background(), entry))
+		}
+
+		due, err = repo.Due(context.Background(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		// The transactions are untouched
+		_, err = txRepo.FindByID(acmeCtx, "tx-2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Store writes no events", func(t *testing.T) {
+		_, err := txRepo.Store(acmeCtx, &entity.Transaction{
+			ID:          "tx-3",
+			Description: "Tolls",
+			Date:        time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+			Amount:      3,
+		})
+		require.NoError(t, err)
+
+		due, err := repo.Due(context.Background(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+	})
+
+	t.Run("Legal hold changes add their event", func(t *testing.T) {
+		hold := entity.LegalHold{Reason: "Audit", PlacedBy: "auditor", PlacedAt: now}
+		_, err := txRepo.PlaceLegalHold(acmeCtx, "tx-3", hold, func(tx *entity.Transaction) (*entity.DomainEvent, error) {
+			return entity.NewTransactionUpdatedEvent("event-hold", tx, entity.ChangeLegalHoldPlaced, now)
+		}, nil)
+		require.NoError(t, err)
+
+		due, err := repo.Due(context.Background(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, entity.EventTransactionUpdated, due[0].Event.Type)
+		assert.Equal(t, "acme", due[0].Event.TenantID)
+		assert.Contains(t, string(due[0].Event.Payload), `"reason":"Audit"`)
+	})
+
+	t.Run("Add stores events of the context's tenant", func(t *testing.T) {
+		event, err := entity.NewRateSyncCompletedEvent("event-sync", entity.RateSyncPayload{Currencies: []string{"EUR"}}, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, repo.Add(acmeCtx, event))
+
+		due, err := repo.Due(context.Background(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assert.Equal(t, "event-sync", due[1].Event.ID)
+		assert.Equal(t, "acme", due[1].Event.TenantID)
+	})
+}
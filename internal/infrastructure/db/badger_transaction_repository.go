@@ -1,48 +1,163 @@
 package db
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
-	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
 	"github.com/dgraph-io/badger/v3"
 )
 
-// BadgerTransactionRepository implements the transaction repository interface using BadgerDB
+// legacyTransactionPrefix is the key prefix used before tenant isolation was introduced.
+// Records stored under it belong to the default tenant.
+const legacyTransactionPrefix = "tx:"
+
+// transactionKey builds the tenant-scoped storage key for a transaction
+func transactionKey(tenantID, id string) []byte {
+	return []byte("t:" + tenantID + ":tx:" + id)
+}
+
+// expiredKey builds the key of the marker recording that a transaction existed
+// after it expires, so lookups can tell an expired transaction from an unknown one
+func expiredKey(tenantID, id string) []byte {
+	return []byte("t:" + tenantID + ":expired:" + id)
+}
+
+// legalHoldKey builds the key of the index entry recording that a transaction is
+// under legal hold, so holds can be listed without reading every transaction
+func legalHoldKey(tenantID, id string) []byte {
+	return []byte("t:" + tenantID + ":hold:" + id)
+}
+
+// dateIndexPrefix is the prefix of a tenant's date index entries
+func dateIndexPrefix(tenantID string) []byte {
+	return []byte("t:" + tenantID + ":date:")
+}
+
+// dateKey builds the key of the index entry recording a transaction's date, so
+// the transactions of a period can be read without reading every transaction.
+// Dates sort as YYYY-MM-DD, so the entries of a tenant are in date order.
+func dateKey(tenantID string, tx *entity.Transaction) []byte {
+	return append(dateIndexPrefix(tenantID), tx.Date.Format("2006-01-02")+":"+tx.ID...)
+}
+
+// RetentionConfig controls how transactions past their retention period are reported
+type RetentionConfig struct {
+	// ReportExpired makes FindByID return repository.ErrTransactionExpired for
+	// expired transactions; otherwise they are reported as not found
+	ReportExpired bool
+	// GonePeriod is how long after expiry a transaction is still reported as
+	// expired rather than not found
+	GonePeriod time.Duration
+	// PublishEvents makes the retention purger add a TransactionDeleted event to the
+	// outbox for each transaction it finds deleted
+	PublishEvents bool
+}
+
+// DefaultRetentionConfig reports expired transactions for 90 days
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		ReportExpired: true,
+		GonePeriod:    90 * 24 * time.Hour,
+	}
+}
+
+// BadgerTransactionRepository implements the transaction repository interface using BadgerDB.
+// Transactions are written with a Badger TTL taken from their TTL field, so Badger
+// stops returning them once their retention period has passed. Transactions under
+// legal hold are written without one. Each stored transaction is also indexed by
+// date and appended to its tenant's transaction stream.
 type BadgerTransactionRepository struct {
-	db     *badger.DB
-	logger logger.Logger
+	db        *badger.DB
+	retention RetentionConfig
+	logger    logger.Logger
+	metrics   metrics.Metrics
+	now       func() time.Time
+
+	// streamMu serialises stream appends so sequence numbers are not reused
+	streamMu sync.Mutex
 }
 
 // NewBadgerTransactionRepository creates a new BadgerDB transaction repository
-func NewBadgerTransactionRepository(db *badger.DB, log logger.Logger) repository.TransactionRepository {
+func NewBadgerTransactionRepository(db *badger.DB, log logger.Logger, m metrics.Metrics) repository.TransactionRepository {
+	return NewBadgerTransactionRepositoryWithRetention(db, DefaultRetentionConfig(), log, m)
+}
+
+// NewBadgerTransactionRepositoryWithRetention creates a new BadgerDB transaction
+// repository that reports expired transactions as configured
+func NewBadgerTransactionRepositoryWithRetention(db *badger.DB, retention RetentionConfig, log logger.Logger, m metrics.Metrics) repository.TransactionRepository {
 	if log == nil {
 		log = logger.GetDefaultLogger()
 	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
 
 	return &BadgerTransactionRepository{
-		db:     db,
-		logger: log,
+		db:        db,
+		retention: retention,
+		logger:    log,
+		metrics:   m,
+		now:       time.Now,
 	}
 }
 
+// observe records the latency of a database operation
+func (r *BadgerTransactionRepository) observe(operation, outcome string, start time.Time) {
+	r.metrics.ObserveDuration(metrics.DBOperationDuration, time.Since(start), map[string]string{
+		"operation": operation,
+		"outcome":   outcome,
+	})
+}
+
 // Store saves a transaction and returns its ID
 func (r *BadgerTransactionRepository) Store(ctx context.Context, tx *entity.Transaction) (string, error) {
-	requestID := middleware.GetRequestID(ctx)
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.Store")
+	defer span.End()
+
+	id, err := r.store(ctx, tx, nil, nil)
+	tracing.SetError(span, err)
+	return id, err
+}
+
+// StoreWithEvents saves a transaction, adds events to the outbox and appends the
+// audit event to the audit log in the same Badger transaction
+func (r *BadgerTransactionRepository) StoreWithEvents(ctx context.Context, tx *entity.Transaction, events []*entity.DomainEvent, audit *entity.AuditEvent) (string, error) {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.StoreWithEvents")
+	defer span.End()
+
+	id, err := r.store(ctx, tx, events, audit)
+	tracing.SetError(span, err)
+	return id, err
+}
+
+// store writes a transaction, its retention entries, its stream entry, its outbox
+// entries and its audit event
+func (r *BadgerTransactionRepository) store(ctx context.Context, tx *entity.Transaction, events []*entity.DomainEvent, audit *entity.AuditEvent) (string, error) {
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+
+	// The record always belongs to the tenant of the calling context
+	tx.TenantID = tenantID
 
 	// Set CreatedAt if not already set
 	if tx.CreatedAt.IsZero() {
 		tx.CreatedAt = time.Now().UTC()
-		tx.CalculateTTL() // Calculate TTL for data retention
+		tx.CalculateTTL(entity.DefaultRetention) // Calculate TTL for data retention
 	}
 
-	r.logger.Debug("Storing transaction", map[string]interface{}{
-		"request_id":  requestID,
+	log.Debug("Storing transaction", map[string]interface{}{
 		"id":          tx.ID,
 		"description": tx.Description,
 		"date":        tx.Date.Format("2006-01-02"),
@@ -54,31 +169,57 @@ func (r *BadgerTransactionRepository) Store(ctx context.Context, tx *entity.Tran
 	// Serialize transaction to JSON
 	data, err := json.Marshal(tx)
 	if err != nil {
-		r.logger.Error("Failed to marshal transaction", map[string]interface{}{
-			"request_id": requestID,
-			"id":         tx.ID,
-			"error":      err.Error(),
+		log.Error("Failed to marshal transaction", map[string]interface{}{
+			"id":    tx.ID,
+			"error": err.Error(),
 		})
 		return "", fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
 	// Store in BadgerDB
+	entries := r.retentionEntries(tenantID, tx, data)
+	for _, event := range events {
+		event.TenantID = tenantID
+		entry, err := newOutboxEntry(event)
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, entry)
+	}
+	start := time.Now()
+	r.streamMu.Lock()
+	if audit != nil {
+		auditMu.Lock()
+	}
 	err = r.db.Update(func(txn *badger.Txn) error {
-		return txn.Set([]byte("tx:"+tx.ID), data)
+		for _, entry := range entries {
+			if err := txn.SetEntry(entry); err != nil {
+				return err
+			}
+		}
+		if audit != nil {
+			if err := appendAuditEvent(txn, tenantID, audit, r.now()); err != nil {
+				return err
+			}
+		}
+		return appendStreamEntry(txn, tenantID, tx, r.streamTTL(tx))
 	})
+	if audit != nil {
+		auditMu.Unlock()
+	}
+	r.streamMu.Unlock()
+	r.observe("store", metrics.Outcome(err), start)
 
 	if err != nil {
-		r.logger.Error("Failed to store transaction in database", map[string]interface{}{
-			"request_id": requestID,
-			"id":         tx.ID,
-			"error":      err.Error(),
+		log.Error("Failed to store transaction in database", map[string]interface{}{
+			"id":    tx.ID,
+			"error": err.Error(),
 		})
 		return "", fmt.Errorf("failed to store transaction: %w", err)
 	}
 
-	r.logger.Info("Transaction stored successfully", map[string]interface{}{
-		"request_id": requestID,
-		"id":         tx.ID,
+	log.Info("Transaction stored successfully", map[string]interface{}{
+		"id": tx.ID,
 	})
 
 	return tx.ID, nil
@@ -86,17 +227,31 @@ func (r *BadgerTransactionRepository) Store(ctx context.Context, tx *entity.Tran
 
 // FindByID retrieves a transaction by its unique identifier
 func (r *BadgerTransactionRepository) FindByID(ctx context.Context, id string) (*entity.Transaction, error) {
-	requestID := middleware.GetRequestID(ctx)
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.FindByID")
+	defer span.End()
 
-	r.logger.Debug("Finding transaction by ID", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+
+	log.Debug("Finding transaction by ID", map[string]interface{}{
+		"id": id,
 	})
 
 	var tx entity.Transaction
+	var purged bool
 
+	start := time.Now()
 	err := r.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte("tx:" + id))
+		item, err := txn.Get(transactionKey(tenantID, id))
+		if err == badger.ErrKeyNotFound && tenantID == tenant.DefaultID {
+			// Fall back to records written before tenant isolation
+			item, err = txn.Get([]byte(legacyTransactionPrefix + id))
+		}
+		if err == badger.ErrKeyNotFound {
+			// An expiry marker outlives the record it describes
+			_, markerErr := txn.Get(expiredKey(tenantID, id))
+			purged = markerErr == nil
+		}
 		if err != nil {
 			return err
 		}
@@ -106,25 +261,55 @@ func (r *BadgerTransactionRepository) FindByID(ctx context.Context, id string) (
 		})
 	})
 
+	expired := purged || (err == nil && tx.Expired(r.now()))
+	switch {
+	case expired:
+		r.observe("find_by_id", "expired", start)
+	case err == nil:
+		r.observe("find_by_id", "success", start)
+	case err == badger.ErrKeyNotFound:
+		r.observe("find_by_id", "not_found", start)
+	default:
+		r.observe("find_by_id", "error", start)
+		tracing.SetError(span, err)
+	}
+
+	if expired {
+		log.Info("Transaction expired", map[string]interface{}{
+			"id":     id,
+			"purged": purged,
+		})
+		if r.retention.ReportExpired {
+			return nil, fmt.Errorf("%w: %s", repository.ErrTransactionExpired, id)
+		}
+		return nil, fmt.Errorf("%w: %s", repository.ErrTransactionNotFound, id)
+	}
+
 	if err == badger.ErrKeyNotFound {
-		r.logger.Warn("Transaction not found", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
+		log.Warn("Transaction not found", map[string]interface{}{
+			"id": id,
 		})
-		return nil, fmt.Errorf("transaction not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", repository.ErrTransactionNotFound, id)
 	}
 
 	if err != nil {
-		r.logger.Error("Failed to retrieve transaction", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
-			"error":      err.Error(),
+		log.Error("Failed to retrieve transaction", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
 		})
 		return nil, fmt.Errorf("failed to retrieve transaction: %w", err)
 	}
 
-	r.logger.Debug("Transaction found", map[string]interface{}{
-		"request_id":  requestID,
+	// Guard against a record that was written for a different tenant
+	if tx.TenantID != "" && tx.TenantID != tenantID {
+		log.Error("Transaction tenant mismatch", map[string]interface{}{
+			"record_tenant": tx.TenantID,
+			"id":            id,
+		})
+		return nil, fmt.Errorf("%w: %s", repository.ErrTransactionNotFound, id)
+	}
+
+	log.Debug("Transaction found", map[string]interface{}{
 		"id":          tx.ID,
 		"description": tx.Description,
 		"date":        tx.Date.Format("2006-01-02"),
@@ -133,18 +318,479 @@ func (r *BadgerTransactionRepository) FindByID(ctx context.Context, id string) (
 		"ttl":         tx.TTL,
 	})
 
-	// Check if transaction should be expired based on TTL
-	if tx.TTL > 0 && time.Now().Unix() > tx.TTL {
-		r.logger.Warn("Transaction has expired but was not deleted", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
-			"ttl":        tx.TTL,
-			"now":        time.Now().Unix(),
+	return &tx, nil
+}
+
+// List calls fn for each transaction of the context's tenant in key order. Records
+// written before tenant isolation are included for the default tenant.
+func (r *BadgerTransactionRepository) List(ctx context.Context, fn func(*entity.Transaction) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.List")
+	defer span.End()
+
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+
+	prefixes := [][]byte{transactionKey(tenantID, "")}
+	if tenantID == tenant.DefaultID {
+		prefixes = append(prefixes, []byte(legacyTransactionPrefix))
+	}
+
+	// Records written without a Badger TTL stay visible until purged
+	now := r.now()
+	visible := func(tx *entity.Transaction) error {
+		if tx.Expired(now) {
+			return nil
+		}
+		return fn(tx)
+	}
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		for _, prefix := range prefixes {
+			it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true, PrefetchSize: 100})
+			err := iterateTransactions(it, prefix, visible)
+			it.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("list", metrics.Outcome(err), start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		log.Error("Failed to list transactions", map[string]interface{}{
+			"error": err.Error(),
 		})
-		// In production using DynamoDB, this would be handled automatically
-		// For BadgerDB, we could implement a background cleanup process
-		// For now, we'll still return the transaction
+		return fmt.Errorf("failed to list transactions: %w", err)
 	}
 
-	return &tx, nil
+	return nil
+}
+
+// ListAfter calls fn for each transaction of the context's tenant whose ID sorts
+// after after, in ID order. Each prefix is read from after onwards, so a page costs
+// its own length rather than the tenant's. Records written before tenant isolation
+// are merged in for the default tenant.
+func (r *BadgerTransactionRepository) ListAfter(ctx context.Context, after string, fn func(*entity.Transaction) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.ListAfter")
+	defer span.End()
+
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+
+	prefixes := [][]byte{transactionKey(tenantID, "")}
+	if tenantID == tenant.DefaultID {
+		prefixes = append(prefixes, []byte(legacyTransactionPrefix))
+	}
+
+	// Records written without a Badger TTL stay visible until purged
+	now := r.now()
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		iterators := make([]*badger.Iterator, len(prefixes))
+		for i, prefix := range prefixes {
+			it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+			defer it.Close()
+			it.Seek([]byte(string(prefix) + after))
+			if it.ValidForPrefix(prefix) && string(it.Item().Key()[len(prefix):]) == after {
+				it.Next()
+			}
+			iterators[i] = it
+		}
+
+		for {
+			// Take the lowest ID; a legacy record with the ID of a tenant-scoped
+			// one is skipped
+			next, id := -1, ""
+			for i, it := range iterators {
+				if !it.ValidForPrefix(prefixes[i]) {
+					continue
+				}
+				itemID := string(it.Item().Key()[len(prefixes[i]):])
+				switch {
+				case next == -1 || itemID < id:
+					next, id = i, itemID
+				case itemID == id:
+					it.Next()
+				}
+			}
+			if next == -1 {
+				return nil
+			}
+
+			var tx entity.Transaction
+			item := iterators[next].Item()
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &tx) }); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", item.Key(), err)
+			}
+			iterators[next].Next()
+			if tx.Expired(now) {
+				continue
+			}
+			if err := fn(&tx); err != nil {
+				return err
+			}
+		}
+	})
+	r.observe("list_after", metrics.Outcome(err), start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		log.Error("Failed to list transactions", map[string]interface{}{
+			"after": after,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDate calls fn for each transaction of the context's tenant dated within
+// the range, reading the date index rather than every transaction. Records written
+// before tenant isolation are included for the default tenant.
+func (r *BadgerTransactionRepository) ListByDate(ctx context.Context, from, to time.Time, fn func(*entity.Transaction) error) error {
+	return r.ListByDateAfter(ctx, from, to, nil, fn)
+}
+
+// ListByDateAfter is ListByDate starting at after's date index entry, which is
+// skipped, so a listing read in chunks costs each chunk's own length
+func (r *BadgerTransactionRepository) ListByDateAfter(ctx context.Context, from, to time.Time, after *entity.Transaction, fn func(*entity.Transaction) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.ListByDate")
+	defer span.End()
+
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+	prefix := dateIndexPrefix(tenantID)
+
+	seek := prefix
+	switch {
+	case after != nil:
+		seek = dateKey(tenantID, after)
+	case !from.IsZero():
+		seek = append(dateIndexPrefix(tenantID), from.Format("2006-01-02")...)
+	}
+	last := ""
+	if !to.IsZero() {
+		last = to.Format("2006-01-02")
+	}
+
+	// Records written without a Badger TTL stay visible until purged
+	now := r.now()
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Seek(seek); it.ValidForPrefix(prefix); it.Next() {
+			if after != nil && bytes.Equal(it.Item().Key(), seek) {
+				continue
+			}
+			date, id, _ := strings.Cut(string(it.Item().Key()[len(prefix):]), ":")
+			if last != "" && date > last {
+				break
+			}
+
+			item, err := txn.Get(transactionKey(tenantID, id))
+			if err == badger.ErrKeyNotFound && tenantID == tenant.DefaultID {
+				item, err = txn.Get([]byte(legacyTransactionPrefix + id))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read transaction %s: %w", id, err)
+			}
+
+			var tx entity.Transaction
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &tx) }); err != nil {
+				return fmt.Errorf("failed to decode transaction %s: %w", id, err)
+			}
+			if tx.Expired(now) {
+				continue
+			}
+			if err := fn(&tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("list_by_date", metrics.Outcome(err), start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		log.Error("Failed to list transactions by date", map[string]interface{}{
+			"from":  from.Format("2006-01-02"),
+			"to":    to.Format("2006-01-02"),
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to list transactions by date: %w", err)
+	}
+
+	return nil
+}
+
+// PlaceLegalHold puts a transaction of the context's tenant under legal hold. The
+// record is rewritten without a Badger TTL so it is kept until the hold is released.
+func (r *BadgerTransactionRepository) PlaceLegalHold(ctx context.Context, id string, hold entity.LegalHold, event repository.EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.PlaceLegalHold")
+	defer span.End()
+
+	tx, err := r.updateLegalHold(ctx, "place_legal_hold", id, event, audit, func(tx *entity.Transaction) error {
+		if tx.Held() {
+			return fmt.Errorf("%w: %s", repository.ErrLegalHoldExists, id)
+		}
+		tx.LegalHold = &hold
+		return nil
+	})
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	logger.ForContext(ctx, r.logger).Info("Legal hold placed", map[string]interface{}{
+		"id":        id,
+		"placed_by": hold.PlacedBy,
+	})
+	return tx, nil
+}
+
+// ReleaseLegalHold lifts a transaction's legal hold. The record gets back the Badger
+// TTL left in its retention period; one whose period passed while it was held is
+// left for the purge job.
+func (r *BadgerTransactionRepository) ReleaseLegalHold(ctx context.Context, id string, event repository.EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error) {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.ReleaseLegalHold")
+	defer span.End()
+
+	tx, err := r.updateLegalHold(ctx, "release_legal_hold", id, event, audit, func(tx *entity.Transaction) error {
+		if !tx.Held() {
+			return fmt.Errorf("%w: %s", repository.ErrNoLegalHold, id)
+		}
+		tx.LegalHold = nil
+		return nil
+	})
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, err
+	}
+
+	logger.ForContext(ctx, r.logger).Info("Legal hold released", map[string]interface{}{
+		"id":         id,
+		"expires_at": tx.ExpiresAt().Format(time.RFC3339),
+	})
+	return tx, nil
+}
+
+// updateLegalHold applies change to a stored transaction of the context's tenant,
+// then rewrites the record with its retention entries and hold index entry, adds the
+// event built by event to the outbox and appends audit to the audit log. Records
+// written before tenant isolation move to their tenant-scoped key.
+func (r *BadgerTransactionRepository) updateLegalHold(ctx context.Context, operation, id string, event repository.EventFunc, audit *entity.AuditEvent, change func(*entity.Transaction) error) (*entity.Transaction, error) {
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+	now := r.now()
+
+	var tx entity.Transaction
+	start := time.Now()
+	if audit != nil {
+		auditMu.Lock()
+		defer auditMu.Unlock()
+	}
+	err := r.db.Update(func(txn *badger.Txn) error {
+		key := transactionKey(tenantID, id)
+		item, err := txn.Get(key)
+		if err == badger.ErrKeyNotFound && tenantID == tenant.DefaultID {
+			key = []byte(legacyTransactionPrefix + id)
+			item, err = txn.Get(key)
+		}
+		if err == badger.ErrKeyNotFound {
+			if _, markerErr := txn.Get(expiredKey(tenantID, id)); markerErr == nil {
+				return fmt.Errorf("%w: %s", repository.ErrTransactionExpired, id)
+			}
+			return fmt.Errorf("%w: %s", repository.ErrTransactionNotFound, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &tx) }); err != nil {
+			return fmt.Errorf("failed to decode transaction: %w", err)
+		}
+		if tx.TenantID != "" && tx.TenantID != tenantID {
+			return fmt.Errorf("%w: %s", repository.ErrTransactionNotFound, id)
+		}
+		if tx.Expired(now) {
+			return fmt.Errorf("%w: %s", repository.ErrTransactionExpired, id)
+		}
+		if err := change(&tx); err != nil {
+			return err
+		}
+
+		tx.TenantID = tenantID
+		data, err := json.Marshal(&tx)
+		if err != nil {
+			return fmt.Errorf("failed to marshal transaction: %w", err)
+		}
+		if !bytes.Equal(key, transactionKey(tenantID, id)) {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		entries := r.retentionEntries(tenantID, &tx, data)
+		if event != nil {
+			entry, err := r.eventEntry(tenantID, &tx, event)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		for _, entry := range entries {
+			if err := txn.SetEntry(entry); err != nil {
+				return err
+			}
+		}
+		if audit != nil {
+			if err := appendAuditEvent(txn, tenantID, audit, now); err != nil {
+				return err
+			}
+		}
+		if tx.Held() {
+			return txn.Set(legalHoldKey(tenantID, id), nil)
+		}
+		return txn.Delete(legalHoldKey(tenantID, id))
+	})
+
+	switch {
+	case err == nil:
+		r.observe(operation, "success", start)
+		return &tx, nil
+	case errors.Is(err, repository.ErrTransactionExpired):
+		r.observe(operation, "expired", start)
+		if !r.retention.ReportExpired {
+			return nil, fmt.Errorf("%w: %s", repository.ErrTransactionNotFound, id)
+		}
+		return nil, err
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		r.observe(operation, "not_found", start)
+		return nil, err
+	case errors.Is(err, repository.ErrLegalHoldExists), errors.Is(err, repository.ErrNoLegalHold):
+		r.observe(operation, "conflict", start)
+		return nil, err
+	default:
+		r.observe(operation, "error", start)
+		log.Error("Failed to update legal hold", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to update legal hold: %w", err)
+	}
+}
+
+// eventEntry builds the event announcing a change to tx and returns its outbox entry
+func (r *BadgerTransactionRepository) eventEntry(tenantID string, tx *entity.Transaction, event repository.EventFunc) (*badger.Entry, error) {
+	domainEvent, err := event(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+	domainEvent.TenantID = tenantID
+	return newOutboxEntry(domainEvent)
+}
+
+// ListLegalHolds calls fn for each held transaction of the context's tenant in ID order
+func (r *BadgerTransactionRepository) ListLegalHolds(ctx context.Context, fn func(*entity.Transaction) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionRepository.ListLegalHolds")
+	defer span.End()
+
+	log := logger.ForContext(ctx, r.logger)
+	tenantID := tenant.IDFromContext(ctx)
+	prefix := legalHoldKey(tenantID, "")
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			id := string(it.Item().Key()[len(prefix):])
+			item, err := txn.Get(transactionKey(tenantID, id))
+			if err != nil {
+				return fmt.Errorf("failed to read held transaction %s: %w", id, err)
+			}
+
+			var tx entity.Transaction
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &tx) }); err != nil {
+				return fmt.Errorf("failed to decode held transaction %s: %w", id, err)
+			}
+			if err := fn(&tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("list_legal_holds", metrics.Outcome(err), start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		log.Error("Failed to list legal holds", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to list legal holds: %w", err)
+	}
+
+	return nil
+}
+
+// iterateTransactions decodes each record under prefix and passes it to fn
+func iterateTransactions(it *badger.Iterator, prefix []byte, fn func(*entity.Transaction) error) error {
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		var tx entity.Transaction
+		err := it.Item().Value(func(val []byte) error {
+			return json.Unmarshal(val, &tx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", it.Item().Key(), err)
+		}
+		if err := fn(&tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retentionEntries returns the entries that store tx: the record itself and its
+// date index entry, both expiring with the transaction unless it is under legal
+// hold, and its expiry marker
+func (r *BadgerTransactionRepository) retentionEntries(tenantID string, tx *entity.Transaction, data []byte) []*badger.Entry {
+	entry := badger.NewEntry(transactionKey(tenantID, tx.ID), data)
+	index := badger.NewEntry(dateKey(tenantID, tx), nil)
+
+	expiresAt := tx.ExpiresAt()
+	if expiresAt.IsZero() || tx.Held() {
+		return []*badger.Entry{entry, index}
+	}
+
+	// A transaction that has already expired is kept until the purge job removes it
+	ttl := expiresAt.Sub(r.now())
+	if ttl <= 0 {
+		return []*badger.Entry{entry, index}
+	}
+
+	entry = entry.WithTTL(ttl)
+	index.ExpiresAt = entry.ExpiresAt
+	entries := []*badger.Entry{entry, index}
+	if r.retention.GonePeriod > 0 {
+		entries = append(entries, badger.NewEntry(expiredKey(tenantID, tx.ID), nil).WithTTL(ttl+r.retention.GonePeriod))
+	}
+	return entries
+}
+
+// streamTTL returns how long the stream entry of tx is kept: until the
+// transaction expires, or indefinitely if it does not expire yet
+func (r *BadgerTransactionRepository) streamTTL(tx *entity.Transaction) time.Duration {
+	expiresAt := tx.ExpiresAt()
+	if expiresAt.IsZero() || tx.Held() {
+		return 0
+	}
+	return expiresAt.Sub(r.now())
 }
@@ -0,0 +1,411 @@
+// internal/infrastructure/db/badger_transaction_repository_test.go
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB opens an in-memory BadgerDB for repository tests
+func openTestDB(t *testing.T) *badger.DB {
+	t.Helper()
+
+	badgerDB, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { badgerDB.Close() })
+
+	return badgerDB
+}
+
+func TestBadgerTransactionRepositoryTenantIsolation(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	repo := NewBadgerTransactionRepository(badgerDB, log, nil)
+
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	globexCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "globex"})
+
+	tx := &entity.Transaction{
+		ID:          "shared-id",
+		Description: "Acme purchase",
+		Date:        time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC),
+		Amount:      42.50,
+	}
+
+	_, err := repo.Store(acmeCtx, tx)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tx.TenantID)
+
+	t.Run("Owner can read", func(t *testing.T) {
+		found, err := repo.FindByID(acmeCtx, "shared-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme purchase", found.Description)
+	})
+
+	t.Run("Other tenant cannot read", func(t *testing.T) {
+		found, err := repo.FindByID(globexCtx, "shared-id")
+		assert.Error(t, err)
+		assert.Nil(t, found)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("Default tenant cannot read", func(t *testing.T) {
+		_, err := repo.FindByID(context.Background(), "shared-id")
+		assert.Error(t, err)
+	})
+
+	t.Run("Same ID in another tenant is independent", func(t *testing.T) {
+		other := &entity.Transaction{
+			ID:          "shared-id",
+			Description: "Globex purchase",
+			Date:        time.Date(2023, 4, 16, 0, 0, 0, 0, time.UTC),
+			Amount:      10,
+		}
+		_, err := repo.Store(globexCtx, other)
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(acmeCtx, "shared-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme purchase", found.Description)
+	})
+
+	t.Run("Legacy keys belong to the default tenant", func(t *testing.T) {
+		err := badgerDB.Update(func(txn *badger.Txn) error {
+			return txn.Set([]byte(legacyTransactionPrefix+"legacy-id"),
+				[]byte(`{"id":"legacy-id","description":"Old record","amount":5}`))
+		})
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(context.Background(), "legacy-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "Old record", found.Description)
+
+		_, err = repo.FindByID(acmeCtx, "legacy-id")
+		assert.Error(t, err)
+	})
+}
+
+func TestBadgerTransactionRepositoryList(t *testing.T) {
+	badgerDB := openTestDB(t)
+	repo := NewBadgerTransactionRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	defaultCtx := context.Background()
+
+	for _, id := range []string{"b", "a"} {
+		_, err := repo.Store(acmeCtx, &entity.Transaction{ID: id, Description: "Acme " + id, Amount: 1})
+		require.NoError(t, err)
+	}
+	_, err := repo.Store(defaultCtx, &entity.Transaction{ID: "c", Description: "Default", Amount: 1})
+	require.NoError(t, err)
+	require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(legacyTransactionPrefix+"legacy"), []byte(`{"id":"legacy","description":"Legacy","amount":1}`))
+	}))
+
+	list := func(ctx context.Context) []string {
+		var ids []string
+		require.NoError(t, repo.List(ctx, func(tx *entity.Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		}))
+		return ids
+	}
+
+	assert.Equal(t, []string{"a", "b"}, list(acmeCtx))
+	assert.Equal(t, []string{"c", "legacy"}, list(defaultCtx))
+
+	// An error from the callback stops the iteration and is returned
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.List(acmeCtx, func(*entity.Transaction) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBadgerTransactionRepositoryListAfter(t *testing.T) {
+	badgerDB := openTestDB(t)
+	repo := NewBadgerTransactionRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	defaultCtx := context.Background()
+
+	for _, id := range []string{"d", "b", "f"} {
+		_, err := repo.Store(defaultCtx, &entity.Transaction{ID: id, Description: "Default " + id, Amount: 1})
+		require.NoError(t, err)
+	}
+	_, err := repo.Store(acmeCtx, &entity.Transaction{ID: "c", Description: "Acme", Amount: 1})
+	require.NoError(t, err)
+	require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+		for _, id := range []string{"a", "e"} {
+			if err := txn.Set([]byte(legacyTransactionPrefix+id), []byte(`{"id":"`+id+`","description":"Legacy","amount":1}`)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	list := func(ctx context.Context, after string) []string {
+		var ids []string
+		require.NoError(t, repo.ListAfter(ctx, after, func(tx *entity.Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		}))
+		return ids
+	}
+
+	// Legacy records are merged into the default tenant's ID order
+	assert.Equal(t, []string{"a", "b", "d", "e", "f"}, list(defaultCtx, ""))
+	assert.Equal(t, []string{"d", "e", "f"}, list(defaultCtx, "b"))
+	assert.Equal(t, []string{"d", "e", "f"}, list(defaultCtx, "c"))
+	assert.Empty(t, list(defaultCtx, "f"))
+	assert.Equal(t, []string{"c"}, list(acmeCtx, "a"))
+
+	// An error from the callback stops the iteration and is returned
+	stop := errors.New("stop")
+	var ids []string
+	err = repo.ListAfter(defaultCtx, "a", func(tx *entity.Transaction) error {
+		ids = append(ids, tx.ID)
+		if len(ids) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"b", "d"}, ids)
+}
+
+func TestBadgerTransactionRepositoryListByDate(t *testing.T) {
+	badgerDB := openTestDB(t)
+	repo := NewBadgerTransactionRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	globexCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "globex"})
+	day := func(month time.Month, d int) time.Time { return time.Date(2023, month, d, 0, 0, 0, 0, time.UTC) }
+
+	for _, tx := range []*entity.Transaction{
+		{ID: "a", Date: day(3, 2)},
+		{ID: "b", Date: day(1, 31)},
+		{ID: "c", Date: day(2, 1)},
+		{ID: "d", Date: day(2, 28)},
+	} {
+		tx.Description, tx.Amount = "Fuel", 1
+		_, err := repo.Store(acmeCtx, tx)
+		require.NoError(t, err)
+	}
+	_, err := repo.Store(globexCtx, &entity.Transaction{ID: "e", Description: "Fuel", Date: day(2, 14), Amount: 1})
+	require.NoError(t, err)
+
+	list := func(ctx context.Context, from, to time.Time) []string {
+		var ids []string
+		require.NoError(t, repo.ListByDate(ctx, from, to, func(tx *entity.Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		}))
+		return ids
+	}
+
+	// Transactions are in date order, both ends of the range included
+	assert.Equal(t, []string{"c", "d"}, list(acmeCtx, day(2, 1), day(2, 28)))
+	assert.Equal(t, []string{"b", "c", "d", "a"}, list(acmeCtx, time.Time{}, time.Time{}))
+	assert.Equal(t, []string{"d", "a"}, list(acmeCtx, day(2, 2), time.Time{}))
+	assert.Equal(t, []string{"e"}, list(globexCtx, time.Time{}, time.Time{}))
+
+	// Holds rewrite the record and keep its index entry
+	_, err = repo.PlaceLegalHold(acmeCtx, "c", entity.LegalHold{Reason: "Audit", PlacedBy: "alice"}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, list(acmeCtx, day(2, 1), day(2, 28)))
+
+	// A listing resumes after the last transaction read, within its date too
+	var resumed []string
+	require.NoError(t, repo.ListByDateAfter(acmeCtx, day(2, 1), time.Time{}, &entity.Transaction{ID: "c", Date: day(2, 1)},
+		func(tx *entity.Transaction) error {
+			resumed = append(resumed, tx.ID)
+			return nil
+		}))
+	assert.Equal(t, []string{"d", "a"}, resumed)
+	_, err = repo.Store(acmeCtx, &entity.Transaction{ID: "f", Description: "Fuel", Date: day(2, 1), Amount: 1})
+	require.NoError(t, err)
+	resumed = nil
+	require.NoError(t, repo.ListByDateAfter(acmeCtx, time.Time{}, day(2, 28), &entity.Transaction{ID: "c", Date: day(2, 1)},
+		func(tx *entity.Transaction) error {
+			resumed = append(resumed, tx.ID)
+			return nil
+		}))
+	assert.Equal(t, []string{"f", "d"}, resumed)
+}
+
+func TestBadgerTransactionRepositoryRetention(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	repo := NewBadgerTransactionRepository(badgerDB, log, nil)
+	ctx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	// store saves a transaction created at createdAt with the default retention
+	store := func(id string, createdAt time.Time) {
+		tx := &entity.Transaction{ID: id, Description: "Fuel", Date: createdAt, Amount: 10, CreatedAt: createdAt}
+		tx.CalculateTTL(entity.DefaultRetention)
+		_, err := repo.Store(ctx, tx)
+		require.NoError(t, err)
+	}
+	store("live", time.Now())
+	store("stale", time.Now().Add(-2*entity.DefaultRetention))
+
+	t.Run("Records expire in Badger with a marker that outlives them", func(t *testing.T) {
+		require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+			record, err := txn.Get(transactionKey("acme", "live"))
+			require.NoError(t, err)
+			marker, err := txn.Get(expiredKey("acme", "live"))
+			require.NoError(t, err)
+			assert.NotZero(t, record.ExpiresAt())
+			assert.Greater(t, marker.ExpiresAt(), record.ExpiresAt())
+			return nil
+		}))
+	})
+
+	t.Run("Expired records are reported as expired", func(t *testing.T) {
+		_, err := repo.FindByID(ctx, "stale")
+		assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+
+		var ids []string
+		require.NoError(t, repo.List(ctx, func(tx *entity.Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		}))
+		assert.Equal(t, []string{"live"}, ids)
+	})
+
+	t.Run("Records removed by Badger are reported as expired", func(t *testing.T) {
+		// Badger's clock cannot be advanced, so remove the record as expiry would
+		require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+			return txn.Delete(transactionKey("acme", "live"))
+		}))
+
+		_, err := repo.FindByID(ctx, "live")
+		assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+
+		_, err = repo.FindByID(ctx, "never-stored")
+		assert.ErrorIs(t, err, repository.ErrTransactionNotFound)
+	})
+
+	t.Run("Expired records can be reported as not found", func(t *testing.T) {
+		hidden := NewBadgerTransactionRepositoryWithRetention(badgerDB, RetentionConfig{GonePeriod: time.Hour}, log, nil)
+
+		_, err := hidden.FindByID(ctx, "stale")
+		assert.ErrorIs(t, err, repository.ErrTransactionNotFound)
+		assert.NotErrorIs(t, err, repository.ErrTransactionExpired)
+	})
+}
+
+func TestBadgerTransactionRepositoryLegalHold(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	repo := NewBadgerTransactionRepository(badgerDB, log, nil).(*BadgerTransactionRepository)
+	ctx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	for _, id := range []string{"disputed", "other"} {
+		tx := &entity.Transaction{ID: id, Description: "Fuel", Date: time.Now(), Amount: 10, CreatedAt: time.Now()}
+		tx.CalculateTTL(entity.DefaultRetention)
+		_, err := repo.Store(ctx, tx)
+		require.NoError(t, err)
+	}
+
+	// listHolds returns the IDs of the tenant's held transactions
+	listHolds := func(ctx context.Context) []string {
+		var ids []string
+		require.NoError(t, repo.ListLegalHolds(ctx, func(tx *entity.Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		}))
+		return ids
+	}
+
+	hold := entity.LegalHold{Reason: "Chargeback dispute", PlacedBy: "auditor", PlacedAt: time.Now().UTC()}
+
+	t.Run("Placing a hold removes the Badger TTL", func(t *testing.T) {
+		tx, err := repo.PlaceLegalHold(ctx, "disputed", hold, nil, nil)
+		require.NoError(t, err)
+		require.True(t, tx.Held())
+		assert.Equal(t, "auditor", tx.LegalHold.PlacedBy)
+
+		require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+			record, err := txn.Get(transactionKey("acme", "disputed"))
+			require.NoError(t, err)
+			assert.Zero(t, record.ExpiresAt())
+			return nil
+		}))
+
+		_, err = repo.PlaceLegalHold(ctx, "disputed", hold, nil, nil)
+		assert.ErrorIs(t, err, repository.ErrLegalHoldExists)
+	})
+
+	t.Run("Holds are listed per tenant", func(t *testing.T) {
+		assert.Equal(t, []string{"disputed"}, listHolds(ctx))
+		assert.Empty(t, listHolds(context.Background()))
+
+		_, err := repo.PlaceLegalHold(context.Background(), "disputed", hold, nil, nil)
+		assert.ErrorIs(t, err, repository.ErrTransactionNotFound)
+	})
+
+	t.Run("Held transactions outlive their retention period", func(t *testing.T) {
+		repo.now = func() time.Time { return time.Now().Add(2 * entity.DefaultRetention) }
+		defer func() { repo.now = time.Now }()
+
+		tx, err := repo.FindByID(ctx, "disputed")
+		require.NoError(t, err)
+		assert.Equal(t, "Chargeback dispute", tx.LegalHold.Reason)
+
+		_, err = repo.FindByID(ctx, "other")
+		assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+
+		_, err = repo.PlaceLegalHold(ctx, "other", hold, nil, nil)
+		assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+	})
+
+	t.Run("Releasing a hold restores the Badger TTL", func(t *testing.T) {
+		tx, err := repo.ReleaseLegalHold(ctx, "disputed", nil, nil)
+		require.NoError(t, err)
+		assert.False(t, tx.Held())
+		assert.Empty(t, listHolds(ctx))
+
+		require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+			record, err := txn.Get(transactionKey("acme", "disputed"))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(tx.TTL), record.ExpiresAt())
+			return nil
+		}))
+
+		_, err = repo.ReleaseLegalHold(ctx, "disputed", nil, nil)
+		assert.ErrorIs(t, err, repository.ErrNoLegalHold)
+	})
+
+	t.Run("Holding a legacy record moves it to its tenant key", func(t *testing.T) {
+		require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+			return txn.Set([]byte(legacyTransactionPrefix+"legacy"), []byte(`{"id":"legacy","description":"Legacy","amount":1}`))
+		}))
+
+		_, err := repo.PlaceLegalHold(context.Background(), "legacy", hold, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"legacy"}, listHolds(context.Background()))
+
+		require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+			_, err := txn.Get([]byte(legacyTransactionPrefix + "legacy"))
+			assert.ErrorIs(t, err, badger.ErrKeyNotFound)
+			_, err = txn.Get(transactionKey(tenant.DefaultID, "legacy"))
+			assert.NoError(t, err)
+			return nil
+		}))
+	})
+}
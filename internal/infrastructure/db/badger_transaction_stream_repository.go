@@ -0,0 +1,200 @@
+// Package db internal/infrastructure/db/badger_transaction_stream_repository.go
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/pb"
+)
+
+// streamPrefix returns the key prefix of a tenant's stream entries
+func streamPrefix(tenantID string) []byte {
+	return []byte("t:" + tenantID + ":stream:")
+}
+
+// streamKey builds the key of a tenant's stream entry. Sequence numbers are
+// zero-padded so keys sort in stream order.
+func streamKey(tenantID string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("t:%s:stream:%020d", tenantID, sequence))
+}
+
+// streamHeadKey builds the key holding the sequence number of a tenant's latest
+// stream entry
+func streamHeadKey(tenantID string) []byte {
+	return []byte("t:" + tenantID + ":stream-head")
+}
+
+// readStreamHead returns the sequence number of a tenant's latest stream entry
+func readStreamHead(txn *badger.Txn, tenantID string) (uint64, error) {
+	item, err := txn.Get(streamHeadKey(tenantID))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var head uint64
+	err = item.Value(func(val []byte) error {
+		if len(val) != 8 {
+			return fmt.Errorf("invalid stream head of %d bytes", len(val))
+		}
+		head = binary.BigEndian.Uint64(val)
+		return nil
+	})
+	return head, err
+}
+
+// appendStreamEntry adds tx to the end of its tenant's stream. The entry expires
+// with the transaction. Callers serialise appends so each one extends the head it
+// read.
+func appendStreamEntry(txn *badger.Txn, tenantID string, tx *entity.Transaction, ttl time.Duration) error {
+	head, err := readStreamHead(txn, tenantID)
+	if err != nil {
+		return err
+	}
+	entry := entity.StreamEntry{Sequence: head + 1, Transaction: *tx}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream entry: %w", err)
+	}
+
+	record := badger.NewEntry(streamKey(tenantID, entry.Sequence), data)
+	if ttl > 0 {
+		record.WithTTL(ttl)
+	}
+	if err := txn.SetEntry(record); err != nil {
+		return err
+	}
+	return txn.Set(streamHeadKey(tenantID), binary.BigEndian.AppendUint64(nil, entry.Sequence))
+}
+
+// BadgerTransactionStreamRepository implements the transaction stream repository
+// interface using BadgerDB. Entries are written by BadgerTransactionRepository in
+// the same Badger transaction as the record they carry.
+type BadgerTransactionStreamRepository struct {
+	db      *badger.DB
+	logger  logger.Logger
+	metrics metrics.Metrics
+}
+
+// NewBadgerTransactionStreamRepository creates a new BadgerDB transaction stream repository
+func NewBadgerTransactionStreamRepository(db *badger.DB, log logger.Logger, m metrics.Metrics) repository.TransactionStreamRepository {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return &BadgerTransactionStreamRepository{
+		db:      db,
+		logger:  log,
+		metrics: m,
+	}
+}
+
+// observe records the latency of a database operation
+func (r *BadgerTransactionStreamRepository) observe(operation string, err error, start time.Time) {
+	r.metrics.ObserveDuration(metrics.DBOperationDuration, time.Since(start), map[string]string{
+		"operation": operation,
+		"outcome":   metrics.Outcome(err),
+	})
+}
+
+// Head returns the sequence number of the context tenant's latest entry
+func (r *BadgerTransactionStreamRepository) Head(ctx context.Context) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionStreamRepository.Head")
+	defer span.End()
+
+	var head uint64
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		var err error
+		head, err = readStreamHead(txn, tenant.IDFromContext(ctx))
+		return err
+	})
+	r.observe("stream_head", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to read transaction stream head: %w", err)
+	}
+	return head, nil
+}
+
+// ListAfter calls fn for up to limit of the context tenant's entries after the
+// given sequence number. The page is read first, so fn runs with no read
+// transaction open however long it takes.
+func (r *BadgerTransactionStreamRepository) ListAfter(ctx context.Context, after uint64, limit int, fn func(*entity.StreamEntry) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerTransactionStreamRepository.ListAfter")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+	prefix := streamPrefix(tenantID)
+
+	start := time.Now()
+	var page []*entity.StreamEntry
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true, PrefetchSize: limit})
+		defer it.Close()
+
+		for it.Seek(streamKey(tenantID, after+1)); it.ValidForPrefix(prefix) && len(page) < limit; it.Next() {
+			entry := &entity.StreamEntry{}
+			if err := it.Item().Value(func(val []byte) error { return json.Unmarshal(val, entry) }); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", it.Item().Key(), err)
+			}
+			page = append(page, entry)
+		}
+		return nil
+	})
+	r.observe("stream_list", err, start)
+
+	if err != nil {
+		tracing.SetError(span, err)
+		return fmt.Errorf("failed to read transaction stream: %w", err)
+	}
+
+	for _, entry := range page {
+		if err := fn(entry); err != nil {
+			tracing.SetError(span, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch signals changes to the context tenant's stream entries. Signals are
+// coalesced: a reader that is busy when several entries are added receives one.
+func (r *BadgerTransactionStreamRepository) Watch(ctx context.Context) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	match := []pb.Match{{Prefix: streamPrefix(tenant.IDFromContext(ctx))}}
+
+	go func() {
+		defer close(changes)
+		err := r.db.Subscribe(ctx, func(*badger.KVList) error {
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+			return nil
+		}, match)
+		if err != nil && ctx.Err() == nil {
+			logger.ForContext(ctx, r.logger).Error("Failed to watch transaction stream", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}()
+	return changes
+}
@@ -0,0 +1,114 @@
+// internal/infrastructure/db/badger_transaction_stream_repository_test.go
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerTransactionStreamRepository(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	txRepo := NewBadgerTransactionRepository(badgerDB, log, nil)
+	repo := NewBadgerTransactionStreamRepository(badgerDB, log, nil)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	globexCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "globex"})
+
+	store := func(ctx context.Context, id string, amount float64) {
+		_, err := txRepo.Store(ctx, &entity.Transaction{
+			ID:          id,
+			Description: "Fuel",
+			Date:        time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+			Amount:      amount,
+		})
+		require.NoError(t, err)
+	}
+	list := func(ctx context.Context, after uint64, limit int) []*entity.StreamEntry {
+		var entries []*entity.StreamEntry
+		require.NoError(t, repo.ListAfter(ctx, after, limit, func(entry *entity.StreamEntry) error {
+			entries = append(entries, entry)
+			return nil
+		}))
+		return entries
+	}
+
+	head, err := repo.Head(acmeCtx)
+	require.NoError(t, err)
+	assert.Zero(t, head)
+
+	for i := 1; i <= 3; i++ {
+		store(acmeCtx, fmt.Sprintf("tx-%d", i), float64(i*10))
+	}
+	store(globexCtx, "tx-g", 99)
+
+	t.Run("Each stored transaction gets the next sequence number", func(t *testing.T) {
+		entries := list(acmeCtx, 0, 10)
+		require.Len(t, entries, 3)
+		for i, entry := range entries {
+			assert.Equal(t, uint64(i+1), entry.Sequence)
+			assert.Equal(t, fmt.Sprintf("tx-%d", i+1), entry.Transaction.ID)
+			assert.Equal(t, "acme", entry.Transaction.TenantID)
+		}
+
+		head, err := repo.Head(acmeCtx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), head)
+	})
+
+	t.Run("Lists after a sequence number up to the limit", func(t *testing.T) {
+		entries := list(acmeCtx, 1, 1)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "tx-2", entries[0].Transaction.ID)
+		assert.Empty(t, list(acmeCtx, 3, 10))
+	})
+
+	t.Run("Errors from fn stop the listing", func(t *testing.T) {
+		errStop := errors.New("client gone")
+		calls := 0
+		err := repo.ListAfter(acmeCtx, 0, 10, func(*entity.StreamEntry) error {
+			calls++
+			return errStop
+		})
+		assert.Equal(t, errStop, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Tenants have their own streams", func(t *testing.T) {
+		entries := list(globexCtx, 0, 10)
+		require.Len(t, entries, 1)
+		assert.Equal(t, uint64(1), entries[0].Sequence)
+		assert.Equal(t, "tx-g", entries[0].Transaction.ID)
+	})
+
+	t.Run("Watch signals new entries of the tenant", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(acmeCtx)
+		changes := repo.Watch(ctx)
+
+		// The subscription starts asynchronously, so keep storing until it is seen
+		assert.Eventually(t, func() bool {
+			store(globexCtx, "tx-other", 1)
+			store(acmeCtx, "tx-watched", 1)
+			select {
+			case <-changes:
+				return true
+			case <-time.After(50 * time.Millisecond):
+				return false
+			}
+		}, 2*time.Second, time.Millisecond)
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			_, open := <-changes
+			return !open
+		}, 2*time.Second, time.Millisecond)
+	})
+}
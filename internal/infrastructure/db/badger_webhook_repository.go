@@ -0,0 +1,472 @@
+// Package db internal/infrastructure/db/badger_webhook_repository.go
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// webhookPendingPrefix is the key prefix of the queue of pending deliveries. The
+// queue is shared by all tenants; each entry holds the key of its delivery.
+const webhookPendingPrefix = "webhook-pending:"
+
+// DefaultWebhookHistoryRetention is how long delivered webhooks are kept
+const DefaultWebhookHistoryRetention = 7 * 24 * time.Hour
+
+// webhookSubscriptionKey builds the key of a tenant's webhook subscription
+func webhookSubscriptionKey(tenantID, id string) []byte {
+	return []byte("t:" + tenantID + ":webhook:" + id)
+}
+
+// webhookDeliveryKey builds the key of a tenant's webhook delivery
+func webhookDeliveryKey(tenantID, id string) []byte {
+	return []byte("t:" + tenantID + ":webhook-delivery:" + id)
+}
+
+// webhookHistoryKey builds the key of the index entry listing a delivery under its
+// subscription. The zero-padded creation time lists deliveries oldest first.
+func webhookHistoryKey(delivery *entity.WebhookDelivery) []byte {
+	return []byte(fmt.Sprintf("t:%s:webhook-history:%s:%020d:%s",
+		delivery.TenantID, delivery.SubscriptionID, delivery.CreatedAt.UnixNano(), delivery.ID))
+}
+
+// webhookPendingKey builds the key of a delivery's entry in the pending queue
+func webhookPendingKey(delivery *entity.WebhookDelivery) []byte {
+	return []byte(fmt.Sprintf("%s%020d:%s:%s", webhookPendingPrefix, delivery.CreatedAt.UnixNano(), delivery.TenantID, delivery.ID))
+}
+
+// webhookDeadKey builds the key of a delivery's entry in its tenant's dead-letter list
+func webhookDeadKey(tenantID, id string) []byte {
+	return []byte("t:" + tenantID + ":webhook-dead:" + id)
+}
+
+// BadgerWebhookRepository implements the webhook repository interface using
+// BadgerDB. Delivered webhooks expire after the history retention period; pending
+// and dead ones are kept until they change.
+type BadgerWebhookRepository struct {
+	db               *badger.DB
+	historyRetention time.Duration
+	logger           logger.Logger
+	metrics          metrics.Metrics
+}
+
+// NewBadgerWebhookRepository creates a new BadgerDB webhook repository that keeps
+// delivered webhooks for DefaultWebhookHistoryRetention
+func NewBadgerWebhookRepository(db *badger.DB, log logger.Logger, m metrics.Metrics) repository.WebhookRepository {
+	return NewBadgerWebhookRepositoryWithRetention(db, DefaultWebhookHistoryRetention, log, m)
+}
+
+// NewBadgerWebhookRepositoryWithRetention creates a new BadgerDB webhook repository
+// that keeps delivered webhooks for historyRetention
+func NewBadgerWebhookRepositoryWithRetention(db *badger.DB, historyRetention time.Duration, log logger.Logger, m metrics.Metrics) repository.WebhookRepository {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return &BadgerWebhookRepository{
+		db:               db,
+		historyRetention: historyRetention,
+		logger:           log,
+		metrics:          m,
+	}
+}
+
+// observe records the latency of a database operation
+func (r *BadgerWebhookRepository) observe(operation string, err error, start time.Time) {
+	r.metrics.ObserveDuration(metrics.DBOperationDuration, time.Since(start), map[string]string{
+		"operation": operation,
+		"outcome":   metrics.Outcome(err),
+	})
+}
+
+// CreateSubscription saves a new subscription for the context's tenant
+func (r *BadgerWebhookRepository) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.CreateSubscription")
+	defer span.End()
+
+	subscription.TenantID = tenant.IDFromContext(ctx)
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook subscription: %w", err)
+	}
+
+	start := time.Now()
+	err = r.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(webhookSubscriptionKey(subscription.TenantID, subscription.ID), data)
+	})
+	r.observe("webhook_create_subscription", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to store webhook subscription", map[string]interface{}{
+			"id":    subscription.ID,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to store webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetSubscription returns a subscription of the context's tenant
+func (r *BadgerWebhookRepository) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.GetSubscription")
+	defer span.End()
+
+	var subscription entity.WebhookSubscription
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(webhookSubscriptionKey(tenant.IDFromContext(ctx), id))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error { return json.Unmarshal(val, &subscription) })
+	})
+	r.observe("webhook_get_subscription", err, start)
+
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrSubscriptionNotFound, id)
+	}
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, fmt.Errorf("failed to read webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// ListSubscriptions calls fn for each subscription of the context's tenant
+func (r *BadgerWebhookRepository) ListSubscriptions(ctx context.Context, fn func(*entity.WebhookSubscription) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.ListSubscriptions")
+	defer span.End()
+
+	prefix := webhookSubscriptionKey(tenant.IDFromContext(ctx), "")
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true, PrefetchSize: 100})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var subscription entity.WebhookSubscription
+			if err := it.Item().Value(func(val []byte) error { return json.Unmarshal(val, &subscription) }); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", it.Item().Key(), err)
+			}
+			if err := fn(&subscription); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("webhook_list_subscriptions", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription removes a subscription of the context's tenant
+func (r *BadgerWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.DeleteSubscription")
+	defer span.End()
+
+	key := webhookSubscriptionKey(tenant.IDFromContext(ctx), id)
+
+	start := time.Now()
+	err := r.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get(key); err != nil {
+			return err
+		}
+		return txn.Delete(key)
+	})
+	r.observe("webhook_delete_subscription", err, start)
+
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return fmt.Errorf("%w: %s", repository.ErrSubscriptionNotFound, id)
+	}
+	if err != nil {
+		tracing.SetError(span, err)
+		logger.ForContext(ctx, r.logger).Error("Failed to delete webhook subscription", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// Enqueue saves new pending deliveries for the context's tenant, skipping any
+// already stored
+func (r *BadgerWebhookRepository) Enqueue(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.Enqueue")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+
+	start := time.Now()
+	err := r.db.Update(func(txn *badger.Txn) error {
+		for _, delivery := range deliveries {
+			delivery.TenantID = tenantID
+			_, err := txn.Get(webhookDeliveryKey(tenantID, delivery.ID))
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, badger.ErrKeyNotFound) {
+				return err
+			}
+			if err := r.setDelivery(txn, delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe("webhook_enqueue", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to enqueue webhook deliveries", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Due returns up to limit pending deliveries of any tenant that are due at now,
+// oldest first
+func (r *BadgerWebhookRepository) Due(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.Due")
+	defer span.End()
+
+	var due []*entity.WebhookDelivery
+	prefix := []byte(webhookPendingPrefix)
+
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix, PrefetchValues: true, PrefetchSize: 100})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix) && len(due) < limit; it.Next() {
+			deliveryKey, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			item, err := txn.Get(deliveryKey)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", deliveryKey, err)
+			}
+			delivery, err := decodeWebhookDelivery(item)
+			if err != nil {
+				return err
+			}
+			if delivery.NextAttemptAt.After(now) {
+				continue
+			}
+			due = append(due, delivery)
+		}
+		return nil
+	})
+	r.observe("webhook_due", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to read webhook queue", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to read webhook queue: %w", err)
+	}
+	return due, nil
+}
+
+// UpdateDelivery saves a delivery and moves it between the pending queue and the
+// dead-letter list to match its status
+func (r *BadgerWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.UpdateDelivery")
+	defer span.End()
+
+	start := time.Now()
+	err := r.db.Update(func(txn *badger.Txn) error {
+		if err := r.setDelivery(txn, delivery); err != nil {
+			return err
+		}
+		if delivery.Status != entity.DeliveryPending {
+			if err := txn.Delete(webhookPendingKey(delivery)); err != nil {
+				return err
+			}
+		}
+		if delivery.Status != entity.DeliveryDead {
+			return txn.Delete(webhookDeadKey(delivery.TenantID, delivery.ID))
+		}
+		return nil
+	})
+	r.observe("webhook_update_delivery", err, start)
+	tracing.SetError(span, err)
+
+	if err != nil {
+		logger.ForContext(ctx, r.logger).Error("Failed to update webhook delivery", map[string]interface{}{
+			"id":    delivery.ID,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// deliveryEntries returns the entries storing a delivery: the record, its history
+// index entry and its entry in the pending queue or dead-letter list. Delivered
+// webhooks expire after the history retention period.
+func (r *BadgerWebhookRepository) deliveryEntries(delivery *entity.WebhookDelivery) ([]*badger.Entry, error) {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+
+	entries := []*badger.Entry{
+		badger.NewEntry(webhookDeliveryKey(delivery.TenantID, delivery.ID), data),
+		badger.NewEntry(webhookHistoryKey(delivery), nil),
+	}
+	switch delivery.Status {
+	case entity.DeliveryPending:
+		entries = append(entries, badger.NewEntry(webhookPendingKey(delivery), webhookDeliveryKey(delivery.TenantID, delivery.ID)))
+	case entity.DeliveryDead:
+		entries = append(entries, badger.NewEntry(webhookDeadKey(delivery.TenantID, delivery.ID), nil))
+	case entity.DeliveryDelivered:
+		if r.historyRetention > 0 {
+			for _, entry := range entries {
+				entry.WithTTL(r.historyRetention)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// setDelivery writes the entries storing a delivery
+func (r *BadgerWebhookRepository) setDelivery(txn *badger.Txn, delivery *entity.WebhookDelivery) error {
+	entries, err := r.deliveryEntries(delivery)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := txn.SetEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDelivery returns a delivery of the context's tenant
+func (r *BadgerWebhookRepository) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.GetDelivery")
+	defer span.End()
+
+	var delivery *entity.WebhookDelivery
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(webhookDeliveryKey(tenant.IDFromContext(ctx), id))
+		if err != nil {
+			return err
+		}
+		delivery, err = decodeWebhookDelivery(item)
+		return err
+	})
+	r.observe("webhook_get_delivery", err, start)
+
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %s", repository.ErrDeliveryNotFound, id)
+	}
+	if err != nil {
+		tracing.SetError(span, err)
+		return nil, fmt.Errorf("failed to read webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// ListDeliveries calls fn for each delivery to a subscription of the context's
+// tenant, oldest first
+func (r *BadgerWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, fn func(*entity.WebhookDelivery) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.ListDeliveries")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+	prefix := []byte("t:" + tenantID + ":webhook-history:" + subscriptionID + ":")
+
+	err := r.listIndexed(prefix, tenantID, fn, "webhook_list_deliveries")
+	tracing.SetError(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters calls fn for each dead delivery of the context's tenant
+func (r *BadgerWebhookRepository) ListDeadLetters(ctx context.Context, fn func(*entity.WebhookDelivery) error) error {
+	ctx, span := tracing.Start(ctx, "BadgerWebhookRepository.ListDeadLetters")
+	defer span.End()
+
+	tenantID := tenant.IDFromContext(ctx)
+
+	err := r.listIndexed(webhookDeadKey(tenantID, ""), tenantID, fn, "webhook_list_dead_letters")
+	tracing.SetError(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	return nil
+}
+
+// listIndexed calls fn for the delivery named by each index key under prefix. The
+// delivery ID is the last part of the key.
+func (r *BadgerWebhookRepository) listIndexed(prefix []byte, tenantID string, fn func(*entity.WebhookDelivery) error, operation string) error {
+	start := time.Now()
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := string(it.Item().Key())
+			id := key[strings.LastIndexByte(key, ':')+1:]
+
+			item, err := txn.Get(webhookDeliveryKey(tenantID, id))
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				// The index entry outlived its expired delivery
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			delivery, err := decodeWebhookDelivery(item)
+			if err != nil {
+				return err
+			}
+			if err := fn(delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	r.observe(operation, err, start)
+	return err
+}
+
+// decodeWebhookDelivery decodes a stored webhook delivery
+func decodeWebhookDelivery(item *badger.Item) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &delivery) }); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", item.Key(), err)
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,159 @@
+// internal/infrastructure/db/badger_webhook_repository_test.go
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgerWebhookRepository(t *testing.T) {
+	badgerDB := openTestDB(t)
+	repo := NewBadgerWebhookRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	fleetCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "fleet"})
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// delivery returns a pending delivery of an event to a subscription
+	delivery := func(id, subscriptionID string, createdAt time.Time) *entity.WebhookDelivery {
+		return &entity.WebhookDelivery{
+			ID:             id,
+			SubscriptionID: subscriptionID,
+			Event:          entity.DomainEvent{ID: "event-" + id, Type: entity.EventTransactionCreated},
+			Status:         entity.DeliveryPending,
+			CreatedAt:      createdAt,
+		}
+	}
+
+	t.Run("Subscriptions are scoped to their tenant", func(t *testing.T) {
+		require.NoError(t, repo.CreateSubscription(acmeCtx, &entity.WebhookSubscription{
+			ID:     "sub-1",
+			URL:    "https://example.com/hook",
+			Events: []string{entity.EventTransactionCreated},
+			Secret: "whsec_test",
+		}))
+
+		subscription, err := repo.GetSubscription(acmeCtx, "sub-1")
+		require.NoError(t, err)
+		assert.Equal(t, "acme", subscription.TenantID)
+		assert.Equal(t, "whsec_test", subscription.Secret)
+
+		_, err = repo.GetSubscription(fleetCtx, "sub-1")
+		assert.ErrorIs(t, err, repository.ErrSubscriptionNotFound)
+
+		var ids []string
+		require.NoError(t, repo.ListSubscriptions(fleetCtx, func(s *entity.WebhookSubscription) error {
+			ids = append(ids, s.ID)
+			return nil
+		}))
+		assert.Empty(t, ids)
+	})
+
+	t.Run("Enqueue skips deliveries already stored", func(t *testing.T) {
+		require.NoError(t, repo.Enqueue(acmeCtx, []*entity.WebhookDelivery{
+			delivery("d-2", "sub-1", now.Add(-time.Minute)),
+			delivery("d-1", "sub-1", now.Add(-2*time.Minute)),
+		}))
+
+		// A second enqueue of d-1 must not reset its attempts
+		stored, err := repo.GetDelivery(acmeCtx, "d-1")
+		require.NoError(t, err)
+		stored.Record(entity.DeliveryAttempt{At: now, Error: "timeout"})
+		stored.NextAttemptAt = now.Add(time.Hour)
+		require.NoError(t, repo.UpdateDelivery(acmeCtx, stored))
+		require.NoError(t, repo.Enqueue(acmeCtx, []*entity.WebhookDelivery{delivery("d-1", "sub-1", now)}))
+
+		stored, err = repo.GetDelivery(acmeCtx, "d-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.Equal(t, "acme", stored.TenantID)
+	})
+
+	t.Run("Due returns pending deliveries whose attempt is due, oldest first", func(t *testing.T) {
+		due, err := repo.Due(context.Background(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, "d-2", due[0].ID)
+
+		due, err = repo.Due(context.Background(), now.Add(2*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 2)
+		assert.Equal(t, "d-1", due[0].ID)
+
+		due, err = repo.Due(context.Background(), now.Add(2*time.Hour), 1)
+		require.NoError(t, err)
+		assert.Len(t, due, 1)
+	})
+
+	t.Run("Status changes move deliveries between the queue and dead letters", func(t *testing.T) {
+		d1, err := repo.GetDelivery(acmeCtx, "d-1")
+		require.NoError(t, err)
+		d1.Status = entity.DeliveryDead
+		require.NoError(t, repo.UpdateDelivery(acmeCtx, d1))
+
+		d2, err := repo.GetDelivery(acmeCtx, "d-2")
+		require.NoError(t, err)
+		d2.Status = entity.DeliveryDelivered
+		require.NoError(t, repo.UpdateDelivery(acmeCtx, d2))
+
+		due, err := repo.Due(context.Background(), now.Add(2*time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		var dead []string
+		require.NoError(t, repo.ListDeadLetters(acmeCtx, func(d *entity.WebhookDelivery) error {
+			dead = append(dead, d.ID)
+			return nil
+		}))
+		assert.Equal(t, []string{"d-1"}, dead)
+
+		// Retrying returns the delivery to the queue
+		d1.Status = entity.DeliveryPending
+		require.NoError(t, repo.UpdateDelivery(acmeCtx, d1))
+		dead = nil
+		require.NoError(t, repo.ListDeadLetters(acmeCtx, func(d *entity.WebhookDelivery) error {
+			dead = append(dead, d.ID)
+			return nil
+		}))
+		assert.Empty(t, dead)
+		due, err = repo.Due(context.Background(), now.Add(2*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, "d-1", due[0].ID)
+	})
+
+	t.Run("Deliveries are listed by subscription, oldest first", func(t *testing.T) {
+		require.NoError(t, repo.Enqueue(acmeCtx, []*entity.WebhookDelivery{delivery("d-3", "sub-2", now)}))
+
+		var ids []string
+		require.NoError(t, repo.ListDeliveries(acmeCtx, "sub-1", func(d *entity.WebhookDelivery) error {
+			ids = append(ids, d.ID)
+			return nil
+		}))
+		assert.Equal(t, []string{"d-1", "d-2"}, ids)
+
+		ids = nil
+		require.NoError(t, repo.ListDeliveries(fleetCtx, "sub-1", func(d *entity.WebhookDelivery) error {
+			ids = append(ids, d.ID)
+			return nil
+		}))
+		assert.Empty(t, ids)
+	})
+
+	t.Run("Deleting a subscription keeps its history", func(t *testing.T) {
+		require.NoError(t, repo.DeleteSubscription(acmeCtx, "sub-1"))
+		assert.ErrorIs(t, repo.DeleteSubscription(acmeCtx, "sub-1"), repository.ErrSubscriptionNotFound)
+
+		_, err := repo.GetDelivery(acmeCtx, "d-2")
+		assert.NoError(t, err)
+		_, err = repo.GetDelivery(fleetCtx, "d-2")
+		assert.ErrorIs(t, err, repository.ErrDeliveryNotFound)
+	})
+}
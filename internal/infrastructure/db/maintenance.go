@@ -0,0 +1,234 @@
+// Package db internal/infrastructure/db/maintenance.go
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/dgraph-io/badger/v3"
+)
+
+// CompactResult reports what Compact did
+type CompactResult struct {
+	// Before and After are the LSM tree and value log sizes in bytes
+	Before, After int64
+	// ValueLogFilesRewritten is the number of value log files garbage collected
+	ValueLogFilesRewritten int
+}
+
+// Compact merges the LSM tree into a single level and rewrites value log files in
+// which at least discardRatio of the data is stale. It needs exclusive access.
+func Compact(badgerDB *badger.DB, discardRatio float64) (CompactResult, error) {
+	var result CompactResult
+	lsm, vlog := badgerDB.Size()
+	result.Before = lsm + vlog
+
+	if err := badgerDB.Flatten(2); err != nil {
+		return result, fmt.Errorf("failed to flatten LSM tree: %w", err)
+	}
+
+	rewritten, err := collectValueLogGarbage(badgerDB, discardRatio)
+	result.ValueLogFilesRewritten = rewritten
+	if err != nil {
+		return result, err
+	}
+
+	lsm, vlog = badgerDB.Size()
+	result.After = lsm + vlog
+	return result, nil
+}
+
+// collectValueLogGarbage rewrites value log files until none has at least
+// discardRatio stale data, returning the number rewritten. In-memory databases
+// have no value log.
+func collectValueLogGarbage(badgerDB *badger.DB, discardRatio float64) (int, error) {
+	rewritten := 0
+	if badgerDB.Opts().InMemory {
+		return rewritten, nil
+	}
+	for {
+		err := badgerDB.RunValueLogGC(discardRatio)
+		if errors.Is(err, badger.ErrNoRewrite) {
+			return rewritten, nil
+		}
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to collect value log garbage: %w", err)
+		}
+		rewritten++
+	}
+}
+
+// VerifyProblem is a stored record that failed verification
+type VerifyProblem struct {
+	Key string
+	Err error
+}
+
+// VerifyResult reports what Verify checked and found
+type VerifyResult struct {
+	Transactions int
+	Rates        int
+	Other        int
+	Problems     []VerifyProblem
+}
+
+// Verify checks block checksums, then decodes every transaction and exchange rate
+// and checks that each transaction is valid and stored under its own ID and tenant
+func Verify(badgerDB *badger.DB) (VerifyResult, error) {
+	var result VerifyResult
+
+	if err := badgerDB.VerifyChecksum(); err != nil {
+		return result, fmt.Errorf("checksum verification failed: %w", err)
+	}
+
+	err := badgerDB.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := string(item.Key())
+
+			var check func([]byte) error
+			switch tenantID, id, ok := parseTransactionKey(key); {
+			case ok:
+				result.Transactions++
+				check = func(val []byte) error { return verifyTransaction(val, tenantID, id) }
+			case strings.HasPrefix(key, exchangeRatePrefix):
+				result.Rates++
+				check = verifyExchangeRate
+			default:
+				result.Other++
+				continue
+			}
+
+			if err := item.Value(check); err != nil {
+				result.Problems = append(result.Problems, VerifyProblem{Key: key, Err: err})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to read database: %w", err)
+	}
+
+	return result, nil
+}
+
+// dateIndexBuiltKey records that every stored transaction has a date index entry
+var dateIndexBuiltKey = []byte("meta:date-index-built")
+
+// BuildDateIndex adds the missing date index entries of transactions stored before
+// the index was introduced, each expiring with its record, and returns the number
+// added. Once the index is complete it returns without reading any transaction.
+func BuildDateIndex(badgerDB *badger.DB) (int, error) {
+	err := badgerDB.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(dateIndexBuiltKey)
+		return err
+	})
+	if err == nil {
+		return 0, nil
+	}
+	if err != badger.ErrKeyNotFound {
+		return 0, fmt.Errorf("failed to read database: %w", err)
+	}
+
+	batch := badgerDB.NewWriteBatch()
+	defer batch.Cancel()
+
+	added := 0
+	err = badgerDB.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			tenantID, _, ok := parseTransactionKey(string(item.Key()))
+			if !ok {
+				continue
+			}
+
+			var tx entity.Transaction
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &tx) }); err != nil {
+				// Left for db verify to report
+				continue
+			}
+			key := dateKey(tenantID, &tx)
+			if _, err := txn.Get(key); err != badger.ErrKeyNotFound {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			entry := badger.NewEntry(key, nil)
+			entry.ExpiresAt = item.ExpiresAt()
+			if err := batch.SetEntry(entry); err != nil {
+				return err
+			}
+			added++
+		}
+		return nil
+	})
+	if err == nil {
+		err = batch.Set(dateIndexBuiltKey, nil)
+	}
+	if err == nil {
+		err = batch.Flush()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to build date index: %w", err)
+	}
+
+	return added, nil
+}
+
+// parseTransactionKey splits a transaction key into its tenant and ID. Legacy keys
+// belong to the default tenant.
+func parseTransactionKey(key string) (tenantID, id string, ok bool) {
+	if id, ok := strings.CutPrefix(key, legacyTransactionPrefix); ok {
+		return tenant.DefaultID, id, true
+	}
+
+	rest, ok := strings.CutPrefix(key, "t:")
+	if !ok {
+		return "", "", false
+	}
+	tenantID, id, ok = strings.Cut(rest, ":tx:")
+	return tenantID, id, ok
+}
+
+// verifyTransaction checks a stored transaction against its key
+func verifyTransaction(val []byte, tenantID, id string) error {
+	var tx entity.Transaction
+	if err := json.Unmarshal(val, &tx); err != nil {
+		return fmt.Errorf("cannot decode transaction: %w", err)
+	}
+	if tx.ID != id {
+		return fmt.Errorf("transaction ID %q does not match key", tx.ID)
+	}
+	// Records written before tenant isolation have no tenant
+	if tx.TenantID != "" && tx.TenantID != tenantID {
+		return fmt.Errorf("transaction tenant %q does not match key", tx.TenantID)
+	}
+	if err := tx.Validate(); err != nil {
+		return fmt.Errorf("invalid transaction: %w", err)
+	}
+	return nil
+}
+
+// verifyExchangeRate checks a stored exchange rate
+func verifyExchangeRate(val []byte) error {
+	var rate entity.ExchangeRate
+	if err := json.Unmarshal(val, &rate); err != nil {
+		return fmt.Errorf("cannot decode exchange rate: %w", err)
+	}
+	if rate.Rate <= 0 {
+		return fmt.Errorf("exchange rate %v is not positive", rate.Rate)
+	}
+	return nil
+}
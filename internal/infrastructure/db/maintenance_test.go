@@ -0,0 +1,150 @@
+// internal/infrastructure/db/maintenance_test.go
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	repo := NewBadgerTransactionRepository(badgerDB, log, nil)
+	ctx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	_, err := repo.Store(ctx, &entity.Transaction{
+		Description: "Fuel",
+		Date:        time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC),
+		Amount:      42.50,
+	})
+	require.NoError(t, err)
+	require.NoError(t, NewBadgerExchangeRateRepository(badgerDB, nil, log).StoreRate(ctx,
+		&entity.ExchangeRate{Currency: "EUR", Date: time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC), Rate: 0.92}))
+
+	t.Run("Consistent database", func(t *testing.T) {
+		result, err := Verify(badgerDB)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Transactions)
+		assert.Equal(t, 1, result.Rates)
+		assert.Empty(t, result.Problems)
+	})
+
+	t.Run("Problems are reported per key", func(t *testing.T) {
+		require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+			// Stored under another tenant's key
+			if err := txn.Set(transactionKey("globex", "moved"),
+				[]byte(`{"id":"moved","tenant_id":"acme","description":"x","date":"2023-01-01T00:00:00Z","amount":1}`)); err != nil {
+				return err
+			}
+			// Not JSON
+			if err := txn.Set([]byte(legacyTransactionPrefix+"garbled"), []byte("{")); err != nil {
+				return err
+			}
+			// Fails validation
+			return txn.Set(transactionKey("acme", "negative"),
+				[]byte(`{"id":"negative","tenant_id":"acme","description":"x","date":"2023-01-01T00:00:00Z","amount":-5}`))
+		}))
+
+		result, err := Verify(badgerDB)
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Transactions)
+		require.Len(t, result.Problems, 3)
+
+		problems := make(map[string]string)
+		for _, problem := range result.Problems {
+			problems[problem.Key] = problem.Err.Error()
+		}
+		assert.Contains(t, problems["t:globex:tx:moved"], "tenant")
+		assert.Contains(t, problems["tx:garbled"], "decode")
+		assert.Contains(t, problems["t:acme:tx:negative"], "amount")
+	})
+}
+
+func TestCompact(t *testing.T) {
+	badgerDB, err := badger.Open(badger.DefaultOptions(t.TempDir()).WithLogger(nil))
+	require.NoError(t, err)
+	defer badgerDB.Close()
+
+	// Overwrite the same keys so there is something to discard
+	for round := 0; round < 3; round++ {
+		require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+			for i := 0; i < 100; i++ {
+				if err := txn.Set([]byte{byte(i)}, []byte{byte(round)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+
+	_, err = Compact(badgerDB, 0.5)
+	require.NoError(t, err)
+
+	// Data survives compaction
+	require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte{7})
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			assert.Equal(t, []byte{2}, val)
+			return nil
+		})
+	}))
+}
+
+func TestBuildDateIndex(t *testing.T) {
+	badgerDB := openTestDB(t)
+	repo := NewBadgerTransactionRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+	ctx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	_, err := repo.Store(ctx, &entity.Transaction{ID: "indexed", Description: "Fuel",
+		Date: time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC), Amount: 1})
+	require.NoError(t, err)
+	// Records written before the index
+	require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(transactionKey("acme", "unindexed"),
+			[]byte(`{"id":"unindexed","tenant_id":"acme","description":"x","date":"2023-04-01T00:00:00Z","amount":1}`)); err != nil {
+			return err
+		}
+		return txn.Set([]byte(legacyTransactionPrefix+"legacy"),
+			[]byte(`{"id":"legacy","description":"Legacy","date":"2023-03-01T00:00:00Z","amount":1}`))
+	}))
+
+	added, err := BuildDateIndex(badgerDB)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+
+	list := func(ctx context.Context) []string {
+		var ids []string
+		require.NoError(t, repo.ListByDate(ctx, time.Time{}, time.Time{}, func(tx *entity.Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		}))
+		return ids
+	}
+	assert.Equal(t, []string{"unindexed", "indexed"}, list(ctx))
+	assert.Equal(t, []string{"legacy"}, list(context.Background()))
+
+	// The index is only built once
+	require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+		return txn.Set(transactionKey("acme", "later"),
+			[]byte(`{"id":"later","tenant_id":"acme","description":"x","date":"2023-04-01T00:00:00Z","amount":1}`))
+	}))
+	added, err = BuildDateIndex(badgerDB)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	result, err := Verify(badgerDB)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Transactions)
+	assert.Empty(t, result.Problems)
+}
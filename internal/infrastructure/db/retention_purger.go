@@ -0,0 +1,284 @@
+// Package db internal/infrastructure/db/retention_purger.go
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/google/uuid"
+)
+
+// purgeDiscardRatio is the share of stale data a value log file must hold to be
+// rewritten after a purge
+const purgeDiscardRatio = 0.5
+
+// expiryAnnounced is the value of an expiry marker once a TransactionDeleted event
+// has been added to the outbox for its transaction
+var expiryAnnounced = []byte("announced")
+
+// PurgeResult reports what a purge did
+type PurgeResult struct {
+	// Purged is the number of expired transactions deleted
+	Purged int
+	// ValueLogFilesRewritten is the number of value log files garbage collected
+	ValueLogFilesRewritten int
+	// Announced is the number of TransactionDeleted events added to the outbox
+	Announced int
+}
+
+// RetentionPurger deletes transactions whose retention period has passed and
+// reclaims the space they used. Badger already hides records written with a TTL
+// once they expire; the purge removes records that have none, such as those
+// written before Badger TTLs were used, and lets value log GC drop the rest.
+//
+// When events are published, each deleted transaction is announced with a
+// TransactionDeleted event. Records Badger expired are found through the expiry
+// markers that outlive them, so they are only announced while the gone period
+// keeps a marker.
+type RetentionPurger struct {
+	db        *badger.DB
+	retention RetentionConfig
+	logger    logger.Logger
+	metrics   metrics.Metrics
+	now       func() time.Time
+}
+
+// NewRetentionPurger creates a purger for the transactions in db
+func NewRetentionPurger(db *badger.DB, retention RetentionConfig, log logger.Logger, m metrics.Metrics) *RetentionPurger {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return &RetentionPurger{
+		db:        db,
+		retention: retention,
+		logger:    log,
+		metrics:   m,
+		now:       time.Now,
+	}
+}
+
+// Run purges every interval until ctx is cancelled
+func (p *RetentionPurger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.Purge(ctx); err != nil {
+				p.logger.Error("Retention purge failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// Purge deletes every expired transaction of every tenant and its date index
+// entry, leaving an expiry marker so lookups still report it as expired, then collects value log garbage
+func (p *RetentionPurger) Purge(ctx context.Context) (PurgeResult, error) {
+	var result PurgeResult
+	now := p.now()
+
+	expired, vanished, err := p.findExpired(ctx, now)
+	if err != nil {
+		return result, err
+	}
+
+	batch := p.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, record := range expired {
+		if err := batch.Delete(record.key); err != nil {
+			return result, fmt.Errorf("failed to delete expired transaction: %w", err)
+		}
+		if err := batch.Delete(dateKey(record.tenantID, record.tx)); err != nil {
+			return result, fmt.Errorf("failed to delete expired transaction: %w", err)
+		}
+		if marker := p.markerEntry(record.tenantID, record.tx, now); marker != nil {
+			if err := batch.SetEntry(marker); err != nil {
+				return result, fmt.Errorf("failed to mark expired transaction: %w", err)
+			}
+		}
+		if err := p.announce(batch, record.tenantID, record.tx.ID, now); err != nil {
+			return result, err
+		}
+	}
+	for _, marker := range vanished {
+		// Keep the marker for the rest of its gone period, recording the announcement
+		entry := badger.NewEntry(marker.key, expiryAnnounced)
+		entry.ExpiresAt = marker.expiresAt
+		if err := batch.SetEntry(entry); err != nil {
+			return result, fmt.Errorf("failed to mark expired transaction: %w", err)
+		}
+		if err := p.announce(batch, marker.tenantID, marker.id, now); err != nil {
+			return result, err
+		}
+	}
+	if err := batch.Flush(); err != nil {
+		return result, fmt.Errorf("failed to purge expired transactions: %w", err)
+	}
+	result.Purged = len(expired)
+	for range expired {
+		p.metrics.IncCounter(metrics.TransactionsPurgedTotal, nil)
+	}
+	if p.retention.PublishEvents {
+		result.Announced = len(expired) + len(vanished)
+	}
+
+	rewritten, err := collectValueLogGarbage(p.db, purgeDiscardRatio)
+	result.ValueLogFilesRewritten = rewritten
+	if err != nil {
+		return result, err
+	}
+
+	p.logger.Info("Retention purge completed", map[string]interface{}{
+		"purged":                    result.Purged,
+		"announced":                 result.Announced,
+		"value_log_files_rewritten": result.ValueLogFilesRewritten,
+	})
+	return result, nil
+}
+
+// announce adds the TransactionDeleted event of an expired transaction to batch
+// when events are published
+func (p *RetentionPurger) announce(batch *badger.WriteBatch, tenantID, id string, now time.Time) error {
+	if !p.retention.PublishEvents {
+		return nil
+	}
+
+	event, err := entity.NewTransactionDeletedEvent(uuid.New().String(), id, "expired", now.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	event.TenantID = tenantID
+	entry, err := newOutboxEntry(event)
+	if err != nil {
+		return err
+	}
+	if err := batch.SetEntry(entry); err != nil {
+		return fmt.Errorf("failed to add event to outbox: %w", err)
+	}
+	return nil
+}
+
+// expiredRecord is a stored transaction that is due for deletion
+type expiredRecord struct {
+	key      []byte
+	tenantID string
+	tx       *entity.Transaction
+}
+
+// vanishedRecord is the expiry marker of a transaction that Badger expired and
+// that has not been announced yet
+type vanishedRecord struct {
+	key       []byte
+	tenantID  string
+	id        string
+	expiresAt uint64
+}
+
+// findExpired returns the stored transactions that have expired at now and, when
+// events are published, the unannounced transactions Badger has expired
+func (p *RetentionPurger) findExpired(ctx context.Context, now time.Time) ([]expiredRecord, []vanishedRecord, error) {
+	var expired []expiredRecord
+	var vanished []vanishedRecord
+
+	err := p.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item := it.Item()
+			if p.retention.PublishEvents {
+				marker, err := p.unannouncedMarker(txn, item)
+				if err != nil {
+					return err
+				}
+				if marker != nil {
+					vanished = append(vanished, *marker)
+					continue
+				}
+			}
+
+			tenantID, _, ok := parseTransactionKey(string(item.Key()))
+			if !ok {
+				continue
+			}
+
+			var tx entity.Transaction
+			if err := item.Value(func(val []byte) error { return json.Unmarshal(val, &tx) }); err != nil {
+				// Left for db verify to report
+				continue
+			}
+			if tx.Expired(now) {
+				expired = append(expired, expiredRecord{key: item.KeyCopy(nil), tenantID: tenantID, tx: &tx})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan for expired transactions: %w", err)
+	}
+
+	return expired, vanished, nil
+}
+
+// unannouncedMarker returns item if it is the expiry marker of a transaction that
+// is gone and has not been announced, or nil
+func (p *RetentionPurger) unannouncedMarker(txn *badger.Txn, item *badger.Item) (*vanishedRecord, error) {
+	tenantID, id, ok := parseExpiredKey(string(item.Key()))
+	if !ok || item.ValueSize() > 0 {
+		return nil, nil
+	}
+
+	// Markers are written with the record, so one whose record is still readable
+	// belongs to a live transaction
+	_, err := txn.Get(transactionKey(tenantID, id))
+	switch {
+	case err == nil:
+		return nil, nil
+	case err != badger.ErrKeyNotFound:
+		return nil, err
+	}
+	return &vanishedRecord{key: item.KeyCopy(nil), tenantID: tenantID, id: id, expiresAt: item.ExpiresAt()}, nil
+}
+
+// markerEntry returns the expiry marker for a purged transaction, or nil if it
+// would already have expired
+func (p *RetentionPurger) markerEntry(tenantID string, tx *entity.Transaction, now time.Time) *badger.Entry {
+	ttl := tx.ExpiresAt().Add(p.retention.GonePeriod).Sub(now)
+	if p.retention.GonePeriod <= 0 || ttl <= 0 {
+		return nil
+	}
+	entry := badger.NewEntry(expiredKey(tenantID, tx.ID), nil).WithTTL(ttl)
+	if p.retention.PublishEvents {
+		entry.Value = expiryAnnounced
+	}
+	return entry
+}
+
+// parseExpiredKey splits an expiry marker key into its tenant and transaction ID
+func parseExpiredKey(key string) (tenantID, id string, ok bool) {
+	rest, ok := strings.CutPrefix(key, "t:")
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":expired:")
+}
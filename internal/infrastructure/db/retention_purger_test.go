@@ -0,0 +1,157 @@
+// internal/infrastructure/db/retention_purger_test.go
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPurger(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	retention := RetentionConfig{ReportExpired: true, GonePeriod: 365 * 24 * time.Hour}
+	repo := NewBadgerTransactionRepositoryWithRetention(badgerDB, retention, log, nil)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	// Expired a month ago, within the gone period
+	createdAt := time.Now().Add(-entity.DefaultRetention - 30*24*time.Hour)
+	expired := &entity.Transaction{ID: "expired", Description: "Fuel", Date: createdAt, Amount: 10, CreatedAt: createdAt}
+	expired.CalculateTTL(entity.DefaultRetention)
+	_, err := repo.Store(acmeCtx, expired)
+	require.NoError(t, err)
+
+	live := &entity.Transaction{ID: "live", Description: "Tires", Date: time.Now(), Amount: 20, CreatedAt: time.Now()}
+	live.CalculateTTL(entity.DefaultRetention)
+	_, err = repo.Store(acmeCtx, live)
+	require.NoError(t, err)
+
+	// A record written before TTLs were stored never expires
+	require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(legacyTransactionPrefix+"legacy"), []byte(`{"id":"legacy","description":"Legacy","amount":1}`))
+	}))
+
+	purger := NewRetentionPurger(badgerDB, retention, log, nil)
+	result, err := purger.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Purged)
+
+	require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(transactionKey("acme", "expired"))
+		assert.ErrorIs(t, err, badger.ErrKeyNotFound)
+		_, err = txn.Get(expiredKey("acme", "expired"))
+		assert.NoError(t, err, "purged records leave a marker")
+		_, err = txn.Get(dateKey("acme", expired))
+		assert.ErrorIs(t, err, badger.ErrKeyNotFound, "purged records leave the date index")
+		return nil
+	}))
+
+	_, err = repo.FindByID(acmeCtx, "expired")
+	assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+	_, err = repo.FindByID(acmeCtx, "live")
+	assert.NoError(t, err)
+	_, err = repo.FindByID(context.Background(), "legacy")
+	assert.NoError(t, err)
+
+	// Nothing is left to purge
+	result, err = purger.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.Purged)
+}
+
+func TestRetentionPurgerSkipsLegalHolds(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	repo := NewBadgerTransactionRepository(badgerDB, log, nil)
+	ctx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	for _, id := range []string{"held", "released"} {
+		tx := &entity.Transaction{ID: id, Description: "Fuel", Date: time.Now(), Amount: 10, CreatedAt: time.Now()}
+		tx.CalculateTTL(entity.DefaultRetention)
+		_, err := repo.Store(ctx, tx)
+		require.NoError(t, err)
+		_, err = repo.PlaceLegalHold(ctx, id, entity.LegalHold{Reason: "Audit", PlacedBy: "auditor", PlacedAt: time.Now()}, nil, nil)
+		require.NoError(t, err)
+	}
+	_, err := repo.ReleaseLegalHold(ctx, "released", nil, nil)
+	require.NoError(t, err)
+
+	// Purge as if both retention periods had passed
+	purger := NewRetentionPurger(badgerDB, DefaultRetentionConfig(), log, nil)
+	purger.now = func() time.Time { return time.Now().Add(2 * entity.DefaultRetention) }
+	result, err := purger.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Purged)
+
+	require.NoError(t, badgerDB.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(transactionKey("acme", "held"))
+		assert.NoError(t, err, "held records are kept")
+		_, err = txn.Get(transactionKey("acme", "released"))
+		assert.ErrorIs(t, err, badger.ErrKeyNotFound)
+		return nil
+	}))
+}
+
+func TestRetentionPurgerAnnouncesDeletions(t *testing.T) {
+	badgerDB := openTestDB(t)
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	retention := RetentionConfig{ReportExpired: true, GonePeriod: 365 * 24 * time.Hour, PublishEvents: true}
+	repo := NewBadgerTransactionRepositoryWithRetention(badgerDB, retention, log, nil)
+	outbox := NewBadgerOutboxRepository(badgerDB, log, nil)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	// Expired before it was stored, so it has no Badger TTL and is purged
+	createdAt := time.Now().Add(-entity.DefaultRetention - 24*time.Hour)
+	expired := &entity.Transaction{ID: "expired", Description: "Fuel", Date: createdAt, Amount: 10, CreatedAt: createdAt}
+	expired.CalculateTTL(entity.DefaultRetention)
+	_, err := repo.Store(acmeCtx, expired)
+	require.NoError(t, err)
+
+	live := &entity.Transaction{ID: "live", Description: "Tires", Date: time.Now(), Amount: 20, CreatedAt: time.Now()}
+	live.CalculateTTL(entity.DefaultRetention)
+	_, err = repo.Store(acmeCtx, live)
+	require.NoError(t, err)
+
+	// Only the marker is left of a transaction Badger has expired
+	require.NoError(t, badgerDB.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry(expiredKey("acme", "vanished"), nil).WithTTL(time.Hour))
+	}))
+
+	purger := NewRetentionPurger(badgerDB, retention, log, nil)
+	result, err := purger.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Purged)
+	assert.Equal(t, 2, result.Announced)
+
+	due, err := outbox.Due(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	deleted := map[string]bool{}
+	for _, entry := range due {
+		assert.Equal(t, entity.EventTransactionDeleted, entry.Event.Type)
+		assert.Equal(t, "acme", entry.Event.TenantID)
+		deleted[entry.Event.AggregateID] = true
+	}
+	assert.Equal(t, map[string]bool{"expired": true, "vanished": true}, deleted)
+
+	t.Run("Markers are kept for the gone period", func(t *testing.T) {
+		_, err := repo.FindByID(acmeCtx, "vanished")
+		assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+		_, err = repo.FindByID(acmeCtx, "expired")
+		assert.ErrorIs(t, err, repository.ErrTransactionExpired)
+	})
+
+	t.Run("Deletions are announced once", func(t *testing.T) {
+		result, err := purger.Purge(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, result.Announced)
+	})
+}
@@ -0,0 +1,63 @@
+// Package export internal/infrastructure/export/csv.go
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVWriter writes a table as CSV. Each row is flushed once written.
+type CSVWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// NewCSVWriter creates a CSV writer writing to w
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// WriteRow writes a record. Dates are written as YYYY-MM-DD, and strings that a
+// spreadsheet would evaluate as a formula are prefixed with an apostrophe.
+func (c *CSVWriter) WriteRow(cells ...interface{}) error {
+	c.record = c.record[:0]
+	for _, cell := range cells {
+		if err := checkCell(cell); err != nil {
+			return err
+		}
+		var field string
+		switch v := cell.(type) {
+		case string:
+			field = escapeFormula(v)
+		case float64:
+			field = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			field = v.Format(dateLayout)
+		}
+		c.record = append(c.record, field)
+	}
+
+	if err := c.w.Write(c.record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Close flushes any buffered output
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula keeps a spreadsheet from evaluating a string, such as a
+// transaction description, that starts like a formula
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+// internal/infrastructure/export/csv_test.go
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+
+	require.NoError(t, w.WriteRow("ID", "Date", "Description", "Amount", "Rate"))
+	// Each row is written out as soon as it is complete
+	assert.Equal(t, "ID,Date,Description,Amount,Rate\n", buf.String())
+
+	require.NoError(t, w.WriteRow("a", time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), "Coffee, large", 12.5, nil))
+	require.NoError(t, w.WriteRow("b", time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), "=HYPERLINK(\"x\")", 3.0, 0.912))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "ID,Date,Description,Amount,Rate\n"+
+		"a,2023-01-15,\"Coffee, large\",12.5,\n"+
+		"b,2023-02-01,\"'=HYPERLINK(\"\"x\"\")\",3,0.912\n", buf.String())
+
+	assert.Error(t, w.WriteRow(42))
+}
+
+func TestNewWriter(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		w, err := NewWriter(format, &bytes.Buffer{})
+		require.NoError(t, err, format)
+		assert.NoError(t, w.Close(), format)
+	}
+
+	_, err := NewWriter("ods", &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+// Package export internal/infrastructure/export/writer.go
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Formats an export can be written in
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer writes a table one row at a time, so exports need not be held in memory.
+// Cells are strings, float64 numbers, time.Time dates or nil for an empty cell.
+type Writer interface {
+	// WriteRow writes the next row of the table
+	WriteRow(cells ...interface{}) error
+	// Close completes the table. It does not close the underlying writer.
+	Close() error
+}
+
+// NewWriter creates a writer of the given format writing to w
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w, "Transactions")
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// dateLayout is the layout of dates in CSV exports
+const dateLayout = "2006-01-02"
+
+// checkCell rejects cell values a writer cannot write
+func checkCell(cell interface{}) error {
+	switch cell.(type) {
+	case nil, string, float64, time.Time:
+		return nil
+	default:
+		return fmt.Errorf("unsupported cell type %T", cell)
+	}
+}
@@ -0,0 +1,105 @@
+// internal/infrastructure/export/xlsx_test.go
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sheet is the part of a worksheet the tests read
+type sheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			S      string `xml:"s,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readSheet(t *testing.T, data []byte) (map[string]bool, sheet) {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := make(map[string]bool)
+	var result sheet
+	for _, file := range archive.File {
+		parts[file.Name] = true
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+
+		// Every part must be well-formed XML
+		var wellFormed struct{}
+		require.NoError(t, xml.Unmarshal(content, &wellFormed), file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			require.NoError(t, xml.Unmarshal(content, &result))
+		}
+	}
+	return parts, result
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf, "Q1 <draft>")
+	require.NoError(t, err)
+
+	require.NoError(t, w.WriteRow("ID", "Date", "Description", "Amount"))
+	require.NoError(t, w.WriteRow("a", time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), "Fish & chips =SUM(A1)", 12.5))
+	require.NoError(t, w.WriteRow("b", nil, "", 0.1))
+	require.NoError(t, w.Close())
+
+	parts, s := readSheet(t, buf.Bytes())
+	for _, part := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.True(t, parts[part], part)
+	}
+
+	require.Len(t, s.Rows, 3)
+	assert.Equal(t, 2, s.Rows[1].R)
+	cells := s.Rows[1].Cells
+	require.Len(t, cells, 4)
+	assert.Equal(t, "A2", cells[0].R)
+	assert.Equal(t, "inlineStr", cells[0].T)
+	assert.Equal(t, "a", cells[0].Inline)
+	// 2023-01-15 is day 44941 of the spreadsheet calendar, shown with the date format
+	assert.Equal(t, "44941", cells[1].V)
+	assert.Equal(t, "1", cells[1].S)
+	assert.Equal(t, "Fish & chips =SUM(A1)", cells[2].Inline)
+	assert.Equal(t, "12.5", cells[3].V)
+
+	// Empty cells are skipped, keeping the references of the others
+	cells = s.Rows[2].Cells
+	require.Len(t, cells, 3)
+	assert.Equal(t, "C3", cells[1].R)
+	assert.Equal(t, "D3", cells[2].R)
+}
+
+func TestXLSXWriterRejectsInvalidCells(t *testing.T) {
+	w, err := NewXLSXWriter(io.Discard, "Sheet")
+	require.NoError(t, err)
+
+	assert.Error(t, w.WriteRow(42))
+	assert.Error(t, w.WriteRow(1.0/zero()))
+}
+
+func zero() float64 { return 0 }
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, want, columnName(i), i)
+	}
+}
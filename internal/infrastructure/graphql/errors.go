@@ -0,0 +1,59 @@
+// Package graphql internal/infrastructure/graphql/errors.go
+package graphql
+
+import (
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+)
+
+// Error codes reported in the extensions of a GraphQL error
+const (
+	codeBadInput        = "BAD_USER_INPUT"
+	codeForbidden       = "FORBIDDEN"
+	codeNotFound        = "NOT_FOUND"
+	codeRateUnavailable = "EXCHANGE_RATE_UNAVAILABLE"
+	codeUnavailable     = "SERVICE_UNAVAILABLE"
+	codeInternal        = "INTERNAL"
+)
+
+// resolverError is an error returned to the client, whose code is added to the
+// error's extensions
+type resolverError struct {
+	code    string
+	message string
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+// Extensions implements the extensions of graph-gophers errors
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+func newError(code, message string) error {
+	return &resolverError{code: code, message: message}
+}
+
+// queryError converts a service error into the error the client sees, classified
+// as the REST handlers classify it
+func queryError(err error) error {
+	switch service.Classify(err) {
+	case service.ErrorCanceled:
+		return newError(codeUnavailable, "request cancelled")
+	case service.ErrorDeadlineExceeded:
+		return newError(codeUnavailable, "request deadline exceeded")
+	case service.ErrorExpired:
+		return newError(codeNotFound, "transaction has passed its retention period")
+	case service.ErrorNotFound:
+		return newError(codeNotFound, "transaction not found")
+	case service.ErrorInvalid:
+		return newError(codeBadInput, err.Error())
+	case service.ErrorNoExchangeRate, service.ErrorRateOutOfRange:
+		return newError(codeRateUnavailable, service.RateErrorMessage(err))
+	case service.ErrorRateServiceUnavailable:
+		return newError(codeUnavailable, "the exchange rate service is temporarily unavailable")
+	default:
+		return newError(codeInternal, "an unexpected error occurred")
+	}
+}
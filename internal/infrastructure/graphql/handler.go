@@ -0,0 +1,125 @@
+// Package graphql internal/infrastructure/graphql/handler.go
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/gorilla/mux"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Config holds the settings of the GraphQL endpoint
+type Config struct {
+	// Policy restricts fields such as AuditTrailField. Fields are unrestricted
+	// when it is nil, as when authentication is disabled.
+	Policy *auth.Policy
+	// MaxDepth limits the nesting of queries; zero means no limit
+	MaxDepth int
+}
+
+// Handler serves GraphQL queries over the transaction, conversion and audit
+// services
+type Handler struct {
+	schema *graphql.Schema
+	logger logger.Logger
+}
+
+// request is a GraphQL request, sent as a JSON body or as query parameters
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewHandler creates a new GraphQL handler
+func NewHandler(transactions *service.TransactionService, conversions *service.ConversionService, audit *service.AuditService, config Config, log logger.Logger) *Handler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	var opts []graphql.SchemaOpt
+	if config.MaxDepth > 0 {
+		opts = append(opts, graphql.MaxDepth(config.MaxDepth))
+	}
+	// The schema is a constant, so it only fails to parse while being written
+	schema := graphql.MustParseSchema(schema, &resolver{
+		transactions: transactions,
+		conversions:  conversions,
+		audit:        audit,
+		policy:       config.Policy,
+	}, opts...)
+
+	return &Handler{
+		schema: schema,
+		logger: log,
+	}
+}
+
+// ServeHTTP executes a query. Errors resolving fields are reported in the
+// response's errors with a 200 status, as GraphQL clients expect.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := logger.ForContext(r.Context(), h.logger)
+
+	var req request
+	if r.Method == http.MethodGet {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeRequestError(w, "variables must be a JSON object")
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRequestError(w, "the request body must be a JSON object with a query")
+		return
+	}
+	if req.Query == "" {
+		writeRequestError(w, "a query is required")
+		return
+	}
+
+	resp := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, err := range resp.Errors {
+			messages[i] = err.Message
+		}
+		log.Warn("GraphQL query returned errors", map[string]interface{}{
+			"operation": req.OperationName,
+			"errors":    messages,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeRequestError reports a request that is not a GraphQL request
+func writeRequestError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    message,
+			"extensions": map[string]interface{}{"code": codeBadInput},
+		}},
+	})
+}
+
+// RegisterRoutes registers the GraphQL routes
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.Handle("/graphql", h).Methods("GET", "POST")
+
+	h.logger.Info("GraphQL routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /graphql",
+			"POST /graphql",
+		},
+	})
+}
@@ -0,0 +1,280 @@
+// internal/infrastructure/graphql/handler_test.go
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// response is a GraphQL response
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// testServer is a GraphQL handler over in-memory services
+type testServer struct {
+	handler      *Handler
+	transactions *service.TransactionService
+	rates        *mocks.MockExchangeRateRepository
+}
+
+func newTestServer(t *testing.T, config Config) *testServer {
+	t.Helper()
+
+	badgerDB, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { badgerDB.Close() })
+
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
+	audit := service.NewAuditService(db.NewBadgerAuditRepository(badgerDB, log, nil), log)
+	rates := new(mocks.MockExchangeRateRepository)
+	transactions := service.NewTransactionServiceWithConfig(txRepo, service.TransactionServiceConfig{Audit: audit}, log)
+	conversions := service.NewConversionServiceWithAudit(txRepo, rates, audit, log)
+
+	return &testServer{
+		handler:      NewHandler(transactions, conversions, audit, config, log),
+		transactions: transactions,
+		rates:        rates,
+	}
+}
+
+func (s *testServer) create(t *testing.T, description string, date time.Time, amount float64) string {
+	t.Helper()
+	id, err := s.transactions.CreateTransaction(context.Background(), description, date, amount)
+	require.NoError(t, err)
+	return id
+}
+
+// query posts a query as principal, which may be nil
+func (s *testServer) query(t *testing.T, principal *auth.Principal, query string, variables map[string]interface{}) response {
+	t.Helper()
+
+	body, err := json.Marshal(request{Query: query, Variables: variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	if principal != nil {
+		req = req.WithContext(middleware.WithPrincipal(req.Context(), principal))
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestHandlerBatchesConversions(t *testing.T) {
+	server := newTestServer(t, Config{})
+	jan := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC)
+	server.create(t, "Coffee", jan, 10)
+	server.create(t, "Lunch", jan, 20)
+	server.create(t, "Dinner", feb, 40)
+
+	// Each currency and date is looked up once, however many transactions share it
+	server.rates.On("FindRate", mock.Anything, "EUR", jan).
+		Return(&entity.ExchangeRate{Currency: "EUR", Date: jan, Rate: 0.9}, nil).Once()
+	server.rates.On("FindRate", mock.Anything, "EUR", feb).
+		Return(&entity.ExchangeRate{Currency: "EUR", Date: feb, Rate: 0.8}, nil).Once()
+	server.rates.On("FindRate", mock.Anything, "GBP", jan).
+		Return(&entity.ExchangeRate{Currency: "GBP", Date: jan, Rate: 0.8}, nil).Once()
+	server.rates.On("FindRate", mock.Anything, "GBP", feb).
+		Return(nil, &repository.LookbackError{Months: 6, Err: fmt.Errorf("%w for GBP", repository.ErrNoExchangeRate)}).Once()
+
+	resp := server.query(t, nil, `{
+		transactions(first: 10) {
+			transactions {
+				description
+				eur: convertedTo(currency: "EUR") { convertedAmount exchangeRate { rate date } }
+				gbp: convertedTo(currency: "GBP") { convertedAmount }
+				auditTrail { action }
+			}
+			nextCursor
+		}
+	}`, nil)
+	server.rates.AssertExpectations(t)
+
+	var data struct {
+		Transactions struct {
+			Transactions []struct {
+				Description string
+				EUR         *struct {
+					ConvertedAmount float64
+					ExchangeRate    struct {
+						Rate float64
+						Date string
+					}
+				}
+				GBP *struct {
+					ConvertedAmount float64
+				}
+				AuditTrail []struct{ Action string }
+			}
+			NextCursor *string
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	assert.Nil(t, data.Transactions.NextCursor)
+
+	amounts := map[string][2]float64{"Coffee": {9, 8}, "Lunch": {18, 16}, "Dinner": {32, 0}}
+	require.Len(t, data.Transactions.Transactions, 3)
+	for _, tx := range data.Transactions.Transactions {
+		want := amounts[tx.Description]
+		require.NotNil(t, tx.EUR, tx.Description)
+		assert.Equal(t, want[0], tx.EUR.ConvertedAmount, tx.Description)
+		if tx.Description == "Dinner" {
+			assert.Nil(t, tx.GBP)
+			assert.Equal(t, "2023-02-15", tx.EUR.ExchangeRate.Date)
+		} else {
+			require.NotNil(t, tx.GBP, tx.Description)
+			assert.Equal(t, want[1], tx.GBP.ConvertedAmount, tx.Description)
+		}
+
+		// Conversions are recorded in the audit log as the REST conversions are
+		require.NotEmpty(t, tx.AuditTrail)
+		assert.Equal(t, entity.AuditTransactionCreated, tx.AuditTrail[0].Action)
+	}
+
+	// The conversion without a rate is reported, the others still resolve
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codeRateUnavailable, resp.Errors[0].Extensions["code"])
+	assert.Contains(t, resp.Errors[0].Message, "within 6 months before the transaction date")
+	assert.Contains(t, resp.Errors[0].Path, "gbp")
+}
+
+func TestHandlerQueries(t *testing.T) {
+	server := newTestServer(t, Config{})
+	date := time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC)
+	ids := []string{
+		server.create(t, "First", date, 10),
+		server.create(t, "Second", date, 20),
+		server.create(t, "Third", date, 30),
+	}
+
+	t.Run("Transaction by ID", func(t *testing.T) {
+		resp := server.query(t, nil, `query($id: ID!) { transaction(id: $id) { id amount date } }`,
+			map[string]interface{}{"id": ids[0]})
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"transaction": {"id": "`+ids[0]+`", "amount": 10, "date": "2023-04-15"}}`, string(resp.Data))
+	})
+
+	t.Run("Unknown transaction is null", func(t *testing.T) {
+		resp := server.query(t, nil, `{ transaction(id: "missing") { id } }`, nil)
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"transaction": null}`, string(resp.Data))
+	})
+
+	t.Run("Pages follow the cursor", func(t *testing.T) {
+		var seen []string
+		variables := map[string]interface{}{}
+		for {
+			resp := server.query(t, nil, `query($after: String) {
+				transactions(first: 2, after: $after) { transactions { id } nextCursor }
+			}`, variables)
+			require.Empty(t, resp.Errors)
+
+			var data struct {
+				Transactions struct {
+					Transactions []struct{ ID string }
+					NextCursor   *string
+				}
+			}
+			require.NoError(t, json.Unmarshal(resp.Data, &data))
+			for _, tx := range data.Transactions.Transactions {
+				seen = append(seen, tx.ID)
+			}
+			if data.Transactions.NextCursor == nil {
+				break
+			}
+			variables["after"] = *data.Transactions.NextCursor
+		}
+		assert.ElementsMatch(t, ids, seen)
+		assert.IsIncreasing(t, seen)
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		resp := server.query(t, nil, `{ transactions(first: 0) { nextCursor } }`, nil)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codeBadInput, resp.Errors[0].Extensions["code"])
+
+		resp = server.query(t, nil, `query($id: ID!) { transaction(id: $id) { convertedTo(currency: "EURO") { currency } } }`,
+			map[string]interface{}{"id": ids[0]})
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, codeBadInput, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("Exchange rate", func(t *testing.T) {
+		server.rates.On("FindRate", mock.Anything, "CAD", date).
+			Return(&entity.ExchangeRate{Currency: "CAD", Date: date.AddDate(0, -1, 0), Rate: 1.35}, nil).Once()
+
+		resp := server.query(t, nil, `{ exchangeRate(currency: "CAD", date: "2023-04-15") { currency rate date } }`, nil)
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"exchangeRate": {"currency": "CAD", "rate": 1.35, "date": "2023-03-15"}}`, string(resp.Data))
+	})
+}
+
+func TestHandlerAuditTrailPolicy(t *testing.T) {
+	policy := auth.NewPolicy().Require(AuditTrailField, "compliance")
+	server := newTestServer(t, Config{Policy: policy})
+	id := server.create(t, "Coffee", time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC), 10)
+	query := `query($id: ID!) { transaction(id: $id) { id auditTrail { action actor details { key value } } } }`
+	variables := map[string]interface{}{"id": id}
+
+	resp := server.query(t, &auth.Principal{Subject: "reader", Roles: []string{"transactions:read"}}, query, variables)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, codeForbidden, resp.Errors[0].Extensions["code"])
+
+	resp = server.query(t, &auth.Principal{Subject: "auditor", Roles: []string{"compliance"}}, query, variables)
+	require.Empty(t, resp.Errors)
+	assert.Contains(t, string(resp.Data), `"action":"transaction.created"`)
+}
+
+func TestHandlerRejectsMalformedRequests(t *testing.T) {
+	server := newTestServer(t, Config{})
+
+	for name, req := range map[string]*http.Request{
+		"Body is not JSON":    httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString("{ transactions }")),
+		"Query is missing":    httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"variables": {}}`)),
+		"Variables not JSON":  httptest.NewRequest(http.MethodGet, "/graphql?query=%7B+__typename+%7D&variables=x", nil),
+		"GET without a query": httptest.NewRequest(http.MethodGet, "/graphql", nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			server.handler.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+
+	// Queries can also be sent as query parameters
+	rec := httptest.NewRecorder()
+	server.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ __typename }"), nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data": {"__typename": "Query"}}`, rec.Body.String())
+}
@@ -0,0 +1,56 @@
+// Package graphql internal/infrastructure/graphql/loader.go
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// conversionBatch converts the transactions resolved together, such as a page of
+// transactions. Every transaction of a list is resolved with the same selection,
+// so the first convertedTo(currency) resolved converts all of them, looking up
+// each distinct rate once, and the rest wait for its results.
+type conversionBatch struct {
+	conversions  *service.ConversionService
+	transactions []*entity.Transaction
+
+	mu    sync.Mutex
+	calls map[string]*conversionCall
+}
+
+// conversionCall is the conversion of a batch to one currency
+type conversionCall struct {
+	once    sync.Once
+	results []service.ConversionResult
+}
+
+func newConversionBatch(conversions *service.ConversionService, transactions []*entity.Transaction) *conversionBatch {
+	return &conversionBatch{
+		conversions:  conversions,
+		transactions: transactions,
+		calls:        make(map[string]*conversionCall),
+	}
+}
+
+// convert returns the conversion of the i'th transaction of the batch to currency
+func (b *conversionBatch) convert(ctx context.Context, i int, currency string) service.ConversionResult {
+	b.mu.Lock()
+	call, ok := b.calls[currency]
+	if !ok {
+		call = &conversionCall{}
+		b.calls[currency] = call
+	}
+	b.mu.Unlock()
+
+	call.once.Do(func() {
+		requests := make([]service.ConversionRequest, len(b.transactions))
+		for j, tx := range b.transactions {
+			requests[j] = service.ConversionRequest{Transaction: tx, Currency: currency}
+		}
+		call.results = b.conversions.ConvertTransactions(ctx, requests)
+	})
+	return call.results[i]
+}
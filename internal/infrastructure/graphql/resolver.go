@@ -0,0 +1,312 @@
+// Package graphql internal/infrastructure/graphql/resolver.go
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// AuditTrailField is the policy route of Transaction.auditTrail, which can require
+// roles beyond those of the /graphql route
+const AuditTrailField = "GRAPHQL Transaction.auditTrail"
+
+// Limits on the transactions returned per page
+const maxPageSize = 500
+
+// resolver is the root resolver of the schema
+type resolver struct {
+	transactions *service.TransactionService
+	conversions  *service.ConversionService
+	audit        *service.AuditService
+	policy       *auth.Policy
+}
+
+// Transaction resolves a transaction by ID. A transaction that does not exist or
+// has expired resolves to null.
+func (r *resolver) Transaction(ctx context.Context, args struct{ ID graphql.ID }) (*transactionResolver, error) {
+	tx, err := r.transactions.GetTransaction(ctx, string(args.ID))
+	if errors.Is(err, repository.ErrTransactionNotFound) || errors.Is(err, repository.ErrTransactionExpired) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, queryError(err)
+	}
+	return r.newTransactions([]*entity.Transaction{tx})[0], nil
+}
+
+// Transactions resolves the page of the tenant's transactions whose IDs follow after
+func (r *resolver) Transactions(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*pageResolver, error) {
+	if args.First < 1 || args.First > maxPageSize {
+		return nil, newError(codeBadInput, fmt.Sprintf("first must be between 1 and %d", maxPageSize))
+	}
+	after := ""
+	if args.After != nil {
+		after = *args.After
+	}
+
+	page, next, err := r.transactions.ListTransactionPage(ctx, after, int(args.First))
+	if err != nil {
+		return nil, queryError(err)
+	}
+
+	resolved := &pageResolver{transactions: r.newTransactions(page)}
+	if next != "" {
+		resolved.nextCursor = &next
+	}
+	return resolved, nil
+}
+
+// ExchangeRate resolves the rate applicable to a purchase in a currency on a date
+func (r *resolver) ExchangeRate(ctx context.Context, args struct {
+	Currency string
+	Date     string
+}) (*exchangeRateResolver, error) {
+	if err := validateCurrency(args.Currency); err != nil {
+		return nil, err
+	}
+	date, err := time.Parse("2006-01-02", args.Date)
+	if err != nil {
+		return nil, newError(codeBadInput, "date must be in YYYY-MM-DD format")
+	}
+
+	key := service.NewRateKey(args.Currency, date)
+	result := r.conversions.FindRates(ctx, []service.RateKey{key})[key]
+	if result.Err != nil {
+		return nil, queryError(result.Err)
+	}
+	return &exchangeRateResolver{rate: result.Rate}, nil
+}
+
+// newTransactions creates the resolvers of transactions resolved together, which
+// share their conversions
+func (r *resolver) newTransactions(transactions []*entity.Transaction) []*transactionResolver {
+	batch := newConversionBatch(r.conversions, transactions)
+	resolvers := make([]*transactionResolver, len(transactions))
+	for i, tx := range transactions {
+		resolvers[i] = &transactionResolver{root: r, tx: tx, batch: batch, index: i}
+	}
+	return resolvers
+}
+
+// validateCurrency checks that a currency is a 3 character code, as the REST
+// handler does
+func validateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return newError(codeBadInput, "currency code should be 3 characters (e.g., EUR, GBP, CAD)")
+	}
+	return nil
+}
+
+type pageResolver struct {
+	transactions []*transactionResolver
+	nextCursor   *string
+}
+
+func (p *pageResolver) Transactions() []*transactionResolver {
+	return p.transactions
+}
+
+func (p *pageResolver) NextCursor() *string {
+	return p.nextCursor
+}
+
+type transactionResolver struct {
+	root  *resolver
+	tx    *entity.Transaction
+	batch *conversionBatch
+	index int
+}
+
+func (t *transactionResolver) ID() graphql.ID {
+	return graphql.ID(t.tx.ID)
+}
+
+func (t *transactionResolver) Description() string {
+	return t.tx.Description
+}
+
+func (t *transactionResolver) Category() *string {
+	if t.tx.Category == "" {
+		return nil
+	}
+	return &t.tx.Category
+}
+
+func (t *transactionResolver) Date() string {
+	return t.tx.Date.Format("2006-01-02")
+}
+
+func (t *transactionResolver) Amount() float64 {
+	return t.tx.Amount
+}
+
+func (t *transactionResolver) CreatedAt() string {
+	return t.tx.CreatedAt.Format(time.RFC3339)
+}
+
+func (t *transactionResolver) LegalHold() *legalHoldResolver {
+	if t.tx.LegalHold == nil {
+		return nil
+	}
+	return &legalHoldResolver{hold: t.tx.LegalHold}
+}
+
+// ConvertedTo resolves the transaction's conversion to a currency through the
+// batch it was resolved with
+func (t *transactionResolver) ConvertedTo(ctx context.Context, args struct{ Currency string }) (*conversionResolver, error) {
+	if err := validateCurrency(args.Currency); err != nil {
+		return nil, err
+	}
+
+	result := t.batch.convert(ctx, t.index, args.Currency)
+	if result.Err != nil {
+		return nil, queryError(result.Err)
+	}
+	return &conversionResolver{converted: result.Converted}, nil
+}
+
+// AuditTrail resolves the events recorded against the transaction. The policy
+// can restrict it, as it restricts the REST audit trail.
+func (t *transactionResolver) AuditTrail(ctx context.Context) ([]*auditEventResolver, error) {
+	if t.root.policy != nil {
+		principal := middleware.GetPrincipal(ctx)
+		if principal == nil || !t.root.policy.Authorize(AuditTrailField, principal) {
+			return nil, newError(codeForbidden, "the audit trail requires one of the roles "+
+				fmt.Sprint(t.root.policy.RequiredRoles(AuditTrailField)))
+		}
+	}
+
+	events := []*auditEventResolver{}
+	err := t.root.audit.TransactionTrail(ctx, t.tx.ID, func(event *entity.AuditEvent) error {
+		events = append(events, &auditEventResolver{event: event})
+		return nil
+	})
+	if err != nil {
+		return nil, queryError(err)
+	}
+	return events, nil
+}
+
+type legalHoldResolver struct {
+	hold *entity.LegalHold
+}
+
+func (h *legalHoldResolver) Reason() string {
+	return h.hold.Reason
+}
+
+func (h *legalHoldResolver) PlacedBy() string {
+	return h.hold.PlacedBy
+}
+
+func (h *legalHoldResolver) PlacedAt() string {
+	return h.hold.PlacedAt.Format(time.RFC3339)
+}
+
+type conversionResolver struct {
+	converted *service.ConvertedTransaction
+}
+
+func (c *conversionResolver) Currency() string {
+	return c.converted.Currency
+}
+
+func (c *conversionResolver) ExchangeRate() *exchangeRateResolver {
+	return &exchangeRateResolver{rate: &entity.ExchangeRate{
+		Currency: c.converted.Currency,
+		Date:     c.converted.RateDate,
+		Rate:     c.converted.ExchangeRate,
+	}}
+}
+
+func (c *conversionResolver) ConvertedAmount() float64 {
+	return c.converted.ConvertedAmount
+}
+
+type exchangeRateResolver struct {
+	rate *entity.ExchangeRate
+}
+
+func (e *exchangeRateResolver) Currency() string {
+	return e.rate.Currency
+}
+
+func (e *exchangeRateResolver) Rate() float64 {
+	return e.rate.Rate
+}
+
+func (e *exchangeRateResolver) Date() string {
+	return e.rate.Date.Format("2006-01-02")
+}
+
+type auditEventResolver struct {
+	event *entity.AuditEvent
+}
+
+// Sequence is a Float, as sequences can outgrow the 32 bits of a GraphQL Int
+func (a *auditEventResolver) Sequence() float64 {
+	return float64(a.event.Sequence)
+}
+
+func (a *auditEventResolver) Action() string {
+	return a.event.Action
+}
+
+func (a *auditEventResolver) Actor() string {
+	return a.event.Actor
+}
+
+func (a *auditEventResolver) RequestID() *string {
+	if a.event.RequestID == "" {
+		return nil
+	}
+	return &a.event.RequestID
+}
+
+func (a *auditEventResolver) OccurredAt() string {
+	return a.event.OccurredAt.Format(time.RFC3339Nano)
+}
+
+// Details resolves the event's details in key order
+func (a *auditEventResolver) Details() []*auditDetailResolver {
+	details := make([]*auditDetailResolver, 0, len(a.event.Details))
+	for key, value := range a.event.Details {
+		details = append(details, &auditDetailResolver{key: key, value: value})
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].key < details[j].key })
+	return details
+}
+
+func (a *auditEventResolver) PrevHash() string {
+	return a.event.PrevHash
+}
+
+func (a *auditEventResolver) Hash() string {
+	return a.event.Hash
+}
+
+type auditDetailResolver struct {
+	key   string
+	value string
+}
+
+func (d *auditDetailResolver) Key() string {
+	return d.key
+}
+
+func (d *auditDetailResolver) Value() string {
+	return d.value
+}
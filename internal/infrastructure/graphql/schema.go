@@ -0,0 +1,75 @@
+// Package graphql internal/infrastructure/graphql/schema.go
+package graphql
+
+// schema is the GraphQL schema served at /graphql. Dates are YYYY-MM-DD and
+// times RFC 3339, as in the REST API.
+const schema = `
+schema {
+	query: Query
+}
+
+type Query {
+	# A transaction by ID
+	transaction(id: ID!): Transaction
+	# A page of the tenant's transactions, in ID order
+	transactions(first: Int = 50, after: String): TransactionPage!
+	# The rate applicable to a purchase in currency on date: the latest published
+	# within the configured lookback window up to the date
+	exchangeRate(currency: String!, date: String!): ExchangeRate
+}
+
+type TransactionPage {
+	transactions: [Transaction!]!
+	# Pass as after to fetch the next page; null on the last page
+	nextCursor: String
+}
+
+type Transaction {
+	id: ID!
+	description: String!
+	category: String
+	date: String!
+	amount: Float!
+	createdAt: String!
+	legalHold: LegalHold
+	# The transaction converted to currency. Conversions of a page of
+	# transactions are resolved together, looking each rate up once.
+	convertedTo(currency: String!): Conversion
+	# The audit events recorded against the transaction, oldest first
+	auditTrail: [AuditEvent!]!
+}
+
+type LegalHold {
+	reason: String!
+	placedBy: String!
+	placedAt: String!
+}
+
+type Conversion {
+	currency: String!
+	exchangeRate: ExchangeRate!
+	convertedAmount: Float!
+}
+
+type ExchangeRate {
+	currency: String!
+	rate: Float!
+	date: String!
+}
+
+type AuditEvent {
+	sequence: Float!
+	action: String!
+	actor: String!
+	requestId: String
+	occurredAt: String!
+	details: [AuditDetail!]!
+	prevHash: String!
+	hash: String!
+}
+
+type AuditDetail {
+	key: String!
+	value: String!
+}
+`
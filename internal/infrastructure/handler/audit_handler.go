@@ -0,0 +1,83 @@
+// Package handler internal/infrastructure/handler/audit_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// AuditHandler serves transaction audit trails
+type AuditHandler struct {
+	service *service.AuditService
+	logger  logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(service *service.AuditService, log logger.Logger) *AuditHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &AuditHandler{
+		service: service,
+		logger:  log,
+	}
+}
+
+// GetTrail returns every audit event recorded against a transaction, oldest first.
+// Trails outlive their transactions, so an expired transaction still has one.
+func (h *AuditHandler) GetTrail(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	id := mux.Vars(r)["id"]
+
+	resp := AuditTrailResponse{TransactionID: id, Events: []AuditEventResponse{}}
+	err := h.service.TransactionTrail(r.Context(), id, func(event *entity.AuditEvent) error {
+		resp.Events = append(resp.Events, AuditEventResponse{
+			Sequence:   event.Sequence,
+			Action:     event.Action,
+			Actor:      event.Actor,
+			RequestID:  event.RequestID,
+			OccurredAt: event.OccurredAt.Format(time.RFC3339Nano),
+			Details:    event.Details,
+			PrevHash:   event.PrevHash,
+			Hash:       event.Hash,
+		})
+		return nil
+	})
+	if err != nil {
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while reading the audit trail", http.StatusInternalServerError, requestID)
+		return
+	}
+
+	// Transactions created since the audit log was introduced have at least their
+	// creation event
+	if len(resp.Events) == 0 {
+		sendErrorResponse(w, log, "Transaction not found",
+			"No audit trail exists for the requested transaction", http.StatusNotFound, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RegisterRoutes registers the audit routes
+func (h *AuditHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/transactions/{id}/audit", h.GetTrail).Methods("GET")
+
+	h.logger.Info("Audit routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /transactions/{id}/audit",
+		},
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
 	"github.com/gorilla/mux"
 )
 
@@ -54,42 +55,43 @@ func NewConversionHandler(service *service.ConversionService, log logger.Logger)
 func (h *ConversionHandler) ConvertTransaction(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetRequestID(r.Context())
 
+	log := logger.ForContext(r.Context(), h.logger)
+
 	// Get ID from URL
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	h.logger.Info("Handling convert transaction request", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log.Info("Handling convert transaction request", map[string]interface{}{
+		"id": id,
 	})
 
-	// Get currency from query parameter
+	// Get currency from query parameter, falling back to the tenant's default
 	currency := r.URL.Query().Get("currency")
 	if currency == "" {
-		h.logger.Warn("Missing currency parameter", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
+		currency = tenant.FromContext(r.Context()).DefaultCurrency()
+	}
+	if currency == "" {
+		log.Warn("Missing currency parameter", map[string]interface{}{
+			"id": id,
 		})
-		sendErrorResponse(w, h.logger, "Missing currency parameter",
+		sendErrorResponse(w, log, "Missing currency parameter",
 			"The 'currency' query parameter is required", http.StatusBadRequest, requestID)
 		return
 	}
 
-	h.logger.Debug("Currency parameter", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
-		"currency":   currency,
+	log.Debug("Currency parameter", map[string]interface{}{
+		"id":       id,
+		"currency": currency,
 	})
 
 	// Currency codes should be 3 characters
 	if len(currency) != 3 {
-		h.logger.Warn("Invalid currency code", map[string]interface{}{
-			"request_id": requestID,
-			"id":         id,
-			"currency":   currency,
-			"length":     len(currency),
+		log.Warn("Invalid currency code", map[string]interface{}{
+			"id":       id,
+			"currency": currency,
+			"length":   len(currency),
 		})
-		sendErrorResponse(w, h.logger, "Invalid currency code",
+		sendErrorResponse(w, log, "Invalid currency code",
 			"Currency code should be 3 characters (e.g., EUR, GBP, CAD)", http.StatusBadRequest, requestID)
 		return
 	}
@@ -98,74 +100,61 @@ func (h *ConversionHandler) ConvertTransaction(w http.ResponseWriter, r *http.Re
 	convertedTx, err := h.service.GetTransactionInCurrency(r.Context(), id, currency)
 	if err != nil {
 		// Handle different types of errors
-		switch {
-		case strings.Contains(err.Error(), "not found"):
-			h.logger.Warn("Transaction not found", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"error":      err.Error(),
+		switch service.Classify(err) {
+		case service.ErrorExpired:
+			log.Warn("Transaction expired", map[string]interface{}{
+				"id": id,
+			})
+			sendErrorResponse(w, log, "Transaction expired",
+				"The requested transaction has passed its retention period", http.StatusGone, requestID)
+		case service.ErrorNotFound:
+			log.Warn("Transaction not found", map[string]interface{}{
+				"id":    id,
+				"error": err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Transaction not found",
+			sendErrorResponse(w, log, "Transaction not found",
 				"The requested transaction could not be found", http.StatusNotFound, requestID)
-		case strings.Contains(err.Error(), "no exchange rate available"):
-			h.logger.Warn("No exchange rate available", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"currency":   currency,
-				"error":      err.Error(),
+		case service.ErrorNoExchangeRate:
+			log.Warn("No exchange rate available", map[string]interface{}{
+				"id":       id,
+				"currency": currency,
+				"error":    err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "No exchange rate available",
-				"No exchange rate is available within 6 months of the transaction date for the specified currency",
+			sendErrorResponse(w, log, "No exchange rate available", rateErrorMessage(err),
 				http.StatusBadRequest, requestID)
-		case strings.Contains(err.Error(), "exchange rate date") && strings.Contains(err.Error(), "outside the allowed range"):
-			h.logger.Warn("Exchange rate outside allowed range", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"currency":   currency,
-				"error":      err.Error(),
+		case service.ErrorRateOutOfRange:
+			log.Warn("Exchange rate outside allowed range", map[string]interface{}{
+				"id":       id,
+				"currency": currency,
+				"error":    err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Exchange rate outside allowed range",
-				"The available exchange rate is outside the 6-month window prior to the transaction date",
+			sendErrorResponse(w, log, "Exchange rate outside allowed range", rateErrorMessage(err),
 				http.StatusBadRequest, requestID)
-		case strings.Contains(err.Error(), "failed to get exchange rate"):
+		case service.ErrorRateServiceUnavailable:
 			// Log the error for internal debugging
-			h.logger.Error("Exchange rate service error", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"currency":   currency,
-				"error":      err.Error(),
+			log.Error("Exchange rate service error", map[string]interface{}{
+				"id":       id,
+				"currency": currency,
+				"error":    err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Exchange rate service unavailable",
+			sendErrorResponse(w, log, "Exchange rate service unavailable",
 				"Unable to retrieve exchange rate data. Please try again later.",
 				http.StatusServiceUnavailable, requestID)
-		case strings.Contains(err.Error(), "failed to execute request"):
-			// Network or API connectivity issues
-			h.logger.Error("API connectivity error", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"currency":   currency,
-				"error":      err.Error(),
-			})
-			sendErrorResponse(w, h.logger, "Service temporarily unavailable",
-				"The exchange rate service is temporarily unavailable. Please try again later.",
-				http.StatusServiceUnavailable, requestID)
 		default:
 			// Log unexpected errors for investigation
-			h.logger.Error("Unexpected error in conversion handler", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"currency":   currency,
-				"error":      err.Error(),
+			log.Error("Unexpected error in conversion handler", map[string]interface{}{
+				"id":       id,
+				"currency": currency,
+				"error":    err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Internal server error",
+			sendErrorResponse(w, log, "Internal server error",
 				"An unexpected error occurred. Please try again later.",
 				http.StatusInternalServerError, requestID)
 		}
 		return
 	}
 
-	h.logger.Info("Transaction converted successfully", map[string]interface{}{
-		"request_id":       requestID,
+	log.Info("Transaction converted successfully", map[string]interface{}{
 		"id":               id,
 		"currency":         currency,
 		"original_amount":  convertedTx.OriginalAmount,
@@ -200,3 +189,9 @@ func (h *ConversionHandler) RegisterRoutes(router *mux.Router) {
 		},
 	})
 }
+
+// rateErrorMessage returns service.RateErrorMessage as a sentence
+func rateErrorMessage(err error) string {
+	message := service.RateErrorMessage(err)
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -3,6 +3,7 @@ package handler
 // CreateTransactionRequest represents the request body for creating a transaction
 type CreateTransactionRequest struct {
 	Description string  `json:"description"`
+	Category    string  `json:"category,omitempty"`
 	Date        string  `json:"date"`
 	Amount      float64 `json:"amount"`
 }
@@ -11,11 +12,132 @@ type CreateTransactionRequest struct {
 type TransactionResponse struct {
 	ID          string  `json:"id"`
 	Description string  `json:"description"`
+	Category    string  `json:"category,omitempty"`
 	Date        string  `json:"date"`
 	Amount      float64 `json:"amount"`
+	// LegalHold is set while the transaction is under legal hold
+	LegalHold *LegalHoldResponse `json:"legal_hold,omitempty"`
 }
 
 // CreateTransactionResponse represents the response for the create transaction endpoint
 type CreateTransactionResponse struct {
 	ID string `json:"id"`
 }
+
+// LegalHoldRequest represents the request body for placing a legal hold
+type LegalHoldRequest struct {
+	Reason string `json:"reason"`
+}
+
+// LegalHoldResponse describes a legal hold on a transaction
+type LegalHoldResponse struct {
+	Reason   string `json:"reason"`
+	PlacedBy string `json:"placed_by"`
+	PlacedAt string `json:"placed_at"`
+}
+
+// LegalHoldListResponse represents the response for the legal hold listing endpoint
+type LegalHoldListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+}
+
+// AuditEventResponse represents an entry in a transaction's audit trail
+type AuditEventResponse struct {
+	Sequence   uint64            `json:"sequence"`
+	Action     string            `json:"action"`
+	Actor      string            `json:"actor"`
+	RequestID  string            `json:"request_id,omitempty"`
+	OccurredAt string            `json:"occurred_at"`
+	Details    map[string]string `json:"details,omitempty"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+// AuditTrailResponse represents the response for the audit trail endpoint
+type AuditTrailResponse struct {
+	TransactionID string               `json:"transaction_id"`
+	Events        []AuditEventResponse `json:"events"`
+}
+
+// WebhookSubscriptionRequest represents the request body for registering a webhook
+type WebhookSubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// WebhookSubscriptionResponse describes a webhook subscription. Secret is only set
+// in the response to its creation.
+type WebhookSubscriptionResponse struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// WebhookSubscriptionListResponse represents the response for the webhook listing endpoint
+type WebhookSubscriptionListResponse struct {
+	Subscriptions []WebhookSubscriptionResponse `json:"subscriptions"`
+}
+
+// DeliveryAttemptResponse describes one attempt to deliver a webhook
+type DeliveryAttemptResponse struct {
+	At         string `json:"at"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// WebhookDeliveryResponse describes a webhook delivery and its attempts
+type WebhookDeliveryResponse struct {
+	ID             string                    `json:"id"`
+	SubscriptionID string                    `json:"subscription_id"`
+	EventID        string                    `json:"event_id"`
+	EventType      string                    `json:"event_type"`
+	Status         string                    `json:"status"`
+	Attempts       int                       `json:"attempts"`
+	NextAttemptAt  string                    `json:"next_attempt_at,omitempty"`
+	CreatedAt      string                    `json:"created_at"`
+	History        []DeliveryAttemptResponse `json:"history"`
+}
+
+// WebhookDeliveryListResponse represents the response for the delivery history and
+// dead-letter endpoints
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+// SummaryResponse describes a set of amounts in a summary report
+type SummaryResponse struct {
+	Count   int     `json:"count"`
+	Sum     float64 `json:"sum"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Average float64 `json:"average"`
+}
+
+// ConvertedSummaryResponse describes the converted amounts of a report group, and
+// how many of its transactions could not be converted
+type ConvertedSummaryResponse struct {
+	Currency string `json:"currency"`
+	SummaryResponse
+	Unconverted int `json:"unconverted"`
+}
+
+// ReportGroupResponse summarises a month or category of transactions
+type ReportGroupResponse struct {
+	// Key is the month or category, and null for the transactions without a
+	// category
+	Key       *string                   `json:"key"`
+	USD       SummaryResponse           `json:"usd"`
+	Converted *ConvertedSummaryResponse `json:"converted,omitempty"`
+}
+
+// SummaryReportResponse represents the response for the summary report endpoint
+type SummaryReportResponse struct {
+	GroupBy  string                `json:"group_by"`
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	Currency string                `json:"currency,omitempty"`
+	Groups   []ReportGroupResponse `json:"groups"`
+}
@@ -0,0 +1,172 @@
+// Package handler internal/infrastructure/handler/export_handler.go
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/export"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// ExportHandler serves spreadsheet exports of transactions
+type ExportHandler struct {
+	service *service.ExportService
+	logger  logger.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(service *service.ExportService, log logger.Logger) *ExportHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &ExportHandler{
+		service: service,
+		logger:  log,
+	}
+}
+
+// Export streams the tenant's transactions dated between the from and to
+// parameters as CSV or XLSX, per the format parameter. With a currency parameter
+// each row also carries the transaction converted to that currency, or why it
+// could not be converted.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := middleware.GetRequestID(ctx)
+	log := logger.ForContext(ctx, h.logger)
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		sendErrorResponse(w, log, "Invalid format",
+			"The format parameter must be csv or xlsx", http.StatusBadRequest, requestID)
+		return
+	}
+
+	var filter service.ExportFilter
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			sendErrorResponse(w, log, "Invalid date",
+				"The "+param.name+" parameter must be in YYYY-MM-DD format", http.StatusBadRequest, requestID)
+			return
+		}
+		*param.value = date
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		sendErrorResponse(w, log, "Invalid date range",
+			"The from parameter must not be after to", http.StatusBadRequest, requestID)
+		return
+	}
+
+	filter.Currency = query.Get("currency")
+	if filter.Currency != "" && len(filter.Currency) != 3 {
+		sendErrorResponse(w, log, "Invalid currency code",
+			"Currency code should be 3 characters (e.g., EUR, GBP, CAD)", http.StatusBadRequest, requestID)
+		return
+	}
+
+	// Large exports outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	filename := "transactions"
+	if filter.Currency != "" {
+		filename += "-" + filter.Currency
+	}
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+"."+format+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	out, err := export.NewWriter(format, w)
+	if err == nil {
+		err = out.WriteRow(exportHeader(filter.Currency)...)
+	}
+	if err == nil {
+		_, err = h.service.Export(ctx, filter, func(row *service.ExportRow) error {
+			return out.WriteRow(exportCells(row, filter.Currency)...)
+		})
+	}
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil && ctx.Err() != nil {
+		log.Warn("Export cancelled", map[string]interface{}{
+			"format": format,
+		})
+		return
+	}
+	if err != nil {
+		// The status has been sent, so abort the response rather than let a
+		// truncated export look complete
+		log.Error("Export failed", map[string]interface{}{
+			"format": format,
+			"error":  err.Error(),
+		})
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportHeader returns the column names of an export, with the conversion
+// columns when converting to currency
+func exportHeader(currency string) []interface{} {
+	header := []interface{}{"ID", "Date", "Description", "Amount (USD)"}
+	if currency != "" {
+		header = append(header, "Currency", "Exchange Rate", "Rate Date", "Converted Amount", "Conversion Error")
+	}
+	return header
+}
+
+// exportCells returns the cells of a row, in the columns of exportHeader
+func exportCells(row *service.ExportRow, currency string) []interface{} {
+	tx := row.Transaction
+	cells := []interface{}{tx.ID, tx.Date, tx.Description, tx.Amount}
+	if currency == "" {
+		return cells
+	}
+
+	if row.Converted == nil {
+		return append(cells, currency, nil, nil, nil, conversionProblem(row.ConversionErr))
+	}
+	converted := row.Converted
+	return append(cells, converted.Currency, converted.ExchangeRate, converted.RateDate, converted.ConvertedAmount, nil)
+}
+
+// conversionProblem describes why a transaction could not be converted
+func conversionProblem(err error) string {
+	switch service.Classify(err) {
+	case service.ErrorNoExchangeRate, service.ErrorRateOutOfRange:
+		return rateErrorMessage(err)
+	case service.ErrorRateServiceUnavailable:
+		return "The exchange rate service is temporarily unavailable"
+	default:
+		return "An unexpected error occurred"
+	}
+}
+
+// RegisterRoutes registers the export routes. They must be registered before the
+// transaction routes, whose /transactions/{id} would otherwise match.
+func (h *ExportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/transactions/export", h.Export).Methods("GET")
+
+	h.logger.Info("Export routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /transactions/export",
+		},
+	})
+}
@@ -0,0 +1,82 @@
+// Package handler internal/infrastructure/handler/health_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/health"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/gorilla/mux"
+)
+
+// HealthHandler serves the liveness and readiness endpoints
+type HealthHandler struct {
+	registry *health.Registry
+	logger   logger.Logger
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(registry *health.Registry, log logger.Logger) *HealthHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &HealthHandler{
+		registry: registry,
+		logger:   log,
+	}
+}
+
+// Live reports that the process is running. It never checks dependencies, so a
+// failing database does not cause the process to be restarted.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// Ready runs the registered checks and returns 503 if a critical one failed
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.registry.Run(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status == health.StatusUnavailable {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	if report.Status != health.StatusOK {
+		failed := make(map[string]string)
+		for name, component := range report.Components {
+			if component.Status != health.StatusOK {
+				failed[name] = component.Error
+			}
+		}
+		logger.ForContext(r.Context(), h.logger).Warn("Readiness check failed", map[string]interface{}{
+			"status": report.Status,
+			"failed": failed,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(report)
+}
+
+// RegisterRoutes registers the health routes. /health is kept as an alias of
+// /health/live for existing probes.
+func (h *HealthHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/health", h.Live).Methods("GET")
+	router.HandleFunc("/health/live", h.Live).Methods("GET")
+	router.HandleFunc("/health/ready", h.Ready).Methods("GET")
+
+	h.logger.Info("Health routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /health",
+			"GET /health/live",
+			"GET /health/ready",
+		},
+		"checks": h.registry.Names(),
+	})
+}
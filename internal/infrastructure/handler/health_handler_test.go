@@ -0,0 +1,74 @@
+// internal/infrastructure/handler/health_handler_test.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/health"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+
+	badgerErr := error(nil)
+	registry := health.NewRegistry(time.Second)
+	registry.Register("badger", true, health.CheckerFunc(func(ctx context.Context) error { return badgerErr }))
+	registry.Register("treasury", false, health.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	router := mux.NewRouter()
+	NewHealthHandler(registry, log).RegisterRoutes(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	t.Run("Liveness ignores dependencies", func(t *testing.T) {
+		badgerErr = errors.New("DB Closed")
+		defer func() { badgerErr = nil }()
+
+		for _, path := range []string{"/health", "/health/live"} {
+			w := get(path)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+		}
+	})
+
+	t.Run("Degraded dependency keeps the service ready", func(t *testing.T) {
+		w := get("/health/ready")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusDegraded, report.Status)
+		assert.Equal(t, health.StatusOK, report.Components["badger"].Status)
+		assert.Equal(t, "connection refused", report.Components["treasury"].Error)
+	})
+
+	t.Run("Critical failure returns 503", func(t *testing.T) {
+		badgerErr = errors.New("DB Closed")
+		defer func() { badgerErr = nil }()
+
+		w := get("/health/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusUnavailable, report.Status)
+		assert.Equal(t, health.StatusFailed, report.Components["badger"].Status)
+		assert.True(t, report.Components["badger"].Critical)
+	})
+}
@@ -2,27 +2,36 @@
 package handler_test
 
 import (
+	"archive/zip"
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/handler"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/outbox"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/webhook"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
 	"github.com/dgraph-io/badger/v3"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // setupTestServer creates a test server with mocked dependencies
@@ -48,13 +57,26 @@ func setupTestServer(exchangeRateRepo *mocks.MockExchangeRateRepository) (*httpt
 	log := logger.NewJSONLogger(nil, logger.InfoLevel)
 
 	// Create repository and services
-	txRepo := db.NewBadgerTransactionRepository(badgerDB, log)
-	txService := service.NewTransactionService(txRepo, log)
-	conversionService := service.NewConversionService(txRepo, exchangeRateRepo, log)
+	txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
+	auditService := service.NewAuditService(db.NewBadgerAuditRepository(badgerDB, log, nil), log)
+	conversionService := service.NewConversionServiceWithAudit(txRepo, exchangeRateRepo, auditService, log)
+	reportService := service.NewReportService(txRepo, conversionService, service.ReportConfig{CacheTTL: time.Hour, CacheSize: 100}, log)
+	txService := service.NewTransactionServiceWithConfig(txRepo, service.TransactionServiceConfig{
+		Audit:         auditService,
+		PublishEvents: true,
+		Reports:       reportService,
+	}, log)
 
 	// Create handlers
 	txHandler := handler.NewTransactionHandler(txService, log)
 	conversionHandler := handler.NewConversionHandler(conversionService, log)
+	legalHoldHandler := handler.NewLegalHoldHandler(txService, log)
+	auditHandler := handler.NewAuditHandler(auditService, log)
+	webhookHandler := handler.NewWebhookHandler(service.NewWebhookService(db.NewBadgerWebhookRepository(badgerDB, log, nil), log), log)
+	streamService := service.NewTransactionStreamService(db.NewBadgerTransactionStreamRepository(badgerDB, log, nil), log)
+	streamHandler := handler.NewStreamHandler(streamService, 100*time.Millisecond, log)
+	exportHandler := handler.NewExportHandler(service.NewExportService(txRepo, conversionService, log), log)
+	reportHandler := handler.NewReportHandler(reportService, log)
 
 	// Setup router
 	router := mux.NewRouter()
@@ -63,15 +85,23 @@ func setupTestServer(exchangeRateRepo *mocks.MockExchangeRateRepository) (*httpt
 	router.Use(middleware.RequestIDMiddleware)
 	router.Use(middleware.LoggingMiddleware(log))
 
-	// Register routes
+	// Register routes; the stream and export come first so /transactions/{id} does
+	// not match them
+	streamHandler.RegisterRoutes(router)
+	exportHandler.RegisterRoutes(router)
 	txHandler.RegisterRoutes(router)
 	conversionHandler.RegisterRoutes(router)
+	legalHoldHandler.RegisterRoutes(router)
+	auditHandler.RegisterRoutes(router)
+	reportHandler.RegisterRoutes(router)
+	webhookHandler.RegisterRoutes(router)
 
 	// Create test server
 	server := httptest.NewServer(router)
 
 	// Return cleanup function
 	cleanup := func() {
+		streamHandler.Close()
 		server.Close()
 		badgerDB.Close()
 		os.RemoveAll(tempDir)
@@ -144,6 +174,447 @@ func TestTransactionCreationAndRetrieval(t *testing.T) {
 	assert.Equal(t, 123.45, txResp.Amount)
 }
 
+func TestLegalHolds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	server, _, cleanup, err := setupTestServer(new(mocks.MockExchangeRateRepository))
+	if err != nil {
+		t.Fatalf("Failed to setup test server: %v", err)
+	}
+	defer cleanup()
+
+	resp, err := http.Post(server.URL+"/transactions", "application/json",
+		bytes.NewBufferString(`{"description": "Disputed fuel", "date": "2023-04-15", "amount": 42.5}`))
+	if err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	var created handler.CreateTransactionResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	// send issues a request and decodes a successful response into out
+	send := func(method, path, body string, out interface{}) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if out != nil && resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+		return resp.StatusCode
+	}
+	holdPath := "/transactions/" + created.ID + "/legal-hold"
+
+	t.Run("Place a hold", func(t *testing.T) {
+		var held handler.TransactionResponse
+		status := send(http.MethodPut, holdPath, `{"reason": "Chargeback dispute"}`, &held)
+		require.Equal(t, http.StatusOK, status)
+		require.NotNil(t, held.LegalHold)
+		assert.Equal(t, "Chargeback dispute", held.LegalHold.Reason)
+		assert.Equal(t, "anonymous", held.LegalHold.PlacedBy)
+		assert.NotEmpty(t, held.LegalHold.PlacedAt)
+
+		var fetched handler.TransactionResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/transactions/"+created.ID, "", &fetched))
+		assert.Equal(t, held.LegalHold, fetched.LegalHold)
+
+		assert.Equal(t, http.StatusConflict, send(http.MethodPut, holdPath, `{"reason": "Again"}`, nil))
+	})
+
+	t.Run("List holds", func(t *testing.T) {
+		var list handler.LegalHoldListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/legal-holds", "", &list))
+		require.Len(t, list.Transactions, 1)
+		assert.Equal(t, created.ID, list.Transactions[0].ID)
+	})
+
+	t.Run("Release a hold", func(t *testing.T) {
+		var released handler.TransactionResponse
+		require.Equal(t, http.StatusOK, send(http.MethodDelete, holdPath, "", &released))
+		assert.Nil(t, released.LegalHold)
+
+		assert.Equal(t, http.StatusConflict, send(http.MethodDelete, holdPath, "", nil))
+
+		var list handler.LegalHoldListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/legal-holds", "", &list))
+		assert.Empty(t, list.Transactions)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, holdPath, `{"reason": " "}`, nil))
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, holdPath, `{"reason":`, nil))
+		assert.Equal(t, http.StatusNotFound, send(http.MethodPut, "/transactions/unknown/legal-hold", `{"reason": "Audit"}`, nil))
+	})
+}
+
+func TestAuditTrail(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	mockExchangeRateRepo := new(mocks.MockExchangeRateRepository)
+	mockExchangeRateRepo.On("FindRate", mock.Anything, "EUR", mock.Anything).Return(&entity.ExchangeRate{
+		Currency: "EUR",
+		Date:     time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+		Rate:     0.85,
+	}, nil)
+
+	server, _, cleanup, err := setupTestServer(mockExchangeRateRepo)
+	if err != nil {
+		t.Fatalf("Failed to setup test server: %v", err)
+	}
+	defer cleanup()
+
+	resp, err := http.Post(server.URL+"/transactions", "application/json",
+		bytes.NewBufferString(`{"description": "Fuel", "date": "2023-04-15", "amount": 40}`))
+	require.NoError(t, err)
+	var created handler.CreateTransactionResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/transactions/" + created.ID + "/convert?currency=EUR")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	convertRequestID := resp.Header.Get("X-Request-ID")
+
+	t.Run("Trail lists creation and conversion", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/transactions/" + created.ID + "/audit")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var trail handler.AuditTrailResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&trail))
+		require.Len(t, trail.Events, 2)
+
+		createdEvent, converted := trail.Events[0], trail.Events[1]
+		assert.Equal(t, entity.AuditTransactionCreated, createdEvent.Action)
+		assert.Equal(t, "anonymous", createdEvent.Actor)
+		assert.Equal(t, "40.00", createdEvent.Details["amount"])
+
+		assert.Equal(t, entity.AuditTransactionConverted, converted.Action)
+		assert.Equal(t, convertRequestID, converted.RequestID)
+		assert.Equal(t, "34.00", converted.Details["converted_amount"])
+		assert.Equal(t, createdEvent.Hash, converted.PrevHash)
+	})
+
+	t.Run("Unknown transaction has no trail", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/transactions/unknown/audit")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	server, badgerDB, cleanup, err := setupTestServer(new(mocks.MockExchangeRateRepository))
+	if err != nil {
+		t.Fatalf("Failed to setup test server: %v", err)
+	}
+	defer cleanup()
+
+	// The receiver accepts requests signed with secret while accept is set
+	var secret string
+	accept := true
+	var received []entity.DomainEvent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, time.Now(), time.Minute); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !accept {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var event entity.DomainEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+	}))
+	defer receiver.Close()
+
+	// Events travel from the outbox through the dispatcher to the worker
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	webhookRepo := db.NewBadgerWebhookRepository(badgerDB, log, nil)
+	relay := outbox.NewRelay(db.NewBadgerOutboxRepository(badgerDB, log, nil),
+		[]outbox.Sink{webhook.NewDispatcher(webhookRepo)}, outbox.DefaultRelayConfig(), log, nil)
+	workerConfig := webhook.DefaultWorkerConfig()
+	workerConfig.MaxAttempts = 1
+	workerConfig.AllowPrivateNetworks = true
+	worker := webhook.NewWorker(webhookRepo, workerConfig, log, nil)
+	deliver := func() {
+		_, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		_, err = worker.RunOnce(context.Background())
+		require.NoError(t, err)
+	}
+
+	// send issues a request and decodes the response into out
+	send := func(method, path, body string, out interface{}) int {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if out != nil && resp.StatusCode < 300 {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+		return resp.StatusCode
+	}
+	createTransaction := func() string {
+		var created handler.CreateTransactionResponse
+		require.Equal(t, http.StatusCreated, send(http.MethodPost, "/transactions",
+			`{"description": "Fuel", "date": "2023-04-15", "amount": 40}`, &created))
+		return created.ID
+	}
+
+	var subscription handler.WebhookSubscriptionResponse
+	t.Run("Register a subscription", func(t *testing.T) {
+		body := `{"url": "` + receiver.URL + `", "events": ["TransactionCreated", "TransactionUpdated"]}`
+		require.Equal(t, http.StatusCreated, send(http.MethodPost, "/webhooks", body, &subscription))
+		assert.NotEmpty(t, subscription.ID)
+		assert.NotEmpty(t, subscription.Secret)
+		secret = subscription.Secret
+
+		// The secret is only returned on creation
+		var fetched handler.WebhookSubscriptionResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/webhooks/"+subscription.ID, "", &fetched))
+		assert.Empty(t, fetched.Secret)
+		assert.Equal(t, receiver.URL, fetched.URL)
+
+		var list handler.WebhookSubscriptionListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/webhooks", "", &list))
+		assert.Len(t, list.Subscriptions, 1)
+	})
+
+	t.Run("Invalid subscriptions are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/webhooks", `{"url": "ftp://example.com", "events": ["TransactionCreated"]}`, nil))
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/webhooks", `{"url": "https://example.com", "events": []}`, nil))
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/webhooks", `{"url": "https://example.com", "events": ["Nope"]}`, nil))
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/webhooks", `{"url":`, nil))
+	})
+
+	t.Run("Transaction events are delivered signed", func(t *testing.T) {
+		id := createTransaction()
+		require.Equal(t, http.StatusOK, send(http.MethodPut, "/transactions/"+id+"/legal-hold", `{"reason": "Dispute"}`, nil))
+		deliver()
+
+		require.Len(t, received, 2)
+		assert.Equal(t, entity.EventTransactionCreated, received[0].Type)
+		assert.Equal(t, entity.EventTransactionUpdated, received[1].Type)
+		assert.Equal(t, id, received[1].AggregateID)
+
+		var history handler.WebhookDeliveryListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/admin/webhooks/"+subscription.ID+"/deliveries", "", &history))
+		require.Len(t, history.Deliveries, 2)
+		assert.Equal(t, entity.EventTransactionUpdated, history.Deliveries[0].EventType)
+		assert.Equal(t, entity.DeliveryDelivered, history.Deliveries[0].Status)
+		require.Len(t, history.Deliveries[0].History, 1)
+		assert.Equal(t, http.StatusOK, history.Deliveries[0].History[0].StatusCode)
+	})
+
+	t.Run("Failed deliveries are dead-lettered and can be retried", func(t *testing.T) {
+		accept = false
+		createTransaction()
+		deliver()
+
+		var dead handler.WebhookDeliveryListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/admin/webhooks/dead-letters", "", &dead))
+		require.Len(t, dead.Deliveries, 1)
+		deliveryID := dead.Deliveries[0].ID
+		assert.Equal(t, http.StatusInternalServerError, dead.Deliveries[0].History[0].StatusCode)
+
+		var history handler.WebhookDeliveryListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/admin/webhooks/"+subscription.ID+"/deliveries?status=dead", "", &history))
+		assert.Len(t, history.Deliveries, 1)
+
+		accept = true
+		var retried handler.WebhookDeliveryResponse
+		require.Equal(t, http.StatusAccepted, send(http.MethodPost, "/admin/webhooks/deliveries/"+deliveryID+"/retry", "", &retried))
+		assert.Equal(t, entity.DeliveryPending, retried.Status)
+		assert.Equal(t, http.StatusConflict, send(http.MethodPost, "/admin/webhooks/deliveries/"+deliveryID+"/retry", "", nil))
+		deliver()
+
+		assert.Len(t, received, 3)
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/admin/webhooks/dead-letters", "", &dead))
+		assert.Empty(t, dead.Deliveries)
+	})
+
+	t.Run("Invalid admin requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/admin/webhooks/"+subscription.ID+"/deliveries?status=lost", "", nil))
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/admin/webhooks/"+subscription.ID+"/deliveries?limit=0", "", nil))
+		assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/admin/webhooks/deliveries/unknown/retry", "", nil))
+	})
+
+	t.Run("Delete a subscription", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/webhooks/"+subscription.ID, "", nil))
+		assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, "/webhooks/"+subscription.ID, "", nil))
+		assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/webhooks/"+subscription.ID, "", nil))
+
+		// Its history is kept
+		var history handler.WebhookDeliveryListResponse
+		require.Equal(t, http.StatusOK, send(http.MethodGet, "/admin/webhooks/"+subscription.ID+"/deliveries", "", &history))
+		assert.Len(t, history.Deliveries, 3)
+	})
+}
+
+// sseEvent is an event read from a server-sent events stream
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// sseStream reads the events of an open stream
+type sseStream struct {
+	reader *bufio.Reader
+}
+
+// openStream opens the transaction stream with the given query and headers,
+// waiting for the server to accept it
+func openStream(t *testing.T, server *httptest.Server, query string, header http.Header) *sseStream {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/transactions/stream"+query, nil)
+	require.NoError(t, err)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	stream := &sseStream{reader: bufio.NewReader(resp.Body)}
+	retry, _ := stream.next(t, false)
+	assert.Equal(t, "3000", retry.Data)
+	return stream
+}
+
+// next reads the next event, or the next heartbeat comment if heartbeat is set.
+// The retry field is returned as an event's data.
+func (s *sseStream) next(t *testing.T, heartbeat bool) (sseEvent, bool) {
+	t.Helper()
+	var event sseEvent
+	for {
+		line, err := s.reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if event != (sseEvent{}) {
+				return event, false
+			}
+		case strings.HasPrefix(line, ":"):
+			if heartbeat {
+				return sseEvent{}, true
+			}
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		case strings.HasPrefix(line, "retry: "):
+			event.Data = strings.TrimPrefix(line, "retry: ")
+		}
+	}
+}
+
+// transaction reads the next event, which must be a transaction
+func (s *sseStream) transaction(t *testing.T) (string, handler.TransactionResponse) {
+	t.Helper()
+	event, _ := s.next(t, false)
+	require.Equal(t, "transaction", event.Event)
+	var tx handler.TransactionResponse
+	require.NoError(t, json.Unmarshal([]byte(event.Data), &tx))
+	return event.ID, tx
+}
+
+func TestTransactionStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	server, _, cleanup, err := setupTestServer(new(mocks.MockExchangeRateRepository))
+	if err != nil {
+		t.Fatalf("Failed to setup test server: %v", err)
+	}
+	defer cleanup()
+
+	create := func(description string, amount float64) string {
+		resp, err := http.Post(server.URL+"/transactions", "application/json",
+			bytes.NewBufferString(fmt.Sprintf(`{"description": %q, "date": "2023-04-15", "amount": %g}`, description, amount)))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created handler.CreateTransactionResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		return created.ID
+	}
+
+	// Transactions stored before a stream opens are not sent to it
+	create("Before", 1)
+
+	all := openStream(t, server, "", nil)
+	large := openStream(t, server, "?min_amount=100", nil)
+	coffee := create("Coffee", 4.5)
+	laptop := create("Laptop", 1299.99)
+
+	t.Run("New transactions are pushed in order", func(t *testing.T) {
+		id, tx := all.transaction(t)
+		assert.Equal(t, "2", id)
+		assert.Equal(t, coffee, tx.ID)
+		assert.Equal(t, "Coffee", tx.Description)
+
+		id, tx = all.transaction(t)
+		assert.Equal(t, "3", id)
+		assert.Equal(t, laptop, tx.ID)
+		assert.Equal(t, 1299.99, tx.Amount)
+	})
+
+	t.Run("Filtered by minimum amount", func(t *testing.T) {
+		id, tx := large.transaction(t)
+		assert.Equal(t, "3", id)
+		assert.Equal(t, laptop, tx.ID)
+	})
+
+	t.Run("Resumes after Last-Event-ID", func(t *testing.T) {
+		resumed := openStream(t, server, "", http.Header{"Last-Event-ID": {"1"}})
+		_, tx := resumed.transaction(t)
+		assert.Equal(t, coffee, tx.ID)
+		_, tx = resumed.transaction(t)
+		assert.Equal(t, laptop, tx.ID)
+
+		resumed = openStream(t, server, "?last_event_id=2", nil)
+		_, tx = resumed.transaction(t)
+		assert.Equal(t, laptop, tx.ID)
+	})
+
+	t.Run("Idle streams receive heartbeats", func(t *testing.T) {
+		_, heartbeat := all.next(t, true)
+		assert.True(t, heartbeat)
+	})
+
+	t.Run("Invalid parameters are rejected", func(t *testing.T) {
+		for _, query := range []string{"?min_amount=-1", "?max_amount=lots", "?min_amount=10&max_amount=5", "?last_event_id=abc"} {
+			resp, err := http.Get(server.URL + "/transactions/stream" + query)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+}
+
 func TestCurrencyConversion(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -163,7 +634,7 @@ func TestCurrencyConversion(t *testing.T) {
 	log := logger.NewJSONLogger(nil, logger.InfoLevel)
 
 	// Insert a test transaction directly into the database
-	txRepo := db.NewBadgerTransactionRepository(badgerDB, log)
+	txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
 	testDate, err := time.Parse("2006-01-02", "2023-04-15")
 	if err != nil {
 		t.Fatalf("Failed to parse test date: %v", err)
@@ -176,7 +647,7 @@ func TestCurrencyConversion(t *testing.T) {
 		Amount:      123.45,
 		CreatedAt:   time.Now(),
 	}
-	testTx.CalculateTTL()
+	testTx.CalculateTTL(entity.DefaultRetention)
 
 	_, err = txRepo.Store(context.Background(), testTx)
 	assert.NoError(t, err, "Failed to store test transaction")
@@ -217,6 +688,201 @@ func TestCurrencyConversion(t *testing.T) {
 	mockExchangeRateRepo.AssertExpectations(t)
 }
 
+func TestExport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	rates := new(mocks.MockExchangeRateRepository)
+	server, _, cleanup, err := setupTestServer(rates)
+	require.NoError(t, err)
+	defer cleanup()
+
+	ids := make(map[string]string)
+	for _, tx := range []struct{ description, date string }{
+		{"January", "2023-01-15"},
+		{"February", "2023-02-15"},
+		{"March", "2023-03-15"},
+	} {
+		resp, err := http.Post(server.URL+"/transactions", "application/json",
+			strings.NewReader(fmt.Sprintf(`{"description": %q, "date": %q, "amount": 100}`, tx.description, tx.date)))
+		require.NoError(t, err)
+		var created handler.CreateTransactionResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		resp.Body.Close()
+		ids[tx.description] = created.ID
+	}
+
+	feb := time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+	rates.On("FindRate", mock.Anything, "EUR", feb).
+		Return(&entity.ExchangeRate{Currency: "EUR", Date: feb.AddDate(0, 0, -10), Rate: 0.9}, nil).Once()
+	rates.On("FindRate", mock.Anything, "EUR", mar).
+		Return(nil, &repository.LookbackError{Months: 6, Err: fmt.Errorf("%w for EUR", repository.ErrNoExchangeRate)}).Once()
+
+	t.Run("CSV with conversions", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/transactions/export?format=csv&currency=EUR&from=2023-02-01&to=2023-03-31")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="transactions-EUR.csv"`, resp.Header.Get("Content-Disposition"))
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"ID", "Date", "Description", "Amount (USD)", "Currency", "Exchange Rate",
+			"Rate Date", "Converted Amount", "Conversion Error"}, records[0])
+
+		byDescription := map[string][]string{}
+		for _, record := range records[1:] {
+			byDescription[record[2]] = record
+		}
+		assert.Equal(t, []string{ids["February"], "2023-02-15", "February", "100", "EUR", "0.9", "2023-02-05", "90", ""},
+			byDescription["February"])
+		assert.Equal(t, []string{ids["March"], "2023-03-15", "March", "100", "EUR", "", "", "",
+			"No exchange rate is available within 6 months before the transaction date for the currency"}, byDescription["March"])
+		rates.AssertExpectations(t)
+	})
+
+	t.Run("XLSX without conversions", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/transactions/export?format=xlsx")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", resp.Header.Get("Content-Type"))
+
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		for _, file := range archive.File {
+			if file.Name != "xl/worksheets/sheet1.xml" {
+				continue
+			}
+			rc, err := file.Open()
+			require.NoError(t, err)
+			sheet, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			assert.Equal(t, 4, strings.Count(string(sheet), "<row "))
+			for _, id := range ids {
+				assert.Contains(t, string(sheet), id)
+			}
+		}
+	})
+
+	for name, query := range map[string]string{
+		"Unknown format":   "format=pdf",
+		"Invalid date":     "from=15-01-2023",
+		"Inverted range":   "from=2023-03-01&to=2023-02-01",
+		"Invalid currency": "currency=EURO",
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/transactions/export?" + query)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestSummaryReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	rates := new(mocks.MockExchangeRateRepository)
+	server, _, cleanup, err := setupTestServer(rates)
+	require.NoError(t, err)
+	defer cleanup()
+
+	create := func(category, date string, amount float64) {
+		resp, err := http.Post(server.URL+"/transactions", "application/json",
+			strings.NewReader(fmt.Sprintf(`{"description": "Fleet", "category": %q, "date": %q, "amount": %v}`, category, date, amount)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+	create("fuel", "2023-01-15", 40)
+	create("tolls", "2023-01-20", 10)
+	create("fuel", "2023-03-02", 60)
+	create("", "2022-12-31", 99)
+	// A category named like the uncategorized group is kept apart from it
+	create("uncategorized", "2022-12-20", 7)
+
+	summary := func(query string) handler.SummaryReportResponse {
+		resp, err := http.Get(server.URL + "/reports/summary?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var report handler.SummaryReportResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return report
+	}
+
+	t.Run("By month", func(t *testing.T) {
+		report := summary("group_by=month&from=2023-01&to=2023-03")
+		assert.Equal(t, "month", report.GroupBy)
+		assert.Equal(t, "2023-01", report.From)
+		assert.Equal(t, "2023-03", report.To)
+		require.Len(t, report.Groups, 2)
+		january := "2023-01"
+		assert.Equal(t, handler.ReportGroupResponse{Key: &january,
+			USD: handler.SummaryResponse{Count: 2, Sum: 50, Min: 10, Max: 40, Average: 25}}, report.Groups[0])
+		require.NotNil(t, report.Groups[1].Key)
+		assert.Equal(t, "2023-03", *report.Groups[1].Key)
+
+		// A new transaction in a reported month shows in the next report
+		create("tolls", "2023-01-31", 5.5)
+		report = summary("group_by=month&from=2023-01&to=2023-03")
+		assert.Equal(t, handler.SummaryResponse{Count: 3, Sum: 55.5, Min: 5.5, Max: 40, Average: 18.5}, report.Groups[0].USD)
+	})
+
+	t.Run("By category with conversion", func(t *testing.T) {
+		rates.On("FindRate", mock.Anything, "EUR", mock.Anything).
+			Return(&entity.ExchangeRate{Currency: "EUR", Date: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC), Rate: 0.5}, nil)
+
+		report := summary("group_by=category&currency=EUR&from=2022-12&to=2023-03")
+		assert.Equal(t, "EUR", report.Currency)
+		require.Len(t, report.Groups, 4)
+		uncategorized := report.Groups[0]
+		assert.Nil(t, uncategorized.Key)
+		assert.Equal(t, handler.SummaryResponse{Count: 1, Sum: 99, Min: 99, Max: 99, Average: 99}, uncategorized.USD)
+		var keys []string
+		for _, group := range report.Groups[1:] {
+			require.NotNil(t, group.Key)
+			keys = append(keys, *group.Key)
+		}
+		assert.Equal(t, []string{"fuel", "tolls", "uncategorized"}, keys)
+		assert.Equal(t, handler.SummaryResponse{Count: 1, Sum: 7, Min: 7, Max: 7, Average: 7}, report.Groups[3].USD)
+
+		fuel := report.Groups[1]
+		assert.Equal(t, handler.SummaryResponse{Count: 2, Sum: 100, Min: 40, Max: 60, Average: 50}, fuel.USD)
+		require.NotNil(t, fuel.Converted)
+		assert.Equal(t, &handler.ConvertedSummaryResponse{
+			Currency:        "EUR",
+			SummaryResponse: handler.SummaryResponse{Count: 2, Sum: 50, Min: 20, Max: 30, Average: 25},
+		}, fuel.Converted)
+	})
+
+	for name, query := range map[string]string{
+		"Unknown grouping": "group_by=week",
+		"Invalid month":    "from=2023-1-01",
+		"Inverted range":   "from=2023-03&to=2023-01",
+		"Period too long":  "from=2000-01&to=2023-01",
+		"Invalid currency": "currency=EURO",
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/reports/summary?" + query)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -322,6 +988,32 @@ func TestErrorHandling(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
+	t.Run("Expired transaction", func(t *testing.T) {
+		// Created before the retention period, so it is stored already expired
+		createdAt := time.Now().Add(-2 * entity.DefaultRetention)
+		testTx := &entity.Transaction{
+			ID:          "expired-test-id",
+			Description: "Test transaction",
+			Date:        createdAt,
+			Amount:      123.45,
+			CreatedAt:   createdAt,
+		}
+		testTx.CalculateTTL(entity.DefaultRetention)
+
+		txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
+		_, err := txRepo.Store(context.Background(), testTx)
+		assert.NoError(t, err, "Failed to store test transaction")
+
+		for _, path := range []string{"/transactions/expired-test-id", "/transactions/expired-test-id/convert?currency=EUR"} {
+			resp, err := http.Get(server.URL + path)
+			if err != nil {
+				t.Fatalf("Failed to send expired transaction request: %v", err)
+			}
+			resp.Body.Close()
+			assert.Equal(t, http.StatusGone, resp.StatusCode, path)
+		}
+	})
+
 	t.Run("Missing currency parameter", func(t *testing.T) {
 		// First, create a test transaction in the database
 		testDate := time.Now().AddDate(0, 0, -30) // 30 days ago
@@ -332,9 +1024,9 @@ func TestErrorHandling(t *testing.T) {
 			Amount:      123.45,
 			CreatedAt:   time.Now(),
 		}
-		testTx.CalculateTTL()
+		testTx.CalculateTTL(entity.DefaultRetention)
 
-		txRepo := db.NewBadgerTransactionRepository(badgerDB, log)
+		txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
 		_, err := txRepo.Store(context.Background(), testTx)
 		assert.NoError(t, err, "Failed to store test transaction")
 
@@ -381,16 +1073,16 @@ func TestErrorHandling(t *testing.T) {
 			Amount:      123.45,
 			CreatedAt:   time.Now(),
 		}
-		testTx.CalculateTTL()
+		testTx.CalculateTTL(entity.DefaultRetention)
 
-		txRepo := db.NewBadgerTransactionRepository(badgerDB, log)
+		txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
 		_, err := txRepo.Store(context.Background(), testTx)
 		assert.NoError(t, err, "Failed to store test transaction")
 
 		// Mock the repository to return error for XYZ currency
 		mockExchangeRateRepo.On("FindRate", mock.Anything, "XYZ", mock.Anything).
-			Return(nil, fmt.Errorf("no exchange rate available within 6 months of %s for currency XYZ",
-				testDate.Format("2006-01-02"))).Once()
+			Return(nil, &repository.LookbackError{Months: 3, Err: fmt.Errorf("%w within 3 months of %s for currency XYZ",
+				repository.ErrNoExchangeRate, testDate.Format("2006-01-02"))}).Once()
 
 		// Test conversion with a currency that has no rate
 		resp, err := http.Get(server.URL + "/transactions/no-rate-test-id/convert?currency=XYZ")
@@ -405,5 +1097,7 @@ func TestErrorHandling(t *testing.T) {
 		err = json.NewDecoder(resp.Body).Decode(&errorResp)
 		assert.NoError(t, err, "Failed to decode error response")
 		assert.Contains(t, errorResp.Error, "No exchange rate available")
+		assert.Equal(t, "No exchange rate is available within 3 months before the transaction date for the currency",
+			errorResp.Description)
 	})
 }
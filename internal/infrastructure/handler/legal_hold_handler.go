@@ -0,0 +1,150 @@
+// Package handler internal/infrastructure/handler/legal_hold_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// LegalHoldHandler serves the endpoints that place, release and list legal holds.
+// Holds are recorded against the authenticated subject.
+type LegalHoldHandler struct {
+	service *service.TransactionService
+	logger  logger.Logger
+}
+
+// NewLegalHoldHandler creates a new legal hold handler
+func NewLegalHoldHandler(service *service.TransactionService, log logger.Logger) *LegalHoldHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &LegalHoldHandler{
+		service: service,
+		logger:  log,
+	}
+}
+
+// PlaceHold puts a transaction under legal hold
+func (h *LegalHoldHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	id := mux.Vars(r)["id"]
+
+	var req LegalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, log, "Invalid request body",
+			"The request body could not be parsed as valid JSON", http.StatusBadRequest, requestID)
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		sendErrorResponse(w, log, "Invalid legal hold",
+			"A reason is required to place a legal hold", http.StatusBadRequest, requestID)
+		return
+	}
+	if len(reason) > entity.MaxLegalHoldReasonLength {
+		sendErrorResponse(w, log, "Invalid legal hold",
+			"Reason must not exceed "+strconv.Itoa(entity.MaxLegalHoldReasonLength)+" characters",
+			http.StatusBadRequest, requestID)
+		return
+	}
+
+	tx, err := h.service.PlaceLegalHold(r.Context(), id, reason, middleware.GetSubject(r.Context()))
+	if err != nil {
+		h.sendHoldError(w, log, id, err, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTransactionResponse(tx))
+}
+
+// ReleaseHold lifts a transaction's legal hold
+func (h *LegalHoldHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	id := mux.Vars(r)["id"]
+
+	tx, err := h.service.ReleaseLegalHold(r.Context(), id, middleware.GetSubject(r.Context()))
+	if err != nil {
+		h.sendHoldError(w, log, id, err, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTransactionResponse(tx))
+}
+
+// ListHolds returns every transaction of the tenant that is under legal hold
+func (h *LegalHoldHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+
+	resp := LegalHoldListResponse{Transactions: []TransactionResponse{}}
+	err := h.service.ListLegalHolds(r.Context(), func(tx *entity.Transaction) error {
+		resp.Transactions = append(resp.Transactions, newTransactionResponse(tx))
+		return nil
+	})
+	if err != nil {
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while listing legal holds", http.StatusInternalServerError, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// sendHoldError maps an error from placing or releasing a hold to a response
+func (h *LegalHoldHandler) sendHoldError(w http.ResponseWriter, log logger.Logger, id string, err error, requestID string) {
+	switch {
+	case errors.Is(err, repository.ErrTransactionExpired):
+		sendErrorResponse(w, log, "Transaction expired",
+			"The requested transaction has passed its retention period", http.StatusGone, requestID)
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		sendErrorResponse(w, log, "Transaction not found",
+			"The requested transaction could not be found", http.StatusNotFound, requestID)
+	case errors.Is(err, repository.ErrLegalHoldExists):
+		sendErrorResponse(w, log, "Legal hold exists",
+			"The transaction is already under legal hold", http.StatusConflict, requestID)
+	case errors.Is(err, repository.ErrNoLegalHold):
+		sendErrorResponse(w, log, "No legal hold",
+			"The transaction is not under legal hold", http.StatusConflict, requestID)
+	default:
+		log.Error("Unexpected error updating legal hold", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while updating the legal hold",
+			http.StatusInternalServerError, requestID)
+	}
+}
+
+// RegisterRoutes registers the legal hold routes
+func (h *LegalHoldHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/transactions/{id}/legal-hold", h.PlaceHold).Methods("PUT")
+	router.HandleFunc("/transactions/{id}/legal-hold", h.ReleaseHold).Methods("DELETE")
+	router.HandleFunc("/legal-holds", h.ListHolds).Methods("GET")
+
+	h.logger.Info("Legal hold routes registered", map[string]interface{}{
+		"routes": []string{
+			"PUT /transactions/{id}/legal-hold",
+			"DELETE /transactions/{id}/legal-hold",
+			"GET /legal-holds",
+		},
+	})
+}
@@ -0,0 +1,115 @@
+// Package handler internal/infrastructure/handler/log_level_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// LogLevelRequest is the body of a log level change. Components replaces all
+// per-component overrides; omit it or send an empty object to clear them.
+type LogLevelRequest struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components,omitempty"`
+}
+
+// LogLevelResponse reports the current log levels
+type LogLevelResponse struct {
+	Level      logger.Level            `json:"level"`
+	Components map[string]logger.Level `json:"components"`
+}
+
+// LogLevelHandler serves the admin endpoint for changing log levels at runtime
+type LogLevelHandler struct {
+	levels *logger.LevelController
+	logger logger.Logger
+}
+
+// NewLogLevelHandler creates a new log level handler
+func NewLogLevelHandler(levels *logger.LevelController, log logger.Logger) *LogLevelHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &LogLevelHandler{
+		levels: levels,
+		logger: log,
+	}
+}
+
+// GetLevels returns the default level and the per-component overrides
+func (h *LogLevelHandler) GetLevels(w http.ResponseWriter, r *http.Request) {
+	h.sendLevels(w)
+}
+
+// SetLevels replaces the default level and the per-component overrides
+func (h *LogLevelHandler) SetLevels(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, log, "Invalid request body",
+			"The request body could not be parsed as valid JSON", http.StatusBadRequest, requestID)
+		return
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		sendErrorResponse(w, log, "Invalid log level", err.Error(), http.StatusBadRequest, requestID)
+		return
+	}
+
+	components := make(map[string]logger.Level, len(req.Components))
+	for component, name := range req.Components {
+		componentLevel, err := logger.ParseLevel(name)
+		if err != nil {
+			sendErrorResponse(w, log, "Invalid log level",
+				"component "+component+": "+err.Error(), http.StatusBadRequest, requestID)
+			return
+		}
+		components[component] = componentLevel
+	}
+
+	previous := h.levels.Level()
+	previousComponents := h.levels.ComponentLevels()
+	h.levels.Set(level, components)
+
+	// Logged at warn so the change is recorded unless only errors are being logged
+	log.Warn("Log level changed", map[string]interface{}{
+		"previous_log_level":  previous,
+		"previous_components": logger.FormatComponentLevels(previousComponents),
+		"log_level":           level,
+		"components":          logger.FormatComponentLevels(components),
+	})
+
+	h.sendLevels(w)
+}
+
+// sendLevels writes the current levels
+func (h *LogLevelHandler) sendLevels(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(LogLevelResponse{
+		Level:      h.levels.Level(),
+		Components: h.levels.ComponentLevels(),
+	})
+}
+
+// RegisterRoutes registers the log level routes
+func (h *LogLevelHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/admin/log-level", h.GetLevels).Methods("GET")
+	router.HandleFunc("/admin/log-level", h.SetLevels).Methods("PUT")
+
+	h.logger.Info("Admin routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /admin/log-level",
+			"PUT /admin/log-level",
+		},
+	})
+}
@@ -0,0 +1,60 @@
+// internal/infrastructure/handler/log_level_handler_test.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	levels := logger.NewLevelController(logger.InfoLevel)
+	router := mux.NewRouter()
+	NewLogLevelHandler(levels, logger.NewJSONLogger(nil, logger.InfoLevel)).RegisterRoutes(router)
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body)))
+		return w
+	}
+
+	t.Run("Get current levels", func(t *testing.T) {
+		w := do("GET", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"level":"INFO","components":{}}`, w.Body.String())
+	})
+
+	t.Run("Set level and component overrides", func(t *testing.T) {
+		w := do("PUT", `{"level":"warn","components":{"api":"debug"}}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp LogLevelResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, logger.WarnLevel, resp.Level)
+		assert.Equal(t, map[string]logger.Level{"api": logger.DebugLevel}, resp.Components)
+
+		assert.True(t, levels.Enabled("api", logger.DebugLevel))
+		assert.False(t, levels.Enabled("db", logger.InfoLevel))
+	})
+
+	t.Run("Omitting components clears the overrides", func(t *testing.T) {
+		w := do("PUT", `{"level":"INFO"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, levels.ComponentLevels())
+	})
+
+	t.Run("Invalid levels are rejected", func(t *testing.T) {
+		for _, body := range []string{`{"level":"loud"}`, `{"level":"INFO","components":{"api":"x"}}`, `not json`} {
+			w := do("PUT", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+		assert.Equal(t, logger.InfoLevel, levels.Level())
+	})
+}
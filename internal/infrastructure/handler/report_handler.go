@@ -0,0 +1,145 @@
+// Package handler internal/infrastructure/handler/report_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// ReportHandler serves summary reports of transactions
+type ReportHandler struct {
+	service *service.ReportService
+	logger  logger.Logger
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(service *service.ReportService, log logger.Logger) *ReportHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &ReportHandler{
+		service: service,
+		logger:  log,
+	}
+}
+
+// Summary returns the count, sum, minimum, maximum and average amount of the
+// tenant's transactions per month or category, per the group_by parameter, over
+// the months from and to. With a currency parameter each group also summarises
+// its amounts converted to that currency.
+func (h *ReportHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := middleware.GetRequestID(ctx)
+	log := logger.ForContext(ctx, h.logger)
+	query := r.URL.Query()
+
+	filter := service.ReportFilter{GroupBy: query.Get("group_by")}
+	if filter.GroupBy == "" {
+		filter.GroupBy = service.GroupByMonth
+	}
+	if filter.GroupBy != service.GroupByMonth && filter.GroupBy != service.GroupByCategory {
+		sendErrorResponse(w, log, "Invalid grouping",
+			"The group_by parameter must be month or category", http.StatusBadRequest, requestID)
+		return
+	}
+
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		month, err := time.Parse("2006-01", raw)
+		if err != nil {
+			sendErrorResponse(w, log, "Invalid month",
+				"The "+param.name+" parameter must be in YYYY-MM format", http.StatusBadRequest, requestID)
+			return
+		}
+		*param.value = month
+	}
+
+	filter.Currency = query.Get("currency")
+	if filter.Currency != "" && len(filter.Currency) != 3 {
+		sendErrorResponse(w, log, "Invalid currency code",
+			"Currency code should be 3 characters (e.g., EUR, GBP, CAD)", http.StatusBadRequest, requestID)
+		return
+	}
+
+	report, err := h.service.Summary(ctx, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReport) {
+			log.Warn("Invalid report period", map[string]interface{}{
+				"error": err.Error(),
+			})
+			sendErrorResponse(w, log, "Invalid report period",
+				"The from month must not be after to, and the period must not exceed 120 months",
+				http.StatusBadRequest, requestID)
+			return
+		}
+		log.Error("Failed to build summary report", map[string]interface{}{
+			"error": err.Error(),
+		})
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while building the report", http.StatusInternalServerError, requestID)
+		return
+	}
+
+	resp := SummaryReportResponse{
+		GroupBy:  report.GroupBy,
+		From:     report.From.Format("2006-01"),
+		To:       report.To.Format("2006-01"),
+		Currency: report.Currency,
+		Groups:   []ReportGroupResponse{},
+	}
+	for _, group := range report.Groups {
+		groupResp := ReportGroupResponse{USD: newSummaryResponse(group.USD)}
+		if group.Key != "" {
+			key := group.Key
+			groupResp.Key = &key
+		}
+		if group.Converted != nil {
+			groupResp.Converted = &ConvertedSummaryResponse{
+				Currency:        report.Currency,
+				SummaryResponse: newSummaryResponse(*group.Converted),
+				Unconverted:     group.Unconverted,
+			}
+		}
+		resp.Groups = append(resp.Groups, groupResp)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// newSummaryResponse converts a summary to its API representation
+func newSummaryResponse(summary service.Summary) SummaryResponse {
+	return SummaryResponse{
+		Count:   summary.Count,
+		Sum:     summary.Sum,
+		Min:     summary.Min,
+		Max:     summary.Max,
+		Average: summary.Average,
+	}
+}
+
+// RegisterRoutes registers the report routes
+func (h *ReportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/reports/summary", h.Summary).Methods("GET")
+
+	h.logger.Info("Report routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /reports/summary",
+		},
+	})
+}
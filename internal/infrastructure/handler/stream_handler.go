@@ -0,0 +1,190 @@
+// Package handler internal/infrastructure/handler/stream_handler.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// streamRetry is the reconnection delay suggested to stream clients
+const streamRetry = 3 * time.Second
+
+// StreamHandler serves the server-sent events stream of new transactions
+type StreamHandler struct {
+	service   *service.TransactionStreamService
+	heartbeat time.Duration
+	logger    logger.Logger
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewStreamHandler creates a new stream handler that writes a heartbeat comment
+// to idle streams at the given interval
+func NewStreamHandler(service *service.TransactionStreamService, heartbeat time.Duration, log logger.Logger) *StreamHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &StreamHandler{
+		service:   service,
+		heartbeat: heartbeat,
+		logger:    log,
+		closed:    make(chan struct{}),
+	}
+}
+
+// Stream sends each transaction stored after the stream opens as a "transaction"
+// event whose ID is its sequence number. A client that reconnects with the
+// Last-Event-ID header, or the last_event_id parameter, first receives the
+// transactions it missed. min_amount and max_amount filter the transactions sent.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := middleware.GetRequestID(ctx)
+	log := logger.ForContext(ctx, h.logger)
+	query := r.URL.Query()
+
+	var filter service.StreamFilter
+	for _, param := range []struct {
+		name  string
+		value *float64
+	}{{"min_amount", &filter.MinAmount}, {"max_amount", &filter.MaxAmount}} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil || amount < 0 {
+			sendErrorResponse(w, log, "Invalid filter",
+				"The "+param.name+" parameter must be a non-negative number", http.StatusBadRequest, requestID)
+			return
+		}
+		*param.value = amount
+	}
+	if filter.MinAmount > 0 && filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount {
+		sendErrorResponse(w, log, "Invalid filter",
+			"The min_amount parameter must not exceed max_amount", http.StatusBadRequest, requestID)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			sendErrorResponse(w, log, "Invalid event ID",
+				"The last event ID must be an event ID sent by this stream", http.StatusBadRequest, requestID)
+			return
+		}
+	} else {
+		var err error
+		if after, err = h.service.Head(ctx); err != nil {
+			sendErrorResponse(w, log, "Internal server error",
+				"An unexpected error occurred while opening the stream", http.StatusInternalServerError, requestID)
+			return
+		}
+	}
+
+	// The stream outlives the server's read and write timeouts
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	changes := h.service.Watch(watchCtx)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		log.Error("Transaction stream cannot be flushed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	log.Info("Transaction stream opened", map[string]interface{}{
+		"after":      after,
+		"min_amount": filter.MinAmount,
+		"max_amount": filter.MaxAmount,
+	})
+	defer log.Info("Transaction stream closed", nil)
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		// Catching up on every wake-up, heartbeats included, also picks up any
+		// transaction stored before the watch began
+		var err error
+		after, err = h.service.Since(ctx, after, filter, func(entry *entity.StreamEntry) error {
+			data, err := json.Marshal(newTransactionResponse(&entry.Transaction))
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: transaction\ndata: %s\n\n", entry.Sequence, data)
+			return err
+		})
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn("Transaction stream failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.closed:
+			return
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close ends every open stream. Clients reconnect, to another instance if this
+// one is shutting down.
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// RegisterRoutes registers the stream routes. They must be registered before the
+// transaction routes, whose /transactions/{id} would otherwise match.
+func (h *StreamHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/transactions/stream", h.Stream).Methods("GET")
+
+	h.logger.Info("Stream routes registered", map[string]interface{}{
+		"routes": []string{
+			"GET /transactions/stream",
+		},
+	})
+}
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
 	"github.com/gorilla/mux"
@@ -34,51 +37,71 @@ func NewTransactionHandler(service *service.TransactionService, log logger.Logge
 func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetRequestID(r.Context())
 
-	h.logger.Info("Handling create transaction request", map[string]interface{}{
-		"request_id": requestID,
-		"method":     r.Method,
-		"path":       r.URL.Path,
+	log := logger.ForContext(r.Context(), h.logger)
+
+	log.Info("Handling create transaction request", map[string]interface{}{
+		"method": r.Method,
+		"path":   r.URL.Path,
 	})
 
 	// Parse request body
 	var req CreateTransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Invalid request body", map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Warn("Request body too large", map[string]interface{}{
+				"limit": maxBytesErr.Limit,
+			})
+			sendErrorResponse(w, log, "Request body too large",
+				"The request body exceeds the maximum allowed size", http.StatusRequestEntityTooLarge, requestID)
+			return
+		}
+
+		log.Warn("Invalid request body", map[string]interface{}{
+			"error": err.Error(),
 		})
-		sendErrorResponse(w, h.logger, "Invalid request body",
+		sendErrorResponse(w, log, "Invalid request body",
 			"The request body could not be parsed as valid JSON", http.StatusBadRequest, requestID)
 		return
 	}
 
-	h.logger.Debug("Request parsed", map[string]interface{}{
-		"request_id":  requestID,
+	log.Debug("Request parsed", map[string]interface{}{
 		"description": req.Description,
+		"category":    req.Category,
 		"date":        req.Date,
 		"amount":      req.Amount,
 	})
 
 	// Validate description length
 	if len(req.Description) > 50 {
-		h.logger.Warn("Description too long", map[string]interface{}{
-			"request_id":  requestID,
+		log.Warn("Description too long", map[string]interface{}{
 			"description": req.Description,
 			"length":      len(req.Description),
 			"max_allowed": 50,
 		})
-		sendErrorResponse(w, h.logger, "Description too long",
+		sendErrorResponse(w, log, "Description too long",
 			"Description must not exceed 50 characters", http.StatusBadRequest, requestID)
 		return
 	}
 
+	// Validate category length
+	req.Category = strings.TrimSpace(req.Category)
+	if len(req.Category) > entity.MaxCategoryLength {
+		log.Warn("Category too long", map[string]interface{}{
+			"length":      len(req.Category),
+			"max_allowed": entity.MaxCategoryLength,
+		})
+		sendErrorResponse(w, log, "Category too long",
+			"Category must not exceed 50 characters", http.StatusBadRequest, requestID)
+		return
+	}
+
 	// Validate amount is positive
 	if req.Amount <= 0 {
-		h.logger.Warn("Invalid amount", map[string]interface{}{
-			"request_id": requestID,
-			"amount":     req.Amount,
+		log.Warn("Invalid amount", map[string]interface{}{
+			"amount": req.Amount,
 		})
-		sendErrorResponse(w, h.logger, "Invalid amount",
+		sendErrorResponse(w, log, "Invalid amount",
 			"Amount must be a positive value", http.StatusBadRequest, requestID)
 		return
 	}
@@ -86,61 +109,61 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 	// Parse date
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		h.logger.Warn("Invalid date format", map[string]interface{}{
-			"request_id": requestID,
-			"date":       req.Date,
-			"error":      err.Error(),
+		log.Warn("Invalid date format", map[string]interface{}{
+			"date":  req.Date,
+			"error": err.Error(),
 		})
-		sendErrorResponse(w, h.logger, "Invalid date format",
+		sendErrorResponse(w, log, "Invalid date format",
 			"Date must be in YYYY-MM-DD format", http.StatusBadRequest, requestID)
 		return
 	}
 
 	// Don't allow future dates
 	if date.After(time.Now()) {
-		h.logger.Warn("Future date not allowed", map[string]interface{}{
-			"request_id": requestID,
-			"date":       req.Date,
+		log.Warn("Future date not allowed", map[string]interface{}{
+			"date": req.Date,
 		})
-		sendErrorResponse(w, h.logger, "Future date not allowed",
+		sendErrorResponse(w, log, "Future date not allowed",
 			"Transaction date cannot be in the future", http.StatusBadRequest, requestID)
 		return
 	}
 
 	// Call service
-	id, err := h.service.CreateTransaction(r.Context(), req.Description, date, req.Amount)
+	id, err := h.service.CreateCategorizedTransaction(r.Context(), req.Description, req.Category, date, req.Amount)
 	if err != nil {
 		// Handle different types of errors
 		switch {
-		case strings.Contains(err.Error(), "description must not exceed"):
-			h.logger.Warn("Description validation failed", map[string]interface{}{
-				"request_id": requestID,
-				"error":      err.Error(),
+		case errors.Is(err, entity.ErrDescriptionTooLong):
+			log.Warn("Description validation failed", map[string]interface{}{
+				"error": err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Description too long",
+			sendErrorResponse(w, log, "Description too long",
 				"Description must not exceed 50 characters", http.StatusBadRequest, requestID)
-		case strings.Contains(err.Error(), "amount must be"):
-			h.logger.Warn("Amount validation failed", map[string]interface{}{
-				"request_id": requestID,
-				"error":      err.Error(),
+		case errors.Is(err, entity.ErrCategoryTooLong):
+			log.Warn("Category validation failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			sendErrorResponse(w, log, "Category too long",
+				"Category must not exceed 50 characters", http.StatusBadRequest, requestID)
+		case errors.Is(err, entity.ErrInvalidAmount):
+			log.Warn("Amount validation failed", map[string]interface{}{
+				"error": err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Invalid amount",
+			sendErrorResponse(w, log, "Invalid amount",
 				"Amount must be a positive value", http.StatusBadRequest, requestID)
 		default:
-			h.logger.Error("Unexpected error in create transaction", map[string]interface{}{
-				"request_id": requestID,
-				"error":      err.Error(),
+			log.Error("Unexpected error in create transaction", map[string]interface{}{
+				"error": err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Internal server error",
+			sendErrorResponse(w, log, "Internal server error",
 				"An unexpected error occurred while creating the transaction",
 				http.StatusInternalServerError, requestID)
 		}
 		return
 	}
 
-	h.logger.Info("Transaction created successfully", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log.Info("Transaction created successfully", map[string]interface{}{
+		"id": id,
 	})
 
 	// Return success response
@@ -153,55 +176,70 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetRequestID(r.Context())
 
+	log := logger.ForContext(r.Context(), h.logger)
+
 	// Get ID from URL
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	h.logger.Info("Handling get transaction request", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log.Info("Handling get transaction request", map[string]interface{}{
+		"id": id,
 	})
 
 	// Call service
 	tx, err := h.service.GetTransaction(r.Context(), id)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			h.logger.Warn("Transaction not found", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"error":      err.Error(),
+		if errors.Is(err, repository.ErrTransactionExpired) {
+			log.Warn("Transaction expired", map[string]interface{}{
+				"id": id,
+			})
+			sendErrorResponse(w, log, "Transaction expired",
+				"The requested transaction has passed its retention period", http.StatusGone, requestID)
+		} else if errors.Is(err, repository.ErrTransactionNotFound) {
+			log.Warn("Transaction not found", map[string]interface{}{
+				"id":    id,
+				"error": err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Transaction not found",
+			sendErrorResponse(w, log, "Transaction not found",
 				"The requested transaction could not be found", http.StatusNotFound, requestID)
 		} else {
-			h.logger.Error("Unexpected error in get transaction", map[string]interface{}{
-				"request_id": requestID,
-				"id":         id,
-				"error":      err.Error(),
+			log.Error("Unexpected error in get transaction", map[string]interface{}{
+				"id":    id,
+				"error": err.Error(),
 			})
-			sendErrorResponse(w, h.logger, "Internal server error",
+			sendErrorResponse(w, log, "Internal server error",
 				"An unexpected error occurred while retrieving the transaction",
 				http.StatusInternalServerError, requestID)
 		}
 		return
 	}
 
-	h.logger.Info("Transaction retrieved successfully", map[string]interface{}{
-		"request_id": requestID,
-		"id":         id,
+	log.Info("Transaction retrieved successfully", map[string]interface{}{
+		"id": id,
 	})
 
-	// Create response
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTransactionResponse(tx))
+}
+
+// newTransactionResponse converts a transaction to its API representation
+func newTransactionResponse(tx *entity.Transaction) TransactionResponse {
 	resp := TransactionResponse{
 		ID:          tx.ID,
 		Description: tx.Description,
+		Category:    tx.Category,
 		Date:        tx.Date.Format("2006-01-02"),
 		Amount:      tx.Amount,
 	}
-
-	// Return response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	if tx.Held() {
+		resp.LegalHold = &LegalHoldResponse{
+			Reason:   tx.LegalHold.Reason,
+			PlacedBy: tx.LegalHold.PlacedBy,
+			PlacedAt: tx.LegalHold.PlacedAt.Format(time.RFC3339),
+		}
+	}
+	return resp
 }
 
 // RegisterRoutes registers the transaction handler routes
@@ -230,7 +268,6 @@ func sendErrorResponse(w http.ResponseWriter, log logger.Logger, message, descri
 	}
 
 	log.Debug("Sending error response", map[string]interface{}{
-		"request_id":  requestID,
 		"status_code": statusCode,
 		"message":     message,
 	})
@@ -0,0 +1,288 @@
+// Package handler internal/infrastructure/handler/webhook_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/gorilla/mux"
+)
+
+// Limits on the delivery history returned per request
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// WebhookHandler serves the endpoints that manage webhook subscriptions and the
+// admin endpoints that inspect and retry their deliveries
+type WebhookHandler struct {
+	service *service.WebhookService
+	logger  logger.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(service *service.WebhookService, log logger.Logger) *WebhookHandler {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &WebhookHandler{
+		service: service,
+		logger:  log,
+	}
+}
+
+// CreateSubscription registers a webhook endpoint. The response carries the
+// signing secret, which is not shown again.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+
+	var req WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, log, "Invalid request body",
+			"The request body could not be parsed as valid JSON", http.StatusBadRequest, requestID)
+		return
+	}
+
+	subscription := &entity.WebhookSubscription{URL: req.URL, Events: req.Events}
+	if err := subscription.Validate(); err != nil {
+		sendErrorResponse(w, log, "Invalid webhook subscription", err.Error(), http.StatusBadRequest, requestID)
+		return
+	}
+
+	if err := h.service.Subscribe(r.Context(), subscription); err != nil {
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while creating the webhook subscription", http.StatusInternalServerError, requestID)
+		return
+	}
+
+	resp := newWebhookSubscriptionResponse(subscription)
+	resp.Secret = subscription.Secret
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListSubscriptions returns the tenant's webhook subscriptions
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+
+	resp := WebhookSubscriptionListResponse{Subscriptions: []WebhookSubscriptionResponse{}}
+	err := h.service.ListSubscriptions(r.Context(), func(subscription *entity.WebhookSubscription) error {
+		resp.Subscriptions = append(resp.Subscriptions, newWebhookSubscriptionResponse(subscription))
+		return nil
+	})
+	if err != nil {
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while listing webhook subscriptions", http.StatusInternalServerError, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetSubscription returns one of the tenant's webhook subscriptions
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	id := mux.Vars(r)["id"]
+
+	subscription, err := h.service.GetSubscription(r.Context(), id)
+	if err != nil {
+		h.sendWebhookError(w, log, id, err, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(newWebhookSubscriptionResponse(subscription))
+}
+
+// DeleteSubscription removes one of the tenant's webhook subscriptions
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	id := mux.Vars(r)["id"]
+
+	if err := h.service.Unsubscribe(r.Context(), id); err != nil {
+		h.sendWebhookError(w, log, id, err, requestID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries returns a subscription's deliveries, newest first. The status
+// parameter filters them and limit caps how many are returned. History is kept
+// after the subscription is deleted.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	switch status {
+	case "", entity.DeliveryPending, entity.DeliveryDelivered, entity.DeliveryDead:
+	default:
+		sendErrorResponse(w, log, "Invalid status",
+			"Status must be one of pending, delivered or dead", http.StatusBadRequest, requestID)
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxDeliveryLimit {
+			sendErrorResponse(w, log, "Invalid limit",
+				"Limit must be between 1 and "+strconv.Itoa(maxDeliveryLimit), http.StatusBadRequest, requestID)
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.service.Deliveries(r.Context(), mux.Vars(r)["id"], status, limit)
+	if err != nil {
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while reading webhook deliveries", http.StatusInternalServerError, requestID)
+		return
+	}
+	sendDeliveries(w, deliveries)
+}
+
+// ListDeadLetters returns the tenant's dead-lettered deliveries, oldest first
+func (h *WebhookHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+
+	deliveries, err := h.service.DeadLetters(r.Context())
+	if err != nil {
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while reading dead-lettered webhooks", http.StatusInternalServerError, requestID)
+		return
+	}
+	sendDeliveries(w, deliveries)
+}
+
+// RetryDelivery moves a dead-lettered delivery back to the queue
+func (h *WebhookHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := logger.ForContext(r.Context(), h.logger)
+	id := mux.Vars(r)["id"]
+
+	delivery, err := h.service.RetryDelivery(r.Context(), id)
+	if err != nil {
+		h.sendWebhookError(w, log, id, err, requestID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(newWebhookDeliveryResponse(delivery))
+}
+
+// sendWebhookError maps an error from the webhook service to a response
+func (h *WebhookHandler) sendWebhookError(w http.ResponseWriter, log logger.Logger, id string, err error, requestID string) {
+	switch {
+	case errors.Is(err, repository.ErrSubscriptionNotFound):
+		sendErrorResponse(w, log, "Webhook subscription not found",
+			"The requested webhook subscription could not be found", http.StatusNotFound, requestID)
+	case errors.Is(err, repository.ErrDeliveryNotFound):
+		sendErrorResponse(w, log, "Webhook delivery not found",
+			"The requested webhook delivery could not be found", http.StatusNotFound, requestID)
+	case errors.Is(err, repository.ErrDeliveryNotDead):
+		sendErrorResponse(w, log, "Webhook delivery not dead-lettered",
+			"Only dead-lettered deliveries can be retried", http.StatusConflict, requestID)
+	default:
+		log.Error("Unexpected webhook error", map[string]interface{}{
+			"id":    id,
+			"error": err.Error(),
+		})
+		sendErrorResponse(w, log, "Internal server error",
+			"An unexpected error occurred while processing the webhook request",
+			http.StatusInternalServerError, requestID)
+	}
+}
+
+// sendDeliveries writes a list of deliveries
+func sendDeliveries(w http.ResponseWriter, deliveries []*entity.WebhookDelivery) {
+	resp := WebhookDeliveryListResponse{Deliveries: make([]WebhookDeliveryResponse, len(deliveries))}
+	for i, delivery := range deliveries {
+		resp.Deliveries[i] = newWebhookDeliveryResponse(delivery)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// newWebhookSubscriptionResponse describes a subscription without its secret
+func newWebhookSubscriptionResponse(subscription *entity.WebhookSubscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.Events,
+		CreatedAt: subscription.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// newWebhookDeliveryResponse describes a delivery
+func newWebhookDeliveryResponse(delivery *entity.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.Event.ID,
+		EventType:      delivery.Event.Type,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339Nano),
+		History:        make([]DeliveryAttemptResponse, len(delivery.History)),
+	}
+	if delivery.Status == entity.DeliveryPending && !delivery.NextAttemptAt.IsZero() {
+		resp.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339Nano)
+	}
+	for i, attempt := range delivery.History {
+		resp.History[i] = DeliveryAttemptResponse{
+			At:         attempt.At.Format(time.RFC3339Nano),
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMS: attempt.Duration.Milliseconds(),
+		}
+	}
+	return resp
+}
+
+// RegisterRoutes registers the webhook routes
+func (h *WebhookHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/webhooks", h.CreateSubscription).Methods("POST")
+	router.HandleFunc("/webhooks", h.ListSubscriptions).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", h.GetSubscription).Methods("GET")
+	router.HandleFunc("/webhooks/{id}", h.DeleteSubscription).Methods("DELETE")
+	router.HandleFunc("/admin/webhooks/dead-letters", h.ListDeadLetters).Methods("GET")
+	router.HandleFunc("/admin/webhooks/{id}/deliveries", h.ListDeliveries).Methods("GET")
+	router.HandleFunc("/admin/webhooks/deliveries/{id}/retry", h.RetryDelivery).Methods("POST")
+
+	h.logger.Info("Webhook routes registered", map[string]interface{}{
+		"routes": []string{
+			"POST /webhooks",
+			"GET /webhooks",
+			"GET /webhooks/{id}",
+			"DELETE /webhooks/{id}",
+			"GET /admin/webhooks/dead-letters",
+			"GET /admin/webhooks/{id}/deliveries",
+			"POST /admin/webhooks/deliveries/{id}/retry",
+		},
+	})
+}
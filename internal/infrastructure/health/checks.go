@@ -0,0 +1,82 @@
+// Package health internal/infrastructure/health/checks.go
+package health
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+// badgerProbePrefix prefixes the keys written and read back by the Badger check
+const badgerProbePrefix = "health:probe:"
+
+// badgerProbes numbers the Badger check's probes, so concurrent probes never share
+// a key
+var badgerProbes atomic.Uint64
+
+// BadgerCheck verifies that the database accepts a write and returns it on read.
+// Each probe writes its own key, which expires on its own so it never accumulates.
+func BadgerCheck(db *badger.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		now := time.Now().UnixNano()
+		key := []byte(fmt.Sprintf("%s%d-%d", badgerProbePrefix, now, badgerProbes.Add(1)))
+		value := []byte(strconv.FormatInt(now, 10))
+
+		err := db.Update(func(txn *badger.Txn) error {
+			return txn.SetEntry(badger.NewEntry(key, value).WithTTL(time.Minute))
+		})
+		if err != nil {
+			return fmt.Errorf("write probe failed: %w", err)
+		}
+
+		return db.View(func(txn *badger.Txn) error {
+			item, err := txn.Get(key)
+			if err != nil {
+				return fmt.Errorf("read probe failed: %w", err)
+			}
+			return item.Value(func(stored []byte) error {
+				if !bytes.Equal(stored, value) {
+					return fmt.Errorf("read probe returned a stale value")
+				}
+				return nil
+			})
+		})
+	})
+}
+
+// FreshnessCheck fails when lookups have been failing and the last successful one
+// is older than maxAge. A service that has not yet made a lookup is healthy.
+func FreshnessCheck(lastSuccess, lastFailure func() time.Time, maxAge time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		success, failure := lastSuccess(), lastFailure()
+		if failure.IsZero() || success.After(failure) {
+			return nil
+		}
+		if success.IsZero() {
+			return fmt.Errorf("no successful lookup since start, last failure at %s", failure.UTC().Format(time.RFC3339))
+		}
+		if age := time.Since(success); age > maxAge {
+			return fmt.Errorf("last successful lookup was %s ago", age.Truncate(time.Second))
+		}
+		return nil
+	})
+}
+
+// DiskCheck fails when the filesystem holding path has less than minFree bytes available
+func DiskCheck(path string, minFree uint64) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		free, err := freeBytes(path)
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%d bytes free, need at least %d", free, minFree)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+// Package health internal/infrastructure/health/disk_other.go
+package health
+
+import "math"
+
+// freeBytes is not implemented on this platform; the disk check always passes
+func freeBytes(path string) (uint64, error) {
+	return math.MaxUint64, nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+// Package health internal/infrastructure/health/disk_unix.go
+package health
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// freeBytes returns the space available to unprivileged users on the filesystem holding path
+func freeBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("failed to stat filesystem: %w", err)
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
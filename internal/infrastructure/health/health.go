@@ -0,0 +1,195 @@
+// Package health internal/infrastructure/health/health.go
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Component and overall statuses reported by a readiness check
+const (
+	// StatusOK means every check passed
+	StatusOK = "ok"
+	// StatusDegraded means a non-critical check failed
+	StatusDegraded = "degraded"
+	// StatusUnavailable means a critical check failed
+	StatusUnavailable = "unavailable"
+	// StatusFailed marks an individual failed check
+	StatusFailed = "failed"
+)
+
+// Checker verifies a single dependency
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx)
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// ComponentReport is the result of one check
+type ComponentReport struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the result of running every registered check
+type Report struct {
+	Status     string                     `json:"status"`
+	Timestamp  time.Time                  `json:"timestamp"`
+	Components map[string]ComponentReport `json:"components"`
+}
+
+// registration is a named check and whether its failure makes the service unready
+type registration struct {
+	name     string
+	critical bool
+	checker  Checker
+}
+
+// Registry holds the readiness checks
+type Registry struct {
+	checks  []registration
+	timeout time.Duration
+	mutex   sync.RWMutex
+}
+
+// NewRegistry creates a registry that bounds each check by timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a check. A failed critical check marks the service unavailable;
+// a failed non-critical check only marks it degraded.
+func (r *Registry) Register(name string, critical bool, checker Checker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.checks = append(r.checks, registration{name: name, critical: critical, checker: checker})
+}
+
+// Names returns the registered check names in sorted order
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.checks))
+	for _, c := range r.checks {
+		names = append(names, c.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes every check concurrently and aggregates the results
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mutex.RLock()
+	checks := append([]registration(nil), r.checks...)
+	r.mutex.RUnlock()
+
+	report := Report{
+		Status:     StatusOK,
+		Timestamp:  time.Now().UTC(),
+		Components: make(map[string]ComponentReport, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c registration) {
+			defer wg.Done()
+			result := r.runCheck(ctx, c)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Components[c.name] = result
+			if result.Status != StatusOK {
+				if c.critical {
+					report.Status = StatusUnavailable
+				} else if report.Status == StatusOK {
+					report.Status = StatusDegraded
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runCheck runs a single check with the registry timeout
+func (r *Registry) runCheck(ctx context.Context, c registration) ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errCh <- errors.New("check panicked")
+			}
+		}()
+		errCh <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := ComponentReport{
+		Status:    StatusOK,
+		Critical:  c.critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Cached wraps a checker so that its result is reused for ttl. It keeps expensive
+// probes of external services from running on every readiness request.
+func Cached(checker Checker, ttl time.Duration) Checker {
+	return &cachedChecker{checker: checker, ttl: ttl, now: time.Now}
+}
+
+// cachedChecker remembers the last result of a checker
+type cachedChecker struct {
+	checker Checker
+	ttl     time.Duration
+	now     func() time.Time
+	checked time.Time
+	err     error
+	mutex   sync.Mutex
+}
+
+// Check returns the cached result or runs the wrapped checker when it has expired
+func (c *cachedChecker) Check(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.checked.IsZero() && c.now().Sub(c.checked) < c.ttl {
+		return c.err
+	}
+
+	c.err = c.checker.Check(ctx)
+	c.checked = c.now()
+	return c.err
+}
@@ -0,0 +1,164 @@
+// internal/infrastructure/health/health_test.go
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func passing() Checker {
+	return CheckerFunc(func(ctx context.Context) error { return nil })
+}
+
+func failing(msg string) Checker {
+	return CheckerFunc(func(ctx context.Context) error { return errors.New(msg) })
+}
+
+func TestRegistryRun(t *testing.T) {
+	t.Run("All checks pass", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("badger", true, passing())
+		registry.Register("treasury", false, passing())
+
+		report := registry.Run(context.Background())
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Len(t, report.Components, 2)
+		assert.Equal(t, StatusOK, report.Components["badger"].Status)
+		assert.True(t, report.Components["badger"].Critical)
+	})
+
+	t.Run("Non-critical failure degrades", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("badger", true, passing())
+		registry.Register("treasury", false, failing("connection refused"))
+
+		report := registry.Run(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, StatusFailed, report.Components["treasury"].Status)
+		assert.Equal(t, "connection refused", report.Components["treasury"].Error)
+	})
+
+	t.Run("Critical failure makes the service unavailable", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("badger", true, failing("closed"))
+		registry.Register("treasury", false, failing("connection refused"))
+
+		report := registry.Run(context.Background())
+		assert.Equal(t, StatusUnavailable, report.Status)
+	})
+
+	t.Run("Slow checks time out", func(t *testing.T) {
+		registry := NewRegistry(20 * time.Millisecond)
+		registry.Register("slow", true, CheckerFunc(func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}))
+
+		start := time.Now()
+		report := registry.Run(context.Background())
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Contains(t, report.Components["slow"].Error, "deadline exceeded")
+	})
+
+	t.Run("Panicking checks fail", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register("broken", false, CheckerFunc(func(ctx context.Context) error {
+			panic("nil map")
+		}))
+
+		report := registry.Run(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status)
+	})
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	checker := Cached(CheckerFunc(func(ctx context.Context) error {
+		calls++
+		return errors.New("down")
+	}), time.Minute).(*cachedChecker)
+
+	now := time.Date(2025, 3, 29, 12, 0, 0, 0, time.UTC)
+	checker.now = func() time.Time { return now }
+
+	assert.Error(t, checker.Check(context.Background()))
+	assert.Error(t, checker.Check(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Minute)
+	assert.Error(t, checker.Check(context.Background()))
+	assert.Equal(t, 2, calls)
+}
+
+func TestFreshnessCheck(t *testing.T) {
+	now := time.Now()
+	at := func(tm time.Time) func() time.Time { return func() time.Time { return tm } }
+
+	tests := []struct {
+		name        string
+		success     time.Time
+		failure     time.Time
+		expectError bool
+	}{
+		{"No lookups yet", time.Time{}, time.Time{}, false},
+		{"Only successes", now, time.Time{}, false},
+		{"Recovered after failure", now, now.Add(-time.Minute), false},
+		{"Recent failure after recent success", now.Add(-time.Minute), now, false},
+		{"Failing since a stale success", now.Add(-time.Hour), now, true},
+		{"Never succeeded", time.Time{}, now, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := FreshnessCheck(at(tc.success), at(tc.failure), 15*time.Minute).Check(context.Background())
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBadgerCheck(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+
+	check := BadgerCheck(db)
+	assert.NoError(t, check.Check(context.Background()))
+
+	// Concurrent probes do not read each other's value
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- check.Check(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	require.NoError(t, db.Close())
+	assert.Error(t, check.Check(context.Background()))
+}
+
+func TestDiskCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, DiskCheck(dir, 1).Check(context.Background()))
+	assert.Error(t, DiskCheck(dir, ^uint64(0)).Check(context.Background()))
+	assert.Error(t, DiskCheck("/does/not/exist", 1).Check(context.Background()))
+}
@@ -0,0 +1,192 @@
+// Package logger internal/infrastructure/logger/async.go
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+)
+
+// OverflowPolicy decides what AsyncWriter does when its buffer is full
+type OverflowPolicy string
+
+const (
+	// OverflowBlock makes the caller wait for room in the buffer, so no entry is lost
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDrop discards the entry and counts it, so logging never adds latency
+	OverflowDrop OverflowPolicy = "drop"
+)
+
+// ErrWriterClosed is returned by writes to a closed AsyncWriter
+var ErrWriterClosed = errors.New("log writer closed")
+
+// AsyncWriterConfig configures an AsyncWriter
+type AsyncWriterConfig struct {
+	// BufferSize is the number of entries held before the overflow policy applies
+	BufferSize int
+	// Overflow is the policy applied when the buffer is full
+	Overflow OverflowPolicy
+	// Metrics records dropped entries; nil uses the default recorder
+	Metrics metrics.Metrics
+}
+
+// AsyncWriter moves log output off the calling goroutine. Each Write is one entry,
+// which is copied into a bounded buffer and written to the destination by a
+// single background goroutine in the order received.
+type AsyncWriter struct {
+	out      io.Writer
+	overflow OverflowPolicy
+	metrics  metrics.Metrics
+	entries  chan []byte
+	syncs    chan chan struct{}
+	done     chan struct{}
+	dropped  atomic.Uint64
+
+	// closeMutex guards closed and makes Close wait for in-progress writes
+	closeMutex sync.RWMutex
+	closed     bool
+}
+
+// NewAsyncWriter starts an AsyncWriter writing to out. Close must be called to
+// flush buffered entries and stop the background goroutine.
+func NewAsyncWriter(out io.Writer, config AsyncWriterConfig) *AsyncWriter {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1024
+	}
+	if config.Overflow == "" {
+		config.Overflow = OverflowBlock
+	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.GetDefaultMetrics()
+	}
+
+	w := &AsyncWriter{
+		out:      out,
+		overflow: config.Overflow,
+		metrics:  config.Metrics,
+		entries:  make(chan []byte, config.BufferSize),
+		syncs:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues one entry. With OverflowDrop a full buffer discards the entry and
+// Write still reports success, so callers do not treat it as an I/O failure.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.closeMutex.RLock()
+	defer w.closeMutex.RUnlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	// The caller may reuse p once Write returns
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	if w.overflow == OverflowDrop {
+		select {
+		case w.entries <- entry:
+		default:
+			w.dropped.Add(1)
+			w.metrics.IncCounter(metrics.LogEntriesDroppedTotal, map[string]string{"reason": "buffer_full"})
+		}
+		return len(p), nil
+	}
+
+	w.entries <- entry
+	return len(p), nil
+}
+
+// Sync blocks until every entry queued before the call has been written
+func (w *AsyncWriter) Sync() error {
+	w.closeMutex.RLock()
+	if w.closed {
+		w.closeMutex.RUnlock()
+		return nil
+	}
+
+	ack := make(chan struct{})
+	w.syncs <- ack
+	w.closeMutex.RUnlock()
+
+	<-ack
+	return nil
+}
+
+// Close flushes buffered entries and stops the background goroutine. It returns
+// ctx.Err() if the context ends first; entries still buffered are then lost.
+func (w *AsyncWriter) Close(ctx context.Context) error {
+	w.closeMutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.entries)
+	}
+	w.closeMutex.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dropped returns the number of entries discarded because the buffer was full
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// run writes queued entries until the buffer is closed and drained
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				return
+			}
+			w.write(entry)
+		case ack := <-w.syncs:
+			// Write everything queued so far, then release the caller
+			for drained := false; !drained; {
+				select {
+				case entry, ok := <-w.entries:
+					if !ok {
+						drained = true
+						break
+					}
+					w.write(entry)
+				default:
+					drained = true
+				}
+			}
+			close(ack)
+		}
+	}
+}
+
+// write sends one entry to the destination
+func (w *AsyncWriter) write(entry []byte) {
+	if _, err := w.out.Write(entry); err != nil {
+		// Not much we can do if writing fails, but print to stderr as a last resort
+		fmt.Fprintf(os.Stderr, "Failed to write log entry: %s\n", err)
+	}
+}
+
+// syncOutput flushes a writer that buffers output, such as AsyncWriter. It is
+// called before Fatal exits so the fatal entry is not lost.
+func syncOutput(w io.Writer) {
+	if s, ok := w.(interface{ Sync() error }); ok {
+		_ = s.Sync()
+	}
+}
@@ -0,0 +1,162 @@
+// internal/infrastructure/logger/async_test.go
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedBuffer is a bytes.Buffer safe for use from the writer goroutine
+type lockedBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+// gatedWriter blocks every write until the gate is opened
+type gatedWriter struct {
+	lockedBuffer
+	gate chan struct{}
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	return g.lockedBuffer.Write(p)
+}
+
+// countingMetrics records counter increments by name and reason label
+type countingMetrics struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+func (m *countingMetrics) IncCounter(name string, labels map[string]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[name+"/"+labels["reason"]]++
+}
+
+func (m *countingMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
+
+func (m *countingMetrics) count(key string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.counts[key]
+}
+
+func TestAsyncWriterFlushesInOrderOnClose(t *testing.T) {
+	var out lockedBuffer
+	w := NewAsyncWriter(&out, AsyncWriterConfig{BufferSize: 16, Overflow: OverflowBlock})
+	log := NewJSONLogger(w, InfoLevel)
+
+	for i := 0; i < 100; i++ {
+		log.Info(fmt.Sprintf("entry %d", i), nil)
+	}
+	require.NoError(t, w.Close(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 100)
+	for i, line := range lines {
+		assert.Contains(t, line, fmt.Sprintf(`"message":"entry %d"`, i))
+	}
+
+	// Writes after Close are refused rather than lost silently
+	_, err := w.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, ErrWriterClosed)
+}
+
+func TestAsyncWriterSync(t *testing.T) {
+	var out lockedBuffer
+	w := NewAsyncWriter(&out, AsyncWriterConfig{BufferSize: 16})
+	defer w.Close(context.Background())
+
+	for i := 0; i < 10; i++ {
+		w.Write([]byte("line\n"))
+	}
+	require.NoError(t, w.Sync())
+	assert.Equal(t, 10, strings.Count(out.String(), "line"))
+}
+
+func TestAsyncWriterDropPolicy(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	m := &countingMetrics{}
+	w := NewAsyncWriter(out, AsyncWriterConfig{BufferSize: 4, Overflow: OverflowDrop, Metrics: m})
+
+	// The writer goroutine holds one entry while blocked, the buffer holds four and
+	// the rest are dropped without blocking the caller
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		n, err := w.Write([]byte("entry\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 6, n)
+	}
+	assert.Less(t, time.Since(start), time.Second)
+
+	close(out.gate)
+	require.NoError(t, w.Close(context.Background()))
+
+	written := strings.Count(out.String(), "entry")
+	assert.GreaterOrEqual(t, written, 4)
+	assert.Equal(t, uint64(20-written), w.Dropped())
+	assert.Equal(t, int(w.Dropped()), m.count(metrics.LogEntriesDroppedTotal+"/buffer_full"))
+}
+
+func TestAsyncWriterBlockPolicy(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	w := NewAsyncWriter(out, AsyncWriterConfig{BufferSize: 2, Overflow: OverflowBlock})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			w.Write([]byte("entry\n"))
+		}
+	}()
+
+	// The producer waits for room instead of dropping
+	select {
+	case <-done:
+		t.Fatal("writes completed while the destination was blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(out.gate)
+	<-done
+	require.NoError(t, w.Close(context.Background()))
+	assert.Equal(t, 10, strings.Count(out.String(), "entry"))
+	assert.Zero(t, w.Dropped())
+}
+
+func TestAsyncWriterCloseTimeout(t *testing.T) {
+	out := &gatedWriter{gate: make(chan struct{})}
+	defer close(out.gate)
+	w := NewAsyncWriter(out, AsyncWriterConfig{BufferSize: 2})
+	w.Write([]byte("stuck\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, w.Close(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,56 @@
+// Package logger internal/infrastructure/logger/caller.go
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// packageDir is the directory of this package's source files
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callerPC returns the program counter of the first caller outside this package
+// and log/slog, so that wrapping loggers such as RedactingLogger do not change the
+// reported source location
+func callerPC() uintptr {
+	var pcs [16]uintptr
+	// Skip runtime.Callers and callerPC
+	n := runtime.Callers(2, pcs[:])
+
+	frames := runtime.CallersFrames(pcs[:n])
+	var first uintptr
+	for {
+		frame, more := frames.Next()
+		if first == 0 {
+			first = frame.PC
+		}
+		if !internalFrame(frame) {
+			return frame.PC
+		}
+		if !more {
+			return first
+		}
+	}
+}
+
+// internalFrame reports whether a frame belongs to the logging machinery
+func internalFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "log/slog.") {
+		return true
+	}
+	return filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// callerLocation returns the file and line of the first caller outside this package
+func callerLocation() (string, int, bool) {
+	pc := callerPC()
+	if pc == 0 {
+		return "", 0, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame.File, frame.Line, frame.File != ""
+}
@@ -0,0 +1,52 @@
+// Package logger internal/infrastructure/logger/context.go
+package logger
+
+import (
+	"context"
+)
+
+// fieldsKey is the context key for correlation fields
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the given correlation fields in
+// addition to any already present. Loggers obtained through FromContext or
+// ForContext include them in every entry.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	existing := ContextFields(ctx)
+	merged := make(map[string]interface{}, len(existing)+len(fields))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// ContextFields returns the correlation fields carried by ctx. The returned map
+// must not be modified.
+func ContextFields(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// ForContext returns log with the correlation fields of ctx attached
+func ForContext(ctx context.Context, log Logger) Logger {
+	if log == nil {
+		log = GetDefaultLogger()
+	}
+	return log.WithFields(ContextFields(ctx))
+}
+
+// FromContext returns the default logger with the correlation fields of ctx attached
+func FromContext(ctx context.Context) Logger {
+	return ForContext(ctx, GetDefaultLogger())
+}
@@ -0,0 +1,65 @@
+// internal/infrastructure/logger/context_test.go
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWithFields(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, ContextFields(ctx))
+
+	ctx = ContextWithFields(ctx, map[string]interface{}{"request_id": "req-1"})
+	child := ContextWithFields(ctx, map[string]interface{}{"tenant_id": "acme"})
+
+	// Fields accumulate without changing the parent context
+	assert.Equal(t, map[string]interface{}{"request_id": "req-1"}, ContextFields(ctx))
+	assert.Equal(t, map[string]interface{}{"request_id": "req-1", "tenant_id": "acme"}, ContextFields(child))
+
+	// Later values override earlier ones
+	override := ContextWithFields(child, map[string]interface{}{"tenant_id": "globex"})
+	assert.Equal(t, "globex", ContextFields(override)["tenant_id"])
+	assert.Equal(t, "acme", ContextFields(child)["tenant_id"])
+}
+
+func TestForContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewJSONLogger(&buf, InfoLevel)
+
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{
+		"request_id": "req-1",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	})
+
+	ForContext(ctx, log).Info("Transaction stored", map[string]interface{}{"id": "tx-1"})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+	assert.Equal(t, "tx-1", entry["id"])
+
+	// The caller location is the logging call, not the context helper
+	assert.Contains(t, entry["file"], "context_test.go")
+}
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	original := GetDefaultLogger()
+	SetDefaultLogger(NewJSONLogger(&buf, InfoLevel))
+	defer SetDefaultLogger(original)
+
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"request_id": "req-2"})
+	FromContext(ctx).Info("Hello", nil)
+
+	assert.Contains(t, buf.String(), `"request_id":"req-2"`)
+
+	// Without correlation fields the default logger is returned unchanged
+	assert.Equal(t, GetDefaultLogger(), FromContext(context.Background()))
+}
@@ -0,0 +1,226 @@
+// Package logger internal/infrastructure/logger/level.go
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentField is the field naming the part of the application an entry comes
+// from. Per-component level overrides are matched against it.
+const ComponentField = "component"
+
+// levelRank orders levels by severity
+var levelRank = map[Level]int{
+	DebugLevel: 0,
+	InfoLevel:  1,
+	WarnLevel:  2,
+	ErrorLevel: 3,
+	FatalLevel: 4,
+}
+
+// ParseLevel parses a level name, ignoring case
+func ParseLevel(s string) (Level, error) {
+	level := Level(strings.ToUpper(strings.TrimSpace(s)))
+	if _, ok := levelRank[level]; !ok {
+		return "", fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
+
+// ParseComponentLevels parses a comma-separated list of "component=level" pairs,
+// for example "api=debug,badger=warn"
+func ParseComponentLevels(spec string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		component, name, ok := strings.Cut(item, "=")
+		component = strings.TrimSpace(component)
+		if !ok || component == "" {
+			return nil, fmt.Errorf("component level %q must be in component=level form", item)
+		}
+
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("component level %q: %w", item, err)
+		}
+		levels[component] = level
+	}
+	return levels, nil
+}
+
+// FormatComponentLevels formats overrides in the form read by ParseComponentLevels
+func FormatComponentLevels(levels map[string]Level) string {
+	items := make([]string, 0, len(levels))
+	for component, level := range levels {
+		items = append(items, component+"="+string(level))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// levelState is an immutable snapshot of the configured levels
+type levelState struct {
+	level      Level
+	components map[string]Level
+}
+
+// LevelController holds the minimum level for the application and optional
+// per-component overrides. It can be changed at runtime while other goroutines
+// are logging: readers load an immutable snapshot and writers replace it.
+type LevelController struct {
+	state atomic.Pointer[levelState]
+	mutex sync.Mutex
+}
+
+// NewLevelController creates a controller with the given default level and no
+// component overrides
+func NewLevelController(level Level) *LevelController {
+	c := &LevelController{}
+	c.state.Store(&levelState{level: level, components: map[string]Level{}})
+	return c
+}
+
+// Level returns the default level
+func (c *LevelController) Level() Level {
+	return c.state.Load().level
+}
+
+// ComponentLevels returns a copy of the per-component overrides
+func (c *LevelController) ComponentLevels() map[string]Level {
+	components := c.state.Load().components
+	out := make(map[string]Level, len(components))
+	for k, v := range components {
+		out[k] = v
+	}
+	return out
+}
+
+// SetLevel changes the default level
+func (c *LevelController) SetLevel(level Level) {
+	c.update(func(s *levelState) { s.level = level })
+}
+
+// SetComponentLevel overrides the level for one component. An empty level removes
+// the override.
+func (c *LevelController) SetComponentLevel(component string, level Level) {
+	c.update(func(s *levelState) {
+		if level == "" {
+			delete(s.components, component)
+		} else {
+			s.components[component] = level
+		}
+	})
+}
+
+// Set replaces the default level and all component overrides at once
+func (c *LevelController) Set(level Level, components map[string]Level) {
+	c.update(func(s *levelState) {
+		s.level = level
+		s.components = make(map[string]Level, len(components))
+		for k, v := range components {
+			s.components[k] = v
+		}
+	})
+}
+
+// Enabled reports whether an entry at level from component should be written
+func (c *LevelController) Enabled(component string, level Level) bool {
+	state := c.state.Load()
+	min := state.level
+	if override, ok := state.components[component]; ok && component != "" {
+		min = override
+	}
+	return levelRank[level] >= levelRank[min]
+}
+
+// update applies fn to a copy of the current state and publishes it
+func (c *LevelController) update(fn func(*levelState)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	current := c.state.Load()
+	next := &levelState{
+		level:      current.level,
+		components: make(map[string]Level, len(current.components)),
+	}
+	for k, v := range current.components {
+		next.components[k] = v
+	}
+	fn(next)
+	c.state.Store(next)
+}
+
+// LeveledLogger filters entries using a LevelController. The component is taken
+// from the ComponentField of the logger's context fields, so a component logger is
+// obtained with log.WithField(logger.ComponentField, "api").
+type LeveledLogger struct {
+	next       Logger
+	controller *LevelController
+	component  string
+}
+
+// NewLeveledLogger wraps next so that entries are filtered by controller. The
+// wrapped logger should accept every level.
+func NewLeveledLogger(next Logger, controller *LevelController) *LeveledLogger {
+	return &LeveledLogger{next: next, controller: controller}
+}
+
+// WithField returns a new logger with the field added to the log context
+func (l *LeveledLogger) WithField(key string, value interface{}) Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a new logger with the fields added to the log context
+func (l *LeveledLogger) WithFields(fields map[string]interface{}) Logger {
+	if len(fields) == 0 {
+		return l
+	}
+
+	component := l.component
+	if name, ok := fields[ComponentField].(string); ok {
+		component = name
+	}
+	return &LeveledLogger{next: l.next.WithFields(fields), controller: l.controller, component: component}
+}
+
+// Debug logs a message at debug level
+func (l *LeveledLogger) Debug(msg string, fields map[string]interface{}) {
+	if l.controller.Enabled(l.component, DebugLevel) {
+		l.next.Debug(msg, fields)
+	}
+}
+
+// Info logs a message at info level
+func (l *LeveledLogger) Info(msg string, fields map[string]interface{}) {
+	if l.controller.Enabled(l.component, InfoLevel) {
+		l.next.Info(msg, fields)
+	}
+}
+
+// Warn logs a message at warn level
+func (l *LeveledLogger) Warn(msg string, fields map[string]interface{}) {
+	if l.controller.Enabled(l.component, WarnLevel) {
+		l.next.Warn(msg, fields)
+	}
+}
+
+// Error logs a message at error level
+func (l *LeveledLogger) Error(msg string, fields map[string]interface{}) {
+	if l.controller.Enabled(l.component, ErrorLevel) {
+		l.next.Error(msg, fields)
+	}
+}
+
+// Fatal logs a message at fatal level and then terminates the program. Fatal
+// entries are never filtered.
+func (l *LeveledLogger) Fatal(msg string, fields map[string]interface{}) {
+	l.next.Fatal(msg, fields)
+}
@@ -0,0 +1,128 @@
+// internal/infrastructure/logger/level_test.go
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel(" debug ")
+	require.NoError(t, err)
+	assert.Equal(t, DebugLevel, level)
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestParseComponentLevels(t *testing.T) {
+	levels, err := ParseComponentLevels("api=debug, badger=WARN,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Level{"api": DebugLevel, "badger": WarnLevel}, levels)
+	assert.Equal(t, "api=DEBUG,badger=WARN", FormatComponentLevels(levels))
+
+	_, err = ParseComponentLevels("api")
+	assert.Error(t, err)
+
+	_, err = ParseComponentLevels("api=loud")
+	assert.Error(t, err)
+}
+
+func TestLeveledLogger(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevelController(InfoLevel)
+	log := NewLeveledLogger(NewJSONLogger(&buf, DebugLevel), levels)
+	apiLog := log.WithField(ComponentField, "api")
+	dbLog := log.WithField(ComponentField, "db")
+
+	lines := func() int {
+		n := strings.Count(buf.String(), "\n")
+		buf.Reset()
+		return n
+	}
+
+	log.Debug("Filtered", nil)
+	log.Info("Written", nil)
+	assert.Equal(t, 1, lines())
+
+	// Raising the default level takes effect for existing loggers
+	levels.SetLevel(ErrorLevel)
+	log.Warn("Filtered", nil)
+	apiLog.Warn("Filtered", nil)
+	log.Error("Written", nil)
+	assert.Equal(t, 1, lines())
+
+	// A component override applies to that component only, including loggers
+	// derived from it with further fields
+	levels.SetComponentLevel("api", DebugLevel)
+	apiLog.Debug("Written", nil)
+	apiLog.WithField("attempt", 1).Debug("Written", nil)
+	dbLog.Debug("Filtered", nil)
+	log.Info("Filtered", nil)
+	assert.Equal(t, 2, lines())
+	assert.Equal(t, map[string]Level{"api": DebugLevel}, levels.ComponentLevels())
+
+	// Removing the override falls back to the default level
+	levels.SetComponentLevel("api", "")
+	apiLog.Debug("Filtered", nil)
+	assert.Equal(t, 0, lines())
+
+	// Set replaces everything at once
+	levels.Set(WarnLevel, map[string]Level{"db": DebugLevel})
+	dbLog.Debug("Written", nil)
+	apiLog.Info("Filtered", nil)
+	assert.Equal(t, 1, lines())
+	assert.Equal(t, WarnLevel, levels.Level())
+}
+
+func TestLevelControllerConcurrentUse(t *testing.T) {
+	var buf bytes.Buffer
+	var mutex sync.Mutex
+	levels := NewLevelController(InfoLevel)
+	log := NewLeveledLogger(NewJSONLogger(writerFunc(func(p []byte) (int, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return buf.Write(p)
+	}), DebugLevel), levels).WithField(ComponentField, "api")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				log.Debug("Concurrent", nil)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if (i+j)%2 == 0 {
+					levels.SetComponentLevel("api", DebugLevel)
+				} else {
+					levels.Set(InfoLevel, nil)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Every line written is a complete entry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line != "" {
+			assert.Contains(t, line, `"message":"Concurrent"`)
+		}
+	}
+}
+
+// writerFunc adapts a function to io.Writer
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
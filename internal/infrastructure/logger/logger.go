@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"runtime"
+	"sync"
 	"time"
 )
 
@@ -134,7 +134,8 @@ func (l *JSONLogger) Fatal(msg string, fields map[string]interface{}) {
 	if l.shouldLog(FatalLevel) {
 		l.log(FatalLevel, msg, fields)
 	}
-	os.Exit(1)
+	syncOutput(l.output)
+	exit(1)
 }
 
 // shouldLog determines if a message at the given level should be logged
@@ -159,7 +160,7 @@ func (l *JSONLogger) shouldLog(level Level) bool {
 // log outputs a log message with the given level, message, and fields
 func (l *JSONLogger) log(level Level, msg string, fields map[string]interface{}) {
 	// Get caller info
-	_, file, line, ok := runtime.Caller(2)
+	file, line, ok := callerLocation()
 	if !ok {
 		file = "unknown"
 		line = 0
@@ -204,9 +205,38 @@ func (l *JSONLogger) log(level Level, msg string, fields map[string]interface{})
 
 // Default logger instances
 var (
-	defaultLogger = NewJSONLogger(os.Stdout, InfoLevel)
+	defaultLogger Logger = NewJSONLogger(os.Stdout, InfoLevel)
 )
 
+// exitFunc is called by Fatal after the entry has been written
+var (
+	exitFunc  = os.Exit
+	exitMutex sync.RWMutex
+)
+
+// SetExitFunc replaces the function Fatal calls to terminate the program and
+// returns the previous one, so tests can observe Fatal without exiting. A nil
+// function restores os.Exit.
+func SetExitFunc(fn func(code int)) func(code int) {
+	if fn == nil {
+		fn = os.Exit
+	}
+
+	exitMutex.Lock()
+	defer exitMutex.Unlock()
+	previous := exitFunc
+	exitFunc = fn
+	return previous
+}
+
+// exit terminates the program through the configured exit function
+func exit(code int) {
+	exitMutex.RLock()
+	fn := exitFunc
+	exitMutex.RUnlock()
+	fn(code)
+}
+
 // GetDefaultLogger returns the default logger
 func GetDefaultLogger() Logger {
 	return defaultLogger
@@ -214,8 +244,8 @@ func GetDefaultLogger() Logger {
 
 // SetDefaultLogger sets the default logger
 func SetDefaultLogger(logger Logger) {
-	if l, ok := logger.(*JSONLogger); ok {
-		defaultLogger = l
+	if logger != nil {
+		defaultLogger = logger
 	}
 }
 
@@ -3,6 +3,7 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -110,3 +111,31 @@ func TestSetDefaultLogger(t *testing.T) {
 	// Reset to original
 	SetDefaultLogger(originalLogger)
 }
+
+func TestFatalCallsExitFunc(t *testing.T) {
+	var exitCode int
+	previous := SetExitFunc(func(code int) { exitCode = code })
+	defer SetExitFunc(previous)
+
+	var buf bytes.Buffer
+	NewJSONLogger(&buf, InfoLevel).Fatal("Fatal message", map[string]interface{}{"key": "value"})
+
+	assert.Equal(t, 1, exitCode)
+	var logEntry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &logEntry))
+	assert.Equal(t, "FATAL", logEntry["level"])
+	assert.Equal(t, "value", logEntry["key"])
+
+	// A buffered writer is flushed before the exit function runs
+	var out lockedBuffer
+	asyncWriter := NewAsyncWriter(&out, AsyncWriterConfig{BufferSize: 16})
+	defer asyncWriter.Close(context.Background())
+
+	exitCode = 0
+	SetExitFunc(func(code int) {
+		exitCode = code
+		assert.Contains(t, out.String(), "Buffered fatal")
+	})
+	NewSlogJSONLogger(asyncWriter, InfoLevel).Fatal("Buffered fatal", nil)
+	assert.Equal(t, 1, exitCode)
+}
@@ -0,0 +1,205 @@
+// Package logger internal/infrastructure/logger/redact.go
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RedactAction is what happens to the value of a sensitive field
+type RedactAction string
+
+const (
+	// RedactMask replaces the value with a fixed placeholder
+	RedactMask RedactAction = "mask"
+	// RedactHash replaces the value with a keyed hash, so equal values can still be
+	// correlated across entries without being readable
+	RedactHash RedactAction = "hash"
+	// RedactDrop removes the field from the entry
+	RedactDrop RedactAction = "drop"
+)
+
+// RedactedValue is the placeholder written for masked fields
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactionRules covers transaction descriptions, upstream error bodies and
+// credential-bearing headers. Descriptions are masked rather than hashed, as hashing
+// needs a key to be safe.
+const DefaultRedactionRules = "description:mask,body:mask,authorization:drop,proxy-authorization:drop,cookie:drop,set-cookie:drop,x-api-key:drop"
+
+// RedactionRules maps lower-cased field names to the action applied to them
+type RedactionRules map[string]RedactAction
+
+// ParseRedactionRules parses a comma-separated list of "field:action" pairs, for
+// example "description:hash,authorization:drop". Field names are case-insensitive.
+func ParseRedactionRules(spec string) (RedactionRules, error) {
+	rules := make(RedactionRules)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		field, action, ok := strings.Cut(item, ":")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || field == "" {
+			return nil, fmt.Errorf("redaction rule %q must be in field:action form", item)
+		}
+
+		switch a := RedactAction(strings.TrimSpace(action)); a {
+		case RedactMask, RedactHash, RedactDrop:
+			rules[field] = a
+		default:
+			return nil, fmt.Errorf("redaction rule %q: action must be mask, hash or drop", item)
+		}
+	}
+	return rules, nil
+}
+
+// Hashes reports whether any rule hashes its field
+func (r RedactionRules) Hashes() bool {
+	for _, action := range r {
+		if action == RedactHash {
+			return true
+		}
+	}
+	return false
+}
+
+// Redactor applies redaction rules to log fields
+type Redactor struct {
+	rules RedactionRules
+	key   []byte
+}
+
+// NewRedactor creates a redactor. The key is used for hashed fields; without one,
+// hashes of short values such as descriptions can be reversed by guessing.
+func NewRedactor(rules RedactionRules, key string) *Redactor {
+	return &Redactor{rules: rules, key: []byte(key)}
+}
+
+// Fields returns a copy of fields with the rules applied. Nested maps, including
+// http.Header values, are redacted by key as well. A field named with dots, as
+// produced by slog groups, matches a rule on its last segment.
+func (r *Redactor) Fields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 || len(r.rules) == 0 {
+		return fields
+	}
+
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if value, keep := r.field(k, v); keep {
+			out[k] = value
+		}
+	}
+	return out
+}
+
+// field applies the rule for key to value, reporting whether the field is kept
+func (r *Redactor) field(key string, value interface{}) (interface{}, bool) {
+	switch r.action(key) {
+	case RedactDrop:
+		return nil, false
+	case RedactMask:
+		return RedactedValue, true
+	case RedactHash:
+		return r.hash(value), true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return r.Fields(v), true
+	case map[string]string:
+		out := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			if redactedValue, keep := r.field(k, s); keep {
+				out[k] = redactedValue
+			}
+		}
+		return out, true
+	case http.Header:
+		out := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			if redactedValue, keep := r.field(k, s); keep {
+				out[k] = redactedValue
+			}
+		}
+		return out, true
+	default:
+		return value, true
+	}
+}
+
+// action returns the rule for a field name, if any
+func (r *Redactor) action(key string) RedactAction {
+	key = strings.ToLower(key)
+	if action, ok := r.rules[key]; ok {
+		return action
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return r.rules[key[i+1:]]
+	}
+	return ""
+}
+
+// hash returns a short keyed SHA-256 digest of the value's text form
+func (r *Redactor) hash(value interface{}) string {
+	mac := hmac.New(sha256.New, r.key)
+	fmt.Fprint(mac, value)
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// RedactingLogger applies a Redactor to every field before passing it on, so
+// sensitive values never reach the underlying logger's writer
+type RedactingLogger struct {
+	next     Logger
+	redactor *Redactor
+}
+
+// NewRedactingLogger wraps next so that fields given to it, including those added
+// with WithField and WithFields, are redacted first
+func NewRedactingLogger(next Logger, redactor *Redactor) *RedactingLogger {
+	return &RedactingLogger{next: next, redactor: redactor}
+}
+
+// WithField returns a new logger with the redacted field added to the log context
+func (l *RedactingLogger) WithField(key string, value interface{}) Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a new logger with the redacted fields added to the log context
+func (l *RedactingLogger) WithFields(fields map[string]interface{}) Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	return &RedactingLogger{next: l.next.WithFields(l.redactor.Fields(fields)), redactor: l.redactor}
+}
+
+// Debug logs a message at debug level
+func (l *RedactingLogger) Debug(msg string, fields map[string]interface{}) {
+	l.next.Debug(msg, l.redactor.Fields(fields))
+}
+
+// Info logs a message at info level
+func (l *RedactingLogger) Info(msg string, fields map[string]interface{}) {
+	l.next.Info(msg, l.redactor.Fields(fields))
+}
+
+// Warn logs a message at warn level
+func (l *RedactingLogger) Warn(msg string, fields map[string]interface{}) {
+	l.next.Warn(msg, l.redactor.Fields(fields))
+}
+
+// Error logs a message at error level
+func (l *RedactingLogger) Error(msg string, fields map[string]interface{}) {
+	l.next.Error(msg, l.redactor.Fields(fields))
+}
+
+// Fatal logs a message at fatal level and then terminates the program
+func (l *RedactingLogger) Fatal(msg string, fields map[string]interface{}) {
+	l.next.Fatal(msg, l.redactor.Fields(fields))
+}
@@ -0,0 +1,155 @@
+// internal/infrastructure/logger/redact_test.go
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	secretDescription = "Card 4111-1111-1111-1111 at Jane's Bakery"
+	secretBody        = `{"error":"upstream detail for account 99887766"}`
+	secretToken       = "Bearer eyJhbGciOiJSUzI1NiJ9.secret"
+)
+
+// newHashingRedactor returns a redactor with the default rules, except that
+// descriptions are hashed with key as a deployment with a redact key may choose
+func newHashingRedactor(t *testing.T, key string) *Redactor {
+	t.Helper()
+	rules, err := ParseRedactionRules(DefaultRedactionRules + ",description:hash")
+	require.NoError(t, err)
+	return NewRedactor(rules, key)
+}
+
+// assertNoSecrets fails if any sensitive value reached the writer
+func assertNoSecrets(t *testing.T, output string) {
+	t.Helper()
+	assert.NotContains(t, output, "4111")
+	assert.NotContains(t, output, "Bakery")
+	assert.NotContains(t, output, "99887766")
+	assert.NotContains(t, output, "eyJhbGciOiJSUzI1NiJ9")
+}
+
+func TestParseRedactionRules(t *testing.T) {
+	rules, err := ParseRedactionRules(" Description:hash , body:mask,authorization:drop,")
+	require.NoError(t, err)
+	assert.Equal(t, RedactionRules{
+		"description":   RedactHash,
+		"body":          RedactMask,
+		"authorization": RedactDrop,
+	}, rules)
+
+	rules, err = ParseRedactionRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	_, err = ParseRedactionRules("description")
+	assert.Error(t, err)
+
+	_, err = ParseRedactionRules("description:scramble")
+	assert.Error(t, err)
+}
+
+func TestDefaultRedactionRulesMaskDescriptions(t *testing.T) {
+	rules, err := ParseRedactionRules(DefaultRedactionRules)
+	require.NoError(t, err)
+	assert.False(t, rules.Hashes())
+
+	fields := NewRedactor(rules, "").Fields(map[string]interface{}{"description": secretDescription})
+	assert.Equal(t, RedactedValue, fields["description"])
+}
+
+func TestRedactorFields(t *testing.T) {
+	redactor := newHashingRedactor(t, "test-key")
+
+	header := http.Header{}
+	header.Set("Authorization", secretToken)
+	header.Set("Accept", "application/json")
+
+	fields := redactor.Fields(map[string]interface{}{
+		"description": secretDescription,
+		"body":        secretBody,
+		"amount":      12.5,
+		"headers":     header,
+		"request":     map[string]interface{}{"Description": secretDescription},
+		"http.cookie": "session=abc",
+	})
+
+	hashed, ok := fields["description"].(string)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(hashed, "sha256:"))
+	assert.Equal(t, RedactedValue, fields["body"])
+	assert.Equal(t, 12.5, fields["amount"])
+	assert.NotContains(t, fields, "http.cookie")
+
+	headers := fields["headers"].(map[string]interface{})
+	assert.NotContains(t, headers, "Authorization")
+	assert.Equal(t, []string{"application/json"}, headers["Accept"])
+
+	// Nested values hash the same way, so entries can still be correlated
+	nested := fields["request"].(map[string]interface{})
+	assert.Equal(t, hashed, nested["Description"])
+
+	// A different key gives a different hash
+	other := newHashingRedactor(t, "other-key").Fields(map[string]interface{}{"description": secretDescription})
+	assert.NotEqual(t, hashed, other["description"])
+}
+
+func TestRedactingLoggerNeverWritesSecrets(t *testing.T) {
+	backends := map[string]func(*bytes.Buffer) Logger{
+		"builtin": func(buf *bytes.Buffer) Logger { return NewJSONLogger(buf, DebugLevel) },
+		"slog":    func(buf *bytes.Buffer) Logger { return NewSlogJSONLogger(buf, DebugLevel) },
+		"text":    func(buf *bytes.Buffer) Logger { return NewSlogTextLogger(buf, DebugLevel) },
+	}
+
+	for name, newBackend := range backends {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewRedactingLogger(newBackend(&buf), newHashingRedactor(t, "test-key"))
+
+			// Fields passed per entry
+			log.Info("Creating transaction", map[string]interface{}{
+				"description": secretDescription,
+				"amount":      12.5,
+			})
+			log.Error("API returned error status", map[string]interface{}{
+				"status_code": 500,
+				"body":        secretBody,
+			})
+
+			// Fields attached to derived loggers and through the context
+			log.WithField("Authorization", secretToken).Debug("Derived", nil)
+			ctx := ContextWithFields(context.Background(), map[string]interface{}{"description": secretDescription})
+			ForContext(ctx, log).Warn("From context", nil)
+
+			// Records arriving through the slog bridge
+			slog.New(NewSlogHandler(log)).WithGroup("request").Info("Bridged",
+				"description", secretDescription, "authorization", secretToken)
+
+			output := buf.String()
+			assertNoSecrets(t, output)
+			assert.Equal(t, 5, strings.Count(output, "\n"))
+			assert.Contains(t, output, "sha256:")
+			assert.Contains(t, output, RedactedValue)
+		})
+	}
+}
+
+func TestRedactingLoggerReportsCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewRedactingLogger(NewJSONLogger(&buf, InfoLevel), newHashingRedactor(t, "test-key"))
+
+	log.Info("Caller", nil)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.True(t, strings.HasSuffix(entry["file"].(string), "redact_test.go"))
+}
@@ -0,0 +1,127 @@
+// Package logger internal/infrastructure/logger/sampling.go
+package logger
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+)
+
+// samplerBuckets is the number of message counters. Messages sharing a bucket are
+// sampled together, which only matters if the bucket is busy.
+const samplerBuckets = 4096
+
+// SamplingConfig configures a SamplingLogger. Within each Interval the first
+// Initial entries with a given message are written, then every Thereafter-th.
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	Interval   time.Duration
+	// Metrics records discarded entries; nil uses the default recorder
+	Metrics metrics.Metrics
+}
+
+// messageCounter counts entries for one bucket in the current interval
+type messageCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// SamplingLogger thins out repetitive Debug and Info entries, such as one
+// "Request received" line per request. Warnings, errors and fatal entries are
+// always written.
+type SamplingLogger struct {
+	next     Logger
+	counters *[samplerBuckets]messageCounter
+	config   SamplingConfig
+	now      func() time.Time
+}
+
+// NewSamplingLogger wraps next with message-based sampling
+func NewSamplingLogger(next Logger, config SamplingConfig) *SamplingLogger {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.GetDefaultMetrics()
+	}
+
+	return &SamplingLogger{
+		next:     next,
+		counters: new([samplerBuckets]messageCounter),
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// WithField returns a new logger with the field added to the log context. Derived
+// loggers share the sampling counters.
+func (l *SamplingLogger) WithField(key string, value interface{}) Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a new logger with the fields added to the log context
+func (l *SamplingLogger) WithFields(fields map[string]interface{}) Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	return &SamplingLogger{next: l.next.WithFields(fields), counters: l.counters, config: l.config, now: l.now}
+}
+
+// Debug logs a message at debug level, subject to sampling
+func (l *SamplingLogger) Debug(msg string, fields map[string]interface{}) {
+	if l.sample(msg) {
+		l.next.Debug(msg, fields)
+	}
+}
+
+// Info logs a message at info level, subject to sampling
+func (l *SamplingLogger) Info(msg string, fields map[string]interface{}) {
+	if l.sample(msg) {
+		l.next.Info(msg, fields)
+	}
+}
+
+// Warn logs a message at warn level
+func (l *SamplingLogger) Warn(msg string, fields map[string]interface{}) {
+	l.next.Warn(msg, fields)
+}
+
+// Error logs a message at error level
+func (l *SamplingLogger) Error(msg string, fields map[string]interface{}) {
+	l.next.Error(msg, fields)
+}
+
+// Fatal logs a message at fatal level and then terminates the program
+func (l *SamplingLogger) Fatal(msg string, fields map[string]interface{}) {
+	l.next.Fatal(msg, fields)
+}
+
+// sample reports whether an entry with msg should be written
+func (l *SamplingLogger) sample(msg string) bool {
+	h := fnv.New32a()
+	h.Write([]byte(msg))
+	counter := &l.counters[h.Sum32()%samplerBuckets]
+
+	now := l.now().UnixNano()
+	resetAt := counter.resetAt.Load()
+	if now > resetAt {
+		// Start a new interval; only one caller wins the reset
+		if counter.resetAt.CompareAndSwap(resetAt, now+int64(l.config.Interval)) {
+			counter.count.Store(0)
+		}
+	}
+
+	n := counter.count.Add(1)
+	if n <= uint64(l.config.Initial) {
+		return true
+	}
+	if l.config.Thereafter > 0 && (n-uint64(l.config.Initial))%uint64(l.config.Thereafter) == 0 {
+		return true
+	}
+
+	l.config.Metrics.IncCounter(metrics.LogEntriesDroppedTotal, map[string]string{"reason": "sampled"})
+	return false
+}
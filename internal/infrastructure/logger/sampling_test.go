@@ -0,0 +1,65 @@
+// internal/infrastructure/logger/sampling_test.go
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	var buf bytes.Buffer
+	m := &countingMetrics{}
+	now := time.Date(2025, 3, 29, 12, 0, 0, 0, time.UTC)
+
+	log := NewSamplingLogger(NewJSONLogger(&buf, DebugLevel), SamplingConfig{
+		Initial:    3,
+		Thereafter: 5,
+		Interval:   time.Second,
+		Metrics:    m,
+	})
+	log.now = func() time.Time { return now }
+	count := func(msg string) int {
+		return strings.Count(buf.String(), `"message":"`+msg+`"`)
+	}
+
+	// 3 initial entries, then every 5th of the remaining 20
+	for i := 0; i < 23; i++ {
+		log.Info("Request received", nil)
+	}
+	assert.Equal(t, 7, count("Request received"))
+	assert.Equal(t, 16, m.count(metrics.LogEntriesDroppedTotal+"/sampled"))
+
+	// Other messages have their own counts, also on derived loggers
+	log.WithField("component", "http").Debug("Request completed", nil)
+	assert.Equal(t, 1, count("Request completed"))
+
+	// Warnings and errors are never sampled
+	for i := 0; i < 20; i++ {
+		log.Warn("Request received", nil)
+		log.Error("Request received", nil)
+	}
+	assert.Equal(t, 47, count("Request received"))
+
+	// A new interval starts the count again
+	now = now.Add(2 * time.Second)
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		log.Info("Request received", nil)
+	}
+	assert.Equal(t, 3, count("Request received"))
+}
+
+func TestSamplingLoggerDropsAllAfterInitial(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSamplingLogger(NewJSONLogger(&buf, DebugLevel), SamplingConfig{Initial: 2, Metrics: metrics.NopMetrics{}})
+
+	for i := 0; i < 10; i++ {
+		log.Info("Noisy", nil)
+	}
+	assert.Equal(t, 2, strings.Count(buf.String(), "Noisy"))
+}
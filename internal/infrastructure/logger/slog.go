@@ -0,0 +1,177 @@
+// Package logger internal/infrastructure/logger/slog.go
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+)
+
+// LevelFatal is the slog level used for Fatal entries
+const LevelFatal = slog.Level(12)
+
+// SlogLogger is a Logger backed by a log/slog handler
+type SlogLogger struct {
+	handler slog.Handler
+	output  io.Writer
+}
+
+// NewSlogLogger creates a logger that writes through the given slog handler
+func NewSlogLogger(handler slog.Handler) *SlogLogger {
+	return &SlogLogger{handler: handler}
+}
+
+// NewSlogJSONLogger creates a slog logger that writes JSON lines using the same
+// timestamp, level and message keys as JSONLogger
+func NewSlogJSONLogger(output io.Writer, level Level) *SlogLogger {
+	if output == nil {
+		output = os.Stdout
+	}
+	l := NewSlogLogger(slog.NewJSONHandler(output, slogHandlerOptions(level)))
+	l.output = output
+	return l
+}
+
+// NewSlogTextLogger creates a slog logger that writes logfmt-style key=value lines
+func NewSlogTextLogger(output io.Writer, level Level) *SlogLogger {
+	if output == nil {
+		output = os.Stdout
+	}
+	l := NewSlogLogger(slog.NewTextHandler(output, slogHandlerOptions(level)))
+	l.output = output
+	return l
+}
+
+// slogHandlerOptions returns handler options that filter at level and name the
+// built-in keys consistently with JSONLogger
+func slogHandlerOptions(level Level) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		AddSource: true,
+		Level:     toSlogLevel(level),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				a.Key = "timestamp"
+				a.Value = slog.StringValue(a.Value.Time().UTC().Format(time.RFC3339Nano))
+			case slog.MessageKey:
+				a.Key = "message"
+			case slog.LevelKey:
+				a.Value = slog.StringValue(string(fromSlogLevel(a.Value.Any().(slog.Level))))
+			}
+			return a
+		},
+	}
+}
+
+// WithField returns a new logger with the field added to the log context
+func (l *SlogLogger) WithField(key string, value interface{}) Logger {
+	return &SlogLogger{handler: l.handler.WithAttrs([]slog.Attr{slog.Any(key, value)}), output: l.output}
+}
+
+// WithFields returns a new logger with the fields added to the log context
+func (l *SlogLogger) WithFields(fields map[string]interface{}) Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	return &SlogLogger{handler: l.handler.WithAttrs(toAttrs(fields)), output: l.output}
+}
+
+// Debug logs a message at debug level
+func (l *SlogLogger) Debug(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+// Info logs a message at info level
+func (l *SlogLogger) Info(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+// Warn logs a message at warn level
+func (l *SlogLogger) Warn(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+// Error logs a message at error level
+func (l *SlogLogger) Error(msg string, fields map[string]interface{}) {
+	l.log(slog.LevelError, msg, fields)
+}
+
+// Fatal logs a message at fatal level and then terminates the program
+func (l *SlogLogger) Fatal(msg string, fields map[string]interface{}) {
+	l.log(LevelFatal, msg, fields)
+	if l.output != nil {
+		syncOutput(l.output)
+	}
+	exit(1)
+}
+
+// Handler returns the underlying slog handler
+func (l *SlogLogger) Handler() slog.Handler {
+	return l.handler
+}
+
+// log sends a record to the handler, attributing it to the first caller outside
+// this package
+func (l *SlogLogger) log(level slog.Level, msg string, fields map[string]interface{}) {
+	ctx := context.Background()
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), level, msg, callerPC())
+	record.AddAttrs(toAttrs(fields)...)
+	_ = l.handler.Handle(ctx, record)
+}
+
+// toAttrs converts a fields map into attributes in a stable order
+func toAttrs(fields map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	return attrs
+}
+
+// toSlogLevel maps a Level onto the slog level scale
+func toSlogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return LevelFatal
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// fromSlogLevel maps a slog level onto the nearest Level at or below it
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level >= LevelFatal:
+		return FatalLevel
+	case level >= slog.LevelError:
+		return ErrorLevel
+	case level >= slog.LevelWarn:
+		return WarnLevel
+	case level >= slog.LevelInfo:
+		return InfoLevel
+	default:
+		return DebugLevel
+	}
+}
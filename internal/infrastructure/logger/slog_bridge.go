@@ -0,0 +1,97 @@
+// Package logger internal/infrastructure/logger/slog_bridge.go
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// slogBridge is a slog.Handler that forwards records to a Logger, so that code
+// logging through log/slog (or the standard log package once slog.SetDefault has
+// been called) ends up in the application's structured stream
+type slogBridge struct {
+	logger Logger
+	prefix string
+}
+
+// NewSlogHandler returns a slog handler that writes every record to log. Level
+// filtering is left to log. Groups are flattened into dotted field names and the
+// correlation fields of the record's context are included.
+func NewSlogHandler(log Logger) slog.Handler {
+	if log == nil {
+		log = GetDefaultLogger()
+	}
+	return &slogBridge{logger: log}
+}
+
+// Enabled reports true; the destination logger applies its own level
+func (b *slogBridge) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle converts the record into a fields map and logs it at the matching level
+func (b *slogBridge) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(map[string]interface{}, record.NumAttrs()+2)
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, b.prefix, a)
+		return true
+	})
+
+	// Report the slog call site rather than this bridge
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		fields["file"] = frame.File
+		fields["line"] = frame.Line
+	}
+
+	log := ForContext(ctx, b.logger)
+	switch fromSlogLevel(record.Level) {
+	case DebugLevel:
+		log.Debug(record.Message, fields)
+	case InfoLevel:
+		log.Info(record.Message, fields)
+	case WarnLevel:
+		log.Warn(record.Message, fields)
+	default:
+		// Fatal records are logged as errors; terminating is the caller's decision
+		log.Error(record.Message, fields)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler whose logger carries the attributes as fields
+func (b *slogBridge) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		addAttr(fields, b.prefix, a)
+	}
+	return &slogBridge{logger: b.logger.WithFields(fields), prefix: b.prefix}
+}
+
+// WithGroup returns a handler that prefixes later attribute keys with name
+func (b *slogBridge) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return b
+	}
+	return &slogBridge{logger: b.logger, prefix: b.prefix + name + "."}
+}
+
+// addAttr stores an attribute in fields, flattening groups into dotted keys
+func addAttr(fields map[string]interface{}, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range value.Group() {
+			addAttr(fields, groupPrefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[prefix+a.Key] = value.Any()
+}
@@ -0,0 +1,118 @@
+// internal/infrastructure/logger/slog_test.go
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), buf.String())
+	return entry
+}
+
+func TestSlogJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlogJSONLogger(&buf, DebugLevel)
+
+	log.Debug("Debug message", map[string]interface{}{"key1": "value1"})
+
+	entry := decodeLine(t, &buf)
+	assert.Equal(t, "DEBUG", entry["level"])
+	assert.Equal(t, "Debug message", entry["message"])
+	assert.Equal(t, "value1", entry["key1"])
+	assert.Contains(t, entry, "timestamp")
+
+	// The source is the caller of Debug, not the logger itself
+	source, ok := entry["source"].(map[string]interface{})
+	require.True(t, ok)
+	assert.True(t, strings.HasSuffix(source["file"].(string), "slog_test.go"))
+
+	// Fields added with WithField and WithFields are carried on every entry
+	buf.Reset()
+	log.WithField("component", "test").WithFields(map[string]interface{}{"a": 1}).Info("With fields", nil)
+
+	entry = decodeLine(t, &buf)
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "test", entry["component"])
+	assert.Equal(t, float64(1), entry["a"])
+}
+
+func TestSlogLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlogJSONLogger(&buf, WarnLevel)
+
+	log.Debug("Should not appear", nil)
+	log.Info("Should not appear", nil)
+	assert.Empty(t, buf.String())
+
+	log.Warn("Warning message", nil)
+	assert.Equal(t, "WARN", decodeLine(t, &buf)["level"])
+
+	buf.Reset()
+	log.Error("Error message", nil)
+	assert.Equal(t, "ERROR", decodeLine(t, &buf)["level"])
+
+	// Fatal exits, so check the level name through the handler directly
+	buf.Reset()
+	record := slog.NewRecord(time.Now(), LevelFatal, "Fatal message", 0)
+	require.NoError(t, log.Handler().Handle(context.Background(), record))
+	assert.Equal(t, "FATAL", decodeLine(t, &buf)["level"])
+}
+
+func TestSlogTextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlogTextLogger(&buf, InfoLevel)
+
+	log.Info("Text message", map[string]interface{}{"key": "value"})
+
+	line := buf.String()
+	assert.Contains(t, line, "level=INFO")
+	assert.Contains(t, line, `message="Text message"`)
+	assert.Contains(t, line, "key=value")
+	assert.Contains(t, line, "timestamp=")
+}
+
+func TestSlogHandlerBridge(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewSlogHandler(NewJSONLogger(&buf, InfoLevel)))
+
+	// Groups flatten into dotted keys and the slog call site is reported
+	log.WithGroup("http").With("method", "GET").Info("Bridged", "status", 200)
+
+	entry := decodeLine(t, &buf)
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "Bridged", entry["message"])
+	assert.Equal(t, "GET", entry["http.method"])
+	assert.Equal(t, float64(200), entry["http.status"])
+	assert.True(t, strings.HasSuffix(entry["file"].(string), "slog_test.go"))
+
+	// The destination logger's level still applies
+	buf.Reset()
+	log.Debug("Filtered")
+	assert.Empty(t, buf.String())
+
+	// Correlation fields on the context are included
+	buf.Reset()
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"request_id": "req-1"})
+	log.WarnContext(ctx, "With context")
+
+	entry = decodeLine(t, &buf)
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "req-1", entry["request_id"])
+
+	// Levels above error are logged as errors rather than terminating
+	buf.Reset()
+	log.Log(context.Background(), LevelFatal, "Fatal through slog")
+	assert.Equal(t, "ERROR", decodeLine(t, &buf)["level"])
+}
@@ -0,0 +1,89 @@
+// Package metrics internal/infrastructure/metrics/metrics.go
+package metrics
+
+import (
+	"time"
+)
+
+// Metric names recorded by the application
+const (
+	// HTTPRequestsTotal counts HTTP requests by route, method and status
+	HTTPRequestsTotal = "http_requests_total"
+	// HTTPRequestDuration observes HTTP request latency by route, method and status
+	HTTPRequestDuration = "http_request_duration_seconds"
+	// DBOperationDuration observes Badger operation latency by operation and outcome
+	DBOperationDuration = "db_operation_duration_seconds"
+	// TreasuryRequestsTotal counts Treasury API lookups by outcome
+	TreasuryRequestsTotal = "treasury_requests_total"
+	// TreasuryRequestDuration observes individual Treasury API call latency by outcome
+	TreasuryRequestDuration = "treasury_request_duration_seconds"
+	// TreasuryRetriesTotal counts Treasury API retry attempts
+	TreasuryRetriesTotal = "treasury_retries_total"
+	// CacheRequestsTotal counts exchange rate cache lookups by result (hit or miss)
+	CacheRequestsTotal = "exchange_rate_cache_requests_total"
+	// LogEntriesDroppedTotal counts log entries discarded by reason (buffer_full or sampled)
+	LogEntriesDroppedTotal = "log_entries_dropped_total"
+	// TransactionsPurgedTotal counts transactions deleted by the retention purge
+	TransactionsPurgedTotal = "transactions_purged_total"
+	// OutboxDeliveriesTotal counts attempts to deliver outbox events by sink and outcome
+	OutboxDeliveriesTotal = "outbox_deliveries_total"
+	// WebhookDeliveriesTotal counts attempts to deliver webhooks by outcome
+	WebhookDeliveriesTotal = "webhook_deliveries_total"
+)
+
+// descriptions holds the help text published for each metric
+var descriptions = map[string]string{
+	HTTPRequestsTotal:       "Total number of HTTP requests.",
+	HTTPRequestDuration:     "HTTP request latency in seconds.",
+	DBOperationDuration:     "Database operation latency in seconds.",
+	TreasuryRequestsTotal:   "Total number of Treasury API exchange rate lookups.",
+	TreasuryRequestDuration: "Treasury API call latency in seconds.",
+	TreasuryRetriesTotal:    "Total number of Treasury API retry attempts.",
+	CacheRequestsTotal:      "Total number of exchange rate cache lookups.",
+	LogEntriesDroppedTotal:  "Total number of log entries discarded before being written.",
+	TransactionsPurgedTotal: "Total number of expired transactions deleted by the retention purge.",
+	OutboxDeliveriesTotal:   "Total number of attempts to deliver outbox events to a sink.",
+	WebhookDeliveriesTotal:  "Total number of attempts to deliver webhooks to subscribers.",
+}
+
+// Metrics defines the interface for recording application metrics
+type Metrics interface {
+	// IncCounter increments the named counter
+	IncCounter(name string, labels map[string]string)
+	// ObserveDuration records a duration in the named histogram
+	ObserveDuration(name string, duration time.Duration, labels map[string]string)
+}
+
+// NopMetrics discards all measurements
+type NopMetrics struct{}
+
+// IncCounter does nothing
+func (NopMetrics) IncCounter(string, map[string]string) {}
+
+// ObserveDuration does nothing
+func (NopMetrics) ObserveDuration(string, time.Duration, map[string]string) {}
+
+// Default metrics instance
+var (
+	defaultMetrics Metrics = NopMetrics{}
+)
+
+// GetDefaultMetrics returns the default metrics recorder
+func GetDefaultMetrics() Metrics {
+	return defaultMetrics
+}
+
+// SetDefaultMetrics sets the default metrics recorder
+func SetDefaultMetrics(m Metrics) {
+	if m != nil {
+		defaultMetrics = m
+	}
+}
+
+// Outcome returns the outcome label for an operation result
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
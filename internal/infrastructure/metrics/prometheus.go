@@ -0,0 +1,149 @@
+// Package metrics internal/infrastructure/metrics/prometheus.go
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusMetrics records metrics in a Prometheus registry. Collectors are created
+// on first use; the label names of a metric are fixed by its first observation.
+type PrometheusMetrics struct {
+	namespace  string
+	registry   *prometheus.Registry
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+	mutex      sync.RWMutex
+}
+
+// NewPrometheusMetrics creates a metrics recorder with its own registry. Go runtime
+// and process collectors are registered alongside the application metrics.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return &PrometheusMetrics{
+		namespace:  namespace,
+		registry:   registry,
+		counters:   make(map[string]*prometheus.CounterVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+	}
+}
+
+// Handler returns an HTTP handler serving the registry in the Prometheus text format
+func (p *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+// Registry returns the underlying Prometheus registry
+func (p *PrometheusMetrics) Registry() *prometheus.Registry {
+	return p.registry
+}
+
+// IncCounter increments the named counter
+func (p *PrometheusMetrics) IncCounter(name string, labels map[string]string) {
+	counter := p.counterVec(name, labels)
+	if counter == nil {
+		return
+	}
+	if c, err := counter.GetMetricWith(labels); err == nil {
+		c.Inc()
+	}
+}
+
+// ObserveDuration records a duration, in seconds, in the named histogram
+func (p *PrometheusMetrics) ObserveDuration(name string, duration time.Duration, labels map[string]string) {
+	histogram := p.histogramVec(name, labels)
+	if histogram == nil {
+		return
+	}
+	if h, err := histogram.GetMetricWith(labels); err == nil {
+		h.Observe(duration.Seconds())
+	}
+}
+
+// counterVec returns the counter for name, registering it on first use
+func (p *PrometheusMetrics) counterVec(name string, labels map[string]string) *prometheus.CounterVec {
+	p.mutex.RLock()
+	counter, ok := p.counters[name]
+	p.mutex.RUnlock()
+	if ok {
+		return counter
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if counter, ok := p.counters[name]; ok {
+		return counter
+	}
+
+	counter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: p.namespace,
+		Name:      name,
+		Help:      help(name),
+	}, sortedKeys(labels))
+	if err := p.registry.Register(counter); err != nil {
+		return nil
+	}
+
+	p.counters[name] = counter
+	return counter
+}
+
+// histogramVec returns the histogram for name, registering it on first use
+func (p *PrometheusMetrics) histogramVec(name string, labels map[string]string) *prometheus.HistogramVec {
+	p.mutex.RLock()
+	histogram, ok := p.histograms[name]
+	p.mutex.RUnlock()
+	if ok {
+		return histogram
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if histogram, ok := p.histograms[name]; ok {
+		return histogram
+	}
+
+	histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: p.namespace,
+		Name:      name,
+		Help:      help(name),
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, sortedKeys(labels))
+	if err := p.registry.Register(histogram); err != nil {
+		return nil
+	}
+
+	p.histograms[name] = histogram
+	return histogram
+}
+
+// help returns the description for a metric, falling back to its name
+func help(name string) string {
+	if h, ok := descriptions[name]; ok {
+		return h
+	}
+	return name
+}
+
+// sortedKeys returns the label names in a stable order
+func sortedKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+// internal/infrastructure/metrics/prometheus_test.go
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *PrometheusMetrics) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	m := NewPrometheusMetrics("wex")
+
+	m.IncCounter(CacheRequestsTotal, map[string]string{"result": "hit"})
+	m.IncCounter(CacheRequestsTotal, map[string]string{"result": "hit"})
+	m.IncCounter(CacheRequestsTotal, map[string]string{"result": "miss"})
+	m.IncCounter(TreasuryRetriesTotal, nil)
+	m.ObserveDuration(DBOperationDuration, 3*time.Millisecond, map[string]string{
+		"operation": "store",
+		"outcome":   "success",
+	})
+
+	body := scrape(t, m)
+
+	assert.Contains(t, body, "# HELP wex_exchange_rate_cache_requests_total Total number of exchange rate cache lookups.")
+	assert.Contains(t, body, `wex_exchange_rate_cache_requests_total{result="hit"} 2`)
+	assert.Contains(t, body, `wex_exchange_rate_cache_requests_total{result="miss"} 1`)
+	assert.Contains(t, body, "wex_treasury_retries_total 1")
+	assert.Contains(t, body, `wex_db_operation_duration_seconds_bucket{operation="store",outcome="success",le="0.005"} 1`)
+	assert.Contains(t, body, `wex_db_operation_duration_seconds_count{operation="store",outcome="success"} 1`)
+
+	// Runtime collectors are registered alongside the application metrics
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestPrometheusMetricsInconsistentLabels(t *testing.T) {
+	m := NewPrometheusMetrics("wex")
+
+	m.IncCounter(HTTPRequestsTotal, map[string]string{"route": "/health", "method": "GET", "status": "200"})
+
+	// A mismatched label set is dropped rather than panicking
+	assert.NotPanics(t, func() {
+		m.IncCounter(HTTPRequestsTotal, map[string]string{"route": "/health"})
+	})
+
+	assert.Contains(t, scrape(t, m), `wex_http_requests_total{method="GET",route="/health",status="200"} 1`)
+}
+
+func TestDefaultMetrics(t *testing.T) {
+	assert.IsType(t, NopMetrics{}, GetDefaultMetrics())
+
+	m := NewPrometheusMetrics("wex")
+	SetDefaultMetrics(m)
+	defer SetDefaultMetrics(NopMetrics{})
+	assert.Same(t, m, GetDefaultMetrics())
+
+	// nil leaves the default unchanged
+	SetDefaultMetrics(nil)
+	assert.Same(t, m, GetDefaultMetrics())
+}
@@ -0,0 +1,118 @@
+// Package middleware internal/infrastructure/middleware/auth.go
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/gorilla/mux"
+)
+
+const (
+	principalKey contextKey = "principal"
+)
+
+// AuthMiddleware verifies bearer tokens and enforces the route policy. It must be
+// registered on a mux router so the matched route template is available.
+func AuthMiddleware(authenticator *auth.Authenticator, policy *auth.Policy, log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := GetRequestID(r.Context())
+			reqLog := logger.ForContext(r.Context(), log)
+			route := RouteName(r)
+
+			if policy.IsPublic(route) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), auth.BearerToken(r.Header.Get("Authorization")))
+			if err != nil {
+				reqLog.Warn("Authentication failed", map[string]interface{}{
+					"route": route,
+					"error": err.Error(),
+				})
+
+				challenge := `Bearer`
+				if !errors.Is(err, auth.ErrMissingToken) {
+					challenge = `Bearer error="invalid_token"`
+				}
+				w.Header().Set("WWW-Authenticate", challenge)
+				writeError(w, "Unauthorized", "A valid bearer token is required", http.StatusUnauthorized, requestID)
+				return
+			}
+
+			if !policy.Authorize(route, principal) {
+				reqLog.Warn("Authorization failed", map[string]interface{}{
+					"subject":        principal.Subject,
+					"route":          route,
+					"required_roles": policy.RequiredRoles(route),
+				})
+				writeError(w, "Forbidden", "The caller does not have the role required for this operation",
+					http.StatusForbidden, requestID)
+				return
+			}
+
+			reqLog.Debug("Request authenticated", map[string]interface{}{
+				"subject": principal.Subject,
+				"route":   route,
+			})
+
+			ctx := context.WithValue(r.Context(), principalKey, principal)
+			ctx = logger.ContextWithFields(ctx, map[string]interface{}{"subject": principal.Subject})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetPrincipal retrieves the authenticated principal from context
+func GetPrincipal(ctx context.Context) *auth.Principal {
+	principal, _ := ctx.Value(principalKey).(*auth.Principal)
+	return principal
+}
+
+// GetSubject retrieves the authenticated subject from context
+func GetSubject(ctx context.Context) string {
+	principal := GetPrincipal(ctx)
+	if principal == nil {
+		return "anonymous"
+	}
+	return principal.Subject
+}
+
+// WithPrincipal returns a copy of ctx carrying the given principal
+func WithPrincipal(ctx context.Context, principal *auth.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// RouteName identifies the matched route as "METHOD /path/template"
+func RouteName(r *http.Request) string {
+	path := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			path = tmpl
+		}
+	}
+	return strings.ToUpper(r.Method) + " " + path
+}
+
+// writeError writes an error body matching the handler package's ErrorResponse
+func writeError(w http.ResponseWriter, message, description string, statusCode int, requestID string) {
+	body := map[string]interface{}{
+		"error":       message,
+		"status":      statusCode,
+		"description": description,
+	}
+	if requestID != "" {
+		body["request_id"] = requestID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,108 @@
+// internal/infrastructure/middleware/auth_test.go
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := auth.NewStaticKeySet(map[string]crypto.PublicKey{"k1": &key.PublicKey})
+	authenticator := auth.NewAuthenticator(keys, auth.Config{Issuer: "test-issuer"})
+	policy := auth.NewPolicy().
+		Public("GET /health").
+		Require("POST /transactions", "transactions:write").
+		Require("GET /transactions/{id}", "transactions:read")
+
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+
+	router := mux.NewRouter()
+	router.Use(RequestIDMiddleware)
+	router.Use(AuthMiddleware(authenticator, policy, log))
+
+	echoSubject := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(GetSubject(r.Context())))
+	}
+	router.HandleFunc("/health", echoSubject).Methods("GET")
+	router.HandleFunc("/transactions", echoSubject).Methods("POST")
+	router.HandleFunc("/transactions/{id}", echoSubject).Methods("GET")
+
+	token := func(roles ...string) string {
+		claims := jwt.MapClaims{
+			"sub":   "user-42",
+			"iss":   "test-issuer",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": roles,
+		}
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		tok.Header["kid"] = "k1"
+		signed, err := tok.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{"Public route", "GET", "/health", "", http.StatusOK, "anonymous"},
+		{"Missing token", "GET", "/transactions/abc", "", http.StatusUnauthorized, ""},
+		{"Invalid token", "GET", "/transactions/abc", "not-a-jwt", http.StatusUnauthorized, ""},
+		{"Read allowed", "GET", "/transactions/abc", token("transactions:read"), http.StatusOK, "user-42"},
+		{"Write forbidden for reader", "POST", "/transactions", token("transactions:read"), http.StatusForbidden, ""},
+		{"Write allowed", "POST", "/transactions", token("transactions:write"), http.StatusOK, "user-42"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String())
+			}
+			if tc.status == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+			if tc.status >= http.StatusBadRequest {
+				var body map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, w.Header().Get("X-Request-ID"), body["request_id"])
+			}
+		})
+	}
+}
+
+func TestGetSubject(t *testing.T) {
+	assert.Equal(t, "anonymous", GetSubject(context.Background()))
+
+	ctx := WithPrincipal(context.Background(), &auth.Principal{Subject: "svc-ledger"})
+	assert.Equal(t, "svc-ledger", GetSubject(ctx))
+}
@@ -0,0 +1,47 @@
+// Package middleware internal/infrastructure/middleware/metrics.go
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/gorilla/mux"
+)
+
+// MetricsMiddleware records the count and latency of each request, labelled by
+// route template, method and status code. Requests that did not match a route are
+// grouped under a single label so that arbitrary paths cannot grow the series count.
+func MetricsMiddleware(m metrics.Metrics) func(http.Handler) http.Handler {
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapper := newResponseWrapper(w)
+
+			next.ServeHTTP(wrapper, r)
+
+			labels := map[string]string{
+				"route":  routeTemplate(r),
+				"method": r.Method,
+				"status": strconv.Itoa(wrapper.statusCode),
+			}
+			m.IncCounter(metrics.HTTPRequestsTotal, labels)
+			m.ObserveDuration(metrics.HTTPRequestDuration, time.Since(start), labels)
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return "unmatched"
+}
@@ -0,0 +1,46 @@
+// internal/infrastructure/middleware/metrics_test.go
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	m := metrics.NewPrometheusMetrics("test")
+
+	router := mux.NewRouter()
+	router.Use(MetricsMiddleware(m))
+	router.HandleFunc("/transactions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}).Methods("GET")
+
+	for _, id := range []string{"a", "b", "missing"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/transactions/"+id, nil))
+	}
+
+	// Scrape the registry
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	// Requests are grouped by route template rather than raw path
+	assert.Contains(t, string(body), `test_http_requests_total{method="GET",route="/transactions/{id}",status="200"} 2`)
+	assert.Contains(t, string(body), `test_http_requests_total{method="GET",route="/transactions/{id}",status="404"} 1`)
+	assert.Contains(t, string(body), `test_http_request_duration_seconds_count{method="GET",route="/transactions/{id}",status="200"} 2`)
+	assert.NotContains(t, string(body), `/transactions/a`)
+}
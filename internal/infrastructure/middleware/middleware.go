@@ -29,8 +29,8 @@ func RequestIDMiddleware(next http.Handler) http.Handler {
 		// Add ID to response headers
 		w.Header().Set("X-Request-ID", requestID)
 
-		// Add ID to context
-		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		// Add ID to context, both for GetRequestID and as a logger correlation field
+		ctx := WithRequestID(r.Context(), requestID)
 
 		// Call next handler with updated context
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -46,14 +46,10 @@ func LoggingMiddleware(log logger.Logger) func(http.Handler) http.Handler {
 			// Create a response wrapper to capture status code
 			wrapper := newResponseWrapper(w)
 
-			// Get request ID from context
-			requestID, ok := r.Context().Value(requestIDKey).(string)
-			if !ok || requestID == "" {
-				requestID = "unknown"
-			}
+			// The request-scoped logger carries the request and trace IDs
+			reqLog := logger.ForContext(r.Context(), log)
 
-			log.Info("Request received", map[string]interface{}{
-				"request_id":     requestID,
+			reqLog.Info("Request received", map[string]interface{}{
 				"method":         r.Method,
 				"path":           r.URL.Path,
 				"query":          r.URL.RawQuery,
@@ -68,8 +64,7 @@ func LoggingMiddleware(log logger.Logger) func(http.Handler) http.Handler {
 
 			// Log response
 			duration := time.Since(startTime)
-			log.Info("Response sent", map[string]interface{}{
-				"request_id":     requestID,
+			reqLog.Info("Response sent", map[string]interface{}{
 				"method":         r.Method,
 				"path":           r.URL.Path,
 				"status":         wrapper.statusCode,
@@ -90,6 +85,13 @@ func GetRequestID(ctx context.Context) string {
 	return requestID
 }
 
+// WithRequestID returns a copy of ctx carrying the given request ID, which is also
+// added to the context's logger fields
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey, requestID)
+	return logger.ContextWithFields(ctx, map[string]interface{}{"request_id": requestID})
+}
+
 // responseWrapper wraps http.ResponseWriter to capture the status code
 type responseWrapper struct {
 	http.ResponseWriter
@@ -117,3 +119,22 @@ func (rw *responseWrapper) Write(b []byte) (int, error) {
 	rw.contentLength += int64(n)
 	return n, err
 }
+
+// Unwrap returns the wrapped writer, so http.ResponseController can reach its
+// Flush and deadline methods
+func (rw *responseWrapper) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// MaxBodyBytesMiddleware limits the size of request bodies. Reads beyond the limit
+// fail with *http.MaxBytesError.
+func MaxBodyBytesMiddleware(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil && limit > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -4,12 +4,16 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRequestIDMiddleware(t *testing.T) {
@@ -88,3 +92,45 @@ func TestMiddlewareChain(t *testing.T) {
 	logs := buf.String()
 	assert.Contains(t, logs, "test-id-123", "Request ID should be in logs")
 }
+
+func TestRequestIDReachesDownstreamLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewJSONLogger(&buf, logger.InfoLevel)
+
+	// A downstream component logs through its own logger without passing the ID by hand
+	finalHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.ForContext(r.Context(), log).Info("Handled", nil)
+	})
+
+	chain := RequestIDMiddleware(LoggingMiddleware(log)(finalHandler))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-ID", "test-id-456")
+	chain.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		assert.Contains(t, line, `"request_id":"test-id-456"`)
+	}
+}
+
+func TestMaxBodyBytesMiddleware(t *testing.T) {
+	handler := MaxBodyBytesMiddleware(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader("small")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader("this body is too large")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -0,0 +1,109 @@
+// Package middleware internal/infrastructure/middleware/ratelimit.go
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/ratelimit"
+)
+
+// RateLimitConfig holds the limits applied to each class of route
+type RateLimitConfig struct {
+	Write   ratelimit.Limit
+	Read    ratelimit.Limit
+	Convert ratelimit.Limit
+}
+
+// DefaultRateLimitConfig returns the limits used when none are configured
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Write:   ratelimit.Limit{Rate: 5, Burst: 10},
+		Read:    ratelimit.Limit{Rate: 20, Burst: 40},
+		Convert: ratelimit.Limit{Rate: 2, Burst: 5},
+	}
+}
+
+// RateLimitMiddleware limits requests per client using token buckets. Clients are
+// identified by the authenticated subject when there is one, otherwise by remote
+// IP, so it must run after AuthMiddleware. Conversion routes have their own limit
+// because each request can fan out into Treasury API calls.
+func RateLimitMiddleware(store ratelimit.Store, config RateLimitConfig, log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := GetRequestID(r.Context())
+			reqLog := logger.ForContext(r.Context(), log)
+
+			class, limit := classifyRoute(RouteName(r), config)
+			client := clientKey(r)
+
+			decision, err := store.Allow(r.Context(), class+":"+client, limit)
+			if err != nil {
+				// Fail open so a broken limiter store does not take the API down
+				reqLog.Error("Rate limiter unavailable", map[string]interface{}{
+					"error": err.Error(),
+				})
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+
+			if !decision.Allowed {
+				reqLog.Warn("Rate limit exceeded", map[string]interface{}{
+					"route_class": class,
+					"client":      client,
+					"retry_after": ceilSeconds(decision.RetryAfter),
+				})
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+				writeError(w, "Too many requests", "Rate limit exceeded. Please retry later.",
+					http.StatusTooManyRequests, requestID)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// classifyRoute picks the limit for a route: conversions, writes or reads
+func classifyRoute(route string, config RateLimitConfig) (string, ratelimit.Limit) {
+	method, path, _ := strings.Cut(route, " ")
+
+	switch {
+	case strings.HasSuffix(path, "/convert"):
+		return "convert", config.Convert
+	case method == http.MethodPost || method == http.MethodPut ||
+		method == http.MethodPatch || method == http.MethodDelete:
+		return "write", config.Write
+	default:
+		return "read", config.Read
+	}
+}
+
+// clientKey identifies the caller by its authenticated subject or by its remote
+// IP. Unauthenticated headers are ignored, as a caller could vary them to get a
+// fresh bucket on every request.
+func clientKey(r *http.Request) string {
+	if principal := GetPrincipal(r.Context()); principal != nil && principal.Subject != "" {
+		return "sub:" + principal.Subject
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,108 @@
+// internal/infrastructure/middleware/ratelimit_test.go
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/ratelimit"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore is a rate limit store that always returns an error
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("store unavailable")
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	config := RateLimitConfig{
+		Write:   ratelimit.Limit{Rate: 0.001, Burst: 1},
+		Read:    ratelimit.Limit{Rate: 0.001, Burst: 2},
+		Convert: ratelimit.Limit{Rate: 0.001, Burst: 1},
+	}
+
+	newRouter := func(store ratelimit.Store) *mux.Router {
+		router := mux.NewRouter()
+		router.Use(RateLimitMiddleware(store, config, log))
+		ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+		router.HandleFunc("/transactions", ok).Methods("POST")
+		router.HandleFunc("/transactions/{id}", ok).Methods("GET")
+		router.HandleFunc("/transactions/{id}/convert", ok).Methods("GET")
+		return router
+	}
+
+	do := func(router http.Handler, method, path, remoteAddr, subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+		if subject != "" {
+			req = req.WithContext(WithPrincipal(req.Context(), &auth.Principal{Subject: subject}))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Limits per route class", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore())
+
+		w := do(router, "GET", "/transactions/a/convert", "10.0.0.1:1234", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+		w = do(router, "GET", "/transactions/b/convert", "10.0.0.1:5678", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		assert.NotEmpty(t, w.Header().Get("RateLimit-Reset"))
+
+		// Reads have a separate bucket
+		w = do(router, "GET", "/transactions/a", "10.0.0.1:1234", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+
+		// Writes have a separate bucket
+		assert.Equal(t, http.StatusOK, do(router, "POST", "/transactions", "10.0.0.1:1234", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, do(router, "POST", "/transactions", "10.0.0.1:1234", "").Code)
+	})
+
+	t.Run("Clients are limited independently", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore())
+
+		assert.Equal(t, http.StatusOK, do(router, "POST", "/transactions", "10.0.0.1:1", "").Code)
+		assert.Equal(t, http.StatusOK, do(router, "POST", "/transactions", "10.0.0.2:1", "").Code)
+		assert.Equal(t, http.StatusOK, do(router, "POST", "/transactions", "10.0.0.1:1", "svc-a").Code)
+		assert.Equal(t, http.StatusOK, do(router, "POST", "/transactions", "10.0.0.1:1", "svc-b").Code)
+		assert.Equal(t, http.StatusTooManyRequests, do(router, "POST", "/transactions", "10.0.0.9:1", "svc-a").Code)
+	})
+
+	t.Run("Unauthenticated API keys do not get their own bucket", func(t *testing.T) {
+		router := newRouter(ratelimit.NewMemoryStore())
+
+		for i, apiKey := range []string{"random-1", "random-2"} {
+			req := httptest.NewRequest("POST", "/transactions", nil)
+			req.RemoteAddr = "10.0.0.1:1"
+			req.Header.Set("X-API-Key", apiKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if i == 0 {
+				assert.Equal(t, http.StatusOK, w.Code)
+			} else {
+				assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			}
+		}
+	})
+
+	t.Run("Fails open when the store errors", func(t *testing.T) {
+		router := newRouter(failingStore{})
+		assert.Equal(t, http.StatusOK, do(router, "POST", "/transactions", "10.0.0.1:1", "").Code)
+	})
+}
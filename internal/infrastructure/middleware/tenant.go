@@ -0,0 +1,116 @@
+// Package middleware internal/infrastructure/middleware/tenant.go
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+)
+
+// TenantHeader is the request header used to select a tenant when no token claim is present
+const TenantHeader = "X-Tenant-ID"
+
+var (
+	// ErrTenantMismatch is returned when the requested tenant contradicts the
+	// caller's token
+	ErrTenantMismatch = errors.New("requested tenant does not match the caller's tenant")
+
+	// ErrMissingTenant is returned when a multi-tenant deployment is called without
+	// a tenant
+	ErrMissingTenant = errors.New("tenant is required")
+
+	// ErrMissingTenantClaim is returned when a multi-tenant deployment is called
+	// with a token that carries no tenant claim
+	ErrMissingTenantClaim = errors.New("token has no tenant claim")
+)
+
+// ResolveTenant returns the configuration of the tenant a request acts for. The
+// tenant is taken from the principal's claim when there is one. The requested
+// tenant is only used for unauthenticated calls, and the default tenant in
+// single-tenant deployments. A requested tenant that contradicts the claim is
+// rejected with ErrTenantMismatch, and a principal without a claim is rejected
+// with ErrMissingTenantClaim in multi-tenant deployments, so a caller cannot pick
+// another tenant's records by its header.
+func ResolveTenant(registry *tenant.Registry, claim string, principal *auth.Principal, requested string) (tenant.Config, error) {
+	if claim == "" {
+		claim = "tenant"
+	}
+
+	tenantID := requested
+	if principal != nil {
+		if claimTenant, ok := principal.Claims[claim].(string); ok && claimTenant != "" {
+			if requested != "" && requested != claimTenant {
+				return tenant.Config{}, ErrTenantMismatch
+			}
+			tenantID = claimTenant
+		} else if registry.MultiTenant() {
+			return tenant.Config{}, ErrMissingTenantClaim
+		}
+	}
+
+	if tenantID == "" {
+		if registry.MultiTenant() {
+			return tenant.Config{}, ErrMissingTenant
+		}
+		tenantID = tenant.DefaultID
+	}
+
+	return registry.Resolve(tenantID)
+}
+
+// TenantMiddleware resolves the tenant for each request and stores its configuration
+// in the context. The tenant is taken from the principal's claim when authentication
+// is enabled, and from the X-Tenant-ID header otherwise. A header that contradicts the
+// token claim, or a token without a claim, is rejected. It must run after
+// AuthMiddleware.
+func TenantMiddleware(registry *tenant.Registry, claim string, log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := GetRequestID(r.Context())
+			reqLog := logger.ForContext(r.Context(), log)
+			headerTenant := r.Header.Get(TenantHeader)
+
+			config, err := ResolveTenant(registry, claim, GetPrincipal(r.Context()), headerTenant)
+			switch {
+			case errors.Is(err, ErrTenantMismatch):
+				reqLog.Warn("Tenant header does not match token", map[string]interface{}{
+					"header_tenant": headerTenant,
+					"subject":       GetSubject(r.Context()),
+				})
+				writeError(w, "Forbidden", "The requested tenant does not match the caller's tenant",
+					http.StatusForbidden, requestID)
+				return
+			case errors.Is(err, ErrMissingTenantClaim):
+				reqLog.Warn("Token has no tenant claim", map[string]interface{}{
+					"header_tenant": headerTenant,
+					"subject":       GetSubject(r.Context()),
+				})
+				writeError(w, "Forbidden", "The caller's token does not grant access to a tenant",
+					http.StatusForbidden, requestID)
+				return
+			case errors.Is(err, ErrMissingTenant):
+				reqLog.Warn("Missing tenant", map[string]interface{}{})
+				writeError(w, "Missing tenant", "The "+TenantHeader+" header is required",
+					http.StatusBadRequest, requestID)
+				return
+			case err != nil:
+				reqLog.Warn("Tenant rejected", map[string]interface{}{
+					"header_tenant": headerTenant,
+					"error":         err.Error(),
+				})
+				status := http.StatusForbidden
+				if errors.Is(err, tenant.ErrInvalidID) {
+					status = http.StatusBadRequest
+				}
+				writeError(w, "Invalid tenant", "The requested tenant is not available", status, requestID)
+				return
+			}
+
+			ctx := logger.ContextWithFields(tenant.WithConfig(r.Context(), config), map[string]interface{}{"tenant_id": config.ID})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// internal/infrastructure/middleware/tenant_test.go
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+
+	echoTenant := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(tenant.IDFromContext(r.Context())))
+	})
+
+	t.Run("Single-tenant mode uses default tenant", func(t *testing.T) {
+		registry, err := tenant.NewRegistry()
+		require.NoError(t, err)
+		handler := TenantMiddleware(registry, "", log)(echoTenant)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/transactions/abc", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tenant.DefaultID, w.Body.String())
+	})
+
+	registry, err := tenant.NewRegistry(tenant.Config{ID: "acme"}, tenant.Config{ID: "globex"})
+	require.NoError(t, err)
+	handler := TenantMiddleware(registry, "tenant", log)(echoTenant)
+
+	tests := []struct {
+		name        string
+		header      string
+		claimTenant string
+		noClaim     bool
+		status      int
+		body        string
+	}{
+		{"Header selects tenant", "acme", "", false, http.StatusOK, "acme"},
+		{"Missing tenant", "", "", false, http.StatusBadRequest, ""},
+		{"Unknown tenant", "initech", "", false, http.StatusForbidden, ""},
+		{"Malformed tenant", "acme:tx", "", false, http.StatusBadRequest, ""},
+		{"Claim selects tenant", "", "globex", false, http.StatusOK, "globex"},
+		{"Header matching claim", "globex", "globex", false, http.StatusOK, "globex"},
+		{"Header contradicting claim", "acme", "globex", false, http.StatusForbidden, ""},
+		{"Token without claim", "", "", true, http.StatusForbidden, ""},
+		{"Token without claim selecting a tenant", "globex", "", true, http.StatusForbidden, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/transactions/abc", nil)
+			if tc.header != "" {
+				req.Header.Set(TenantHeader, tc.header)
+			}
+			if tc.claimTenant != "" || tc.noClaim {
+				principal := &auth.Principal{Subject: "user-1", Claims: map[string]interface{}{}}
+				if tc.claimTenant != "" {
+					principal.Claims["tenant"] = tc.claimTenant
+				}
+				req = req.WithContext(WithPrincipal(req.Context(), principal))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
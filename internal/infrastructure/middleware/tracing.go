@@ -0,0 +1,46 @@
+// Package middleware internal/infrastructure/middleware/tracing.go
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for each request. An inbound W3C
+// traceparent header makes the span a child of the caller's trace. The span is
+// named after the matched route template, so it must run after route matching.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Start(ctx, RouteName(r),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", routeTemplate(r)),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("request_id", GetRequestID(r.Context())),
+			),
+		)
+		defer span.End()
+
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			ctx = logger.ContextWithFields(ctx, map[string]interface{}{"trace_id": traceID})
+		}
+
+		wrapper := newResponseWrapper(w)
+		next.ServeHTTP(wrapper, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", wrapper.statusCode))
+		if wrapper.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(wrapper.statusCode))
+		}
+	})
+}
@@ -0,0 +1,193 @@
+// Package outbox internal/infrastructure/outbox/relay.go
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+)
+
+// Sink receives domain events from the relay. Publish must not return until the
+// event is durably accepted; an error means it will be offered again.
+type Sink interface {
+	// Name identifies the sink in delivery state, logs and metrics
+	Name() string
+	// Publish delivers one event
+	Publish(ctx context.Context, event *entity.DomainEvent) error
+}
+
+// RelayConfig controls how often the outbox is read and how failed deliveries
+// are retried
+type RelayConfig struct {
+	// PollInterval is the time between reads of the outbox
+	PollInterval time.Duration
+	// BatchSize is the most events delivered per read
+	BatchSize int
+	// RetryInitial is the delay before the first retry; each further retry
+	// doubles it, up to RetryMax
+	RetryInitial time.Duration
+	RetryMax     time.Duration
+}
+
+// DefaultRelayConfig returns the default relay settings
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		RetryInitial: time.Second,
+		RetryMax:     5 * time.Minute,
+	}
+}
+
+// Relay delivers the events in the outbox to every sink at least once. An event
+// stays in the outbox until each sink has accepted it; sinks that already have are
+// not offered it again. Failed deliveries are retried with exponential backoff
+// for as long as it takes.
+type Relay struct {
+	repo    repository.OutboxRepository
+	sinks   []Sink
+	config  RelayConfig
+	logger  logger.Logger
+	metrics metrics.Metrics
+	now     func() time.Time
+}
+
+// NewRelay creates a relay from the outbox to sinks
+func NewRelay(repo repository.OutboxRepository, sinks []Sink, config RelayConfig, log logger.Logger, m metrics.Metrics) *Relay {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	return &Relay{
+		repo:    repo,
+		sinks:   sinks,
+		config:  config,
+		logger:  log,
+		metrics: m,
+		now:     time.Now,
+	}
+}
+
+// Run relays events every poll interval until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep going while full batches are delivered, so a backlog drains quickly
+		for {
+			delivered, err := r.RelayOnce(ctx)
+			if err != nil {
+				r.logger.Error("Outbox relay failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+				break
+			}
+			if delivered < r.config.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// RelayOnce offers each due event to the sinks that have not accepted it and
+// returns the number of events that are now fully delivered
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	now := r.now()
+	entries, err := r.repo.Due(ctx, now, r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return delivered, err
+		}
+
+		failures := r.deliver(ctx, entry)
+		if len(failures) == 0 {
+			if err := r.repo.Delete(ctx, entry); err != nil {
+				// Delivered again on the next read
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		entry.Attempts++
+		entry.NextAttemptAt = now.Add(r.backoff(entry.Attempts))
+		entry.LastError = errors.Join(failures...).Error()
+		if err := r.repo.Update(ctx, entry); err != nil {
+			return delivered, err
+		}
+
+		r.logger.Warn("Outbox delivery failed, will retry", map[string]interface{}{
+			"event_id":        entry.Event.ID,
+			"event_type":      entry.Event.Type,
+			"attempts":        entry.Attempts,
+			"next_attempt_at": entry.NextAttemptAt.Format(time.RFC3339),
+			"error":           entry.LastError,
+		})
+	}
+
+	return delivered, nil
+}
+
+// deliver offers an entry's event to each sink that has not accepted it, recording
+// the ones that do, and returns the failures
+func (r *Relay) deliver(ctx context.Context, entry *entity.OutboxEntry) []error {
+	var failures []error
+	for _, sink := range r.sinks {
+		if entry.Delivered(sink.Name()) {
+			continue
+		}
+
+		err := sink.Publish(ctx, &entry.Event)
+		r.metrics.IncCounter(metrics.OutboxDeliveriesTotal, map[string]string{
+			"sink":    sink.Name(),
+			"outcome": metrics.Outcome(err),
+		})
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", sink.Name(), err))
+			continue
+		}
+		entry.DeliveredTo = append(entry.DeliveredTo, sink.Name())
+	}
+	return failures
+}
+
+// backoff returns the delay before the next attempt after the given number of
+// failed attempts
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.RetryInitial
+	for i := 1; i < attempts && delay < r.config.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.RetryMax)
+}
+
+// SinkNames returns the names of sinks, for logging
+func SinkNames(sinks []Sink) string {
+	names := make([]string, len(sinks))
+	for i, sink := range sinks {
+		names[i] = sink.Name()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,177 @@
+// internal/infrastructure/outbox/relay_test.go
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOutbox is an in-memory outbox repository
+type memoryOutbox struct {
+	entries map[string]*entity.OutboxEntry
+}
+
+func newMemoryOutbox(ids ...string) *memoryOutbox {
+	o := &memoryOutbox{entries: make(map[string]*entity.OutboxEntry)}
+	for _, id := range ids {
+		o.entries[id] = &entity.OutboxEntry{Event: entity.DomainEvent{ID: id, Type: entity.EventTransactionCreated}}
+	}
+	return o
+}
+
+func (o *memoryOutbox) Add(ctx context.Context, events ...*entity.DomainEvent) error {
+	for _, event := range events {
+		o.entries[event.ID] = &entity.OutboxEntry{Event: *event}
+	}
+	return nil
+}
+
+func (o *memoryOutbox) Due(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEntry, error) {
+	ids := make([]string, 0, len(o.entries))
+	for id := range o.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var due []*entity.OutboxEntry
+	for _, id := range ids {
+		if len(due) == limit {
+			break
+		}
+		if entry := *o.entries[id]; !entry.NextAttemptAt.After(now) {
+			entry.DeliveredTo = append([]string(nil), entry.DeliveredTo...)
+			due = append(due, &entry)
+		}
+	}
+	return due, nil
+}
+
+func (o *memoryOutbox) Update(ctx context.Context, entry *entity.OutboxEntry) error {
+	stored := *entry
+	o.entries[entry.Event.ID] = &stored
+	return nil
+}
+
+func (o *memoryOutbox) Delete(ctx context.Context, entry *entity.OutboxEntry) error {
+	delete(o.entries, entry.Event.ID)
+	return nil
+}
+
+// recordingSink records the events it accepts and fails while err is set
+type recordingSink struct {
+	name   string
+	err    error
+	mu     sync.Mutex
+	events []string
+}
+
+// received returns the IDs of the accepted events
+func (s *recordingSink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.events...)
+}
+
+func (s *recordingSink) Name() string {
+	return s.name
+}
+
+func (s *recordingSink) Publish(ctx context.Context, event *entity.DomainEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event.ID)
+	return nil
+}
+
+func TestRelay(t *testing.T) {
+	repo := newMemoryOutbox("event-1", "event-2")
+	good := &recordingSink{name: "good"}
+	flaky := &recordingSink{name: "flaky", err: errors.New("connection refused")}
+	relay := NewRelay(repo, []Sink{good, flaky}, RelayConfig{
+		BatchSize:    10,
+		RetryInitial: time.Second,
+		RetryMax:     3 * time.Second,
+	}, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	relay.now = func() time.Time { return now }
+
+	t.Run("A failing sink keeps the event in the outbox", func(t *testing.T) {
+		delivered, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, delivered)
+		assert.Equal(t, []string{"event-1", "event-2"}, good.events)
+
+		entry := repo.entries["event-1"]
+		assert.Equal(t, 1, entry.Attempts)
+		assert.Equal(t, now.Add(time.Second), entry.NextAttemptAt)
+		assert.Equal(t, []string{"good"}, entry.DeliveredTo)
+		assert.Contains(t, entry.LastError, "flaky: connection refused")
+	})
+
+	t.Run("Nothing is retried before the backoff elapses", func(t *testing.T) {
+		delivered, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, delivered)
+		assert.Equal(t, 1, repo.entries["event-1"].Attempts)
+	})
+
+	t.Run("Backoff doubles up to the maximum", func(t *testing.T) {
+		for _, want := range []time.Duration{2 * time.Second, 3 * time.Second, 3 * time.Second} {
+			now = repo.entries["event-1"].NextAttemptAt
+			_, err := relay.RelayOnce(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, now.Add(want), repo.entries["event-1"].NextAttemptAt)
+		}
+		assert.Equal(t, 4, repo.entries["event-1"].Attempts)
+	})
+
+	t.Run("Recovered sinks receive the event and it leaves the outbox", func(t *testing.T) {
+		flaky.err = nil
+		now = repo.entries["event-1"].NextAttemptAt
+
+		delivered, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, delivered)
+		assert.Empty(t, repo.entries)
+		assert.Equal(t, []string{"event-1", "event-2"}, flaky.events)
+
+		// Sinks that accepted the event earlier are not offered it again
+		assert.Equal(t, []string{"event-1", "event-2"}, good.events)
+	})
+}
+
+func TestRelayRun(t *testing.T) {
+	repo := newMemoryOutbox("event-1", "event-2", "event-3")
+	sink := &recordingSink{name: "sink"}
+	relay := NewRelay(repo, []Sink{sink}, RelayConfig{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    2,
+		RetryInitial: time.Second,
+		RetryMax:     time.Second,
+	}, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	// The backlog is larger than a batch; Run keeps reading until it is drained
+	require.Eventually(t, func() bool { return len(sink.received()) == 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+	assert.Empty(t, repo.entries)
+}
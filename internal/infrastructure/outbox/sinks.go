@@ -0,0 +1,141 @@
+// Package outbox internal/infrastructure/outbox/sinks.go
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+)
+
+// WebhookSink POSTs each event as JSON to a URL. Any 2xx response accepts it.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink posting to url, giving up on a request after timeout
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the sink
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Publish posts the event. The event ID and type are also sent as headers so
+// receivers can discard duplicates without parsing the body.
+func (s *WebhookSink) Publish(ctx context.Context, event *entity.DomainEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID)
+	req.Header.Set("X-Event-Type", event.Type)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// FileSink appends each event to a file as one line of JSON
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens path for appending, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open event file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Name identifies the sink
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+// Publish appends the event and syncs the file, so an accepted event survives a crash
+func (s *FileSink) Publish(ctx context.Context, event *entity.DomainEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// Publisher is the part of a message broker client the broker sink needs. Adapt a
+// Kafka, NATS or AMQP producer to it; Publish must return once the broker has
+// acknowledged the message.
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, body []byte) error
+}
+
+// BrokerSink publishes each event to a message broker topic, keyed by the
+// transaction ID so a partitioned broker keeps a transaction's events in order
+type BrokerSink struct {
+	publisher Publisher
+	topic     string
+}
+
+// NewBrokerSink creates a sink publishing to topic through publisher
+func NewBrokerSink(publisher Publisher, topic string) *BrokerSink {
+	return &BrokerSink{
+		publisher: publisher,
+		topic:     topic,
+	}
+}
+
+// Name identifies the sink
+func (s *BrokerSink) Name() string {
+	return "broker:" + s.topic
+}
+
+// Publish sends the event as JSON
+func (s *BrokerSink) Publish(ctx context.Context, event *entity.DomainEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return s.publisher.Publish(ctx, s.topic, event.AggregateID, body)
+}
@@ -0,0 +1,146 @@
+// internal/infrastructure/outbox/sinks_test.go
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEvent returns a TransactionCreated event for the tests
+func testEvent(t *testing.T, id string) *entity.DomainEvent {
+	t.Helper()
+
+	event, err := entity.NewTransactionCreatedEvent(id, &entity.Transaction{
+		ID:          "tx-" + id,
+		Description: "Fuel",
+		Date:        time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+		Amount:      42.5,
+		CreatedAt:   time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	event.TenantID = "acme"
+	return event
+}
+
+func TestWebhookSink(t *testing.T) {
+	status := http.StatusNoContent
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, time.Second)
+	assert.Equal(t, "webhook", sink.Name())
+
+	t.Run("Event is posted as JSON", func(t *testing.T) {
+		require.NoError(t, sink.Publish(context.Background(), testEvent(t, "event-1")))
+		require.Len(t, received, 1)
+
+		r := received[0]
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "event-1", r.Header.Get("X-Event-ID"))
+		assert.Equal(t, entity.EventTransactionCreated, r.Header.Get("X-Event-Type"))
+
+		var event entity.DomainEvent
+		require.NoError(t, json.Unmarshal(bodies[0], &event))
+		assert.Equal(t, "tx-event-1", event.AggregateID)
+		assert.Equal(t, "acme", event.TenantID)
+
+		var payload entity.TransactionPayload
+		require.NoError(t, json.Unmarshal(event.Payload, &payload))
+		assert.Equal(t, "2024-02-28", payload.Date)
+		assert.Equal(t, 42.5, payload.Amount)
+	})
+
+	t.Run("Non-2xx responses fail", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		err := sink.Publish(context.Background(), testEvent(t, "event-2"))
+		assert.ErrorContains(t, err, "503")
+	})
+
+	t.Run("Unreachable receivers fail", func(t *testing.T) {
+		unreachable := NewWebhookSink("http://127.0.0.1:1", time.Second)
+		assert.Error(t, unreachable.Publish(context.Background(), testEvent(t, "event-3")))
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	assert.Equal(t, "file", sink.Name())
+	require.NoError(t, sink.Publish(context.Background(), testEvent(t, "event-1")))
+	require.NoError(t, sink.Close())
+
+	// Reopening appends rather than truncating
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), testEvent(t, "event-2")))
+	require.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event entity.DomainEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		ids = append(ids, event.ID)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"event-1", "event-2"}, ids)
+}
+
+// publisherFunc adapts a function to the Publisher interface
+type publisherFunc func(ctx context.Context, topic, key string, body []byte) error
+
+func (f publisherFunc) Publish(ctx context.Context, topic, key string, body []byte) error {
+	return f(ctx, topic, key, body)
+}
+
+func TestBrokerSink(t *testing.T) {
+	var topic, key string
+	var body []byte
+	sink := NewBrokerSink(publisherFunc(func(ctx context.Context, gotTopic, gotKey string, gotBody []byte) error {
+		topic, key, body = gotTopic, gotKey, gotBody
+		return nil
+	}), "transactions")
+	assert.Equal(t, "broker:transactions", sink.Name())
+
+	require.NoError(t, sink.Publish(context.Background(), testEvent(t, "event-1")))
+	assert.Equal(t, "transactions", topic)
+	assert.Equal(t, "tx-event-1", key)
+
+	var event entity.DomainEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, "event-1", event.ID)
+
+	t.Run("Broker errors are returned", func(t *testing.T) {
+		failing := NewBrokerSink(publisherFunc(func(context.Context, string, string, []byte) error {
+			return errors.New("not acknowledged")
+		}), "transactions")
+		assert.ErrorContains(t, failing.Publish(context.Background(), testEvent(t, "event-2")), "not acknowledged")
+	})
+}
@@ -0,0 +1,149 @@
+// Package ratelimit internal/infrastructure/ratelimit/limiter.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit describes a token bucket: Rate tokens are added per second up to Burst
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseLimit parses a limit in the form "rate:burst", e.g. "5:10"
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected rate:burst", s)
+	}
+
+	rate, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || rate <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate in %q", s)
+	}
+
+	burst, err := strconv.Atoi(parts[1])
+	if err != nil || burst < 1 {
+		return Limit{}, fmt.Errorf("invalid burst in %q", s)
+	}
+
+	return Limit{Rate: rate, Burst: burst}, nil
+}
+
+// Decision is the outcome of a rate limit check
+type Decision struct {
+	// Allowed reports whether the request may proceed
+	Allowed bool
+	// Limit is the bucket capacity
+	Limit int
+	// Remaining is the number of requests left in the bucket
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request would be allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps rate limiter state. The in-memory implementation serves a single
+// instance; a shared implementation can be plugged in for multiple replicas.
+type Store interface {
+	// Allow takes one token from the bucket identified by key
+	Allow(ctx context.Context, key string, limit Limit) (Decision, error)
+}
+
+// bucket holds the token bucket state for a single key
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+	// fullAt is when the bucket refills to its burst under its own limit, after
+	// which dropping it loses nothing
+	fullAt time.Time
+}
+
+// MemoryStore is an in-memory Store using token buckets
+type MemoryStore struct {
+	buckets       map[string]*bucket
+	mutex         sync.Mutex
+	now           func() time.Time
+	sweepInterval time.Duration
+	lastSweep     time.Time
+}
+
+// NewMemoryStore creates a new in-memory rate limit store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:       make(map[string]*bucket),
+		now:           time.Now,
+		sweepInterval: time.Minute,
+		lastSweep:     time.Now(),
+	}
+}
+
+// Allow takes one token from the bucket identified by key
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Decision, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), lastSeen: now}
+		s.buckets[key] = b
+	}
+
+	// Refill tokens for the time elapsed since the last request
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.lastSeen = now
+
+	decision := Decision{Limit: limit.Burst}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+
+	decision.Remaining = int(math.Floor(b.tokens))
+	decision.Reset = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+	b.fullAt = now.Add(decision.Reset)
+
+	return decision, nil
+}
+
+// Size returns the number of tracked buckets
+func (s *MemoryStore) Size() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.buckets)
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.After(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// secondsToDuration converts fractional seconds to a duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
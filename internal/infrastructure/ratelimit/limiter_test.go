@@ -0,0 +1,85 @@
+// internal/infrastructure/ratelimit/limiter_test.go
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+
+	limit := Limit{Rate: 1, Burst: 3}
+	ctx := context.Background()
+
+	// The bucket starts full
+	for i := 2; i >= 0; i-- {
+		decision, err := store.Allow(ctx, "client", limit)
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 3, decision.Limit)
+		assert.Equal(t, i, decision.Remaining)
+	}
+
+	// The fourth request is rejected until a token is refilled
+	decision, _ := store.Allow(ctx, "client", limit)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, time.Second, decision.RetryAfter)
+	assert.Equal(t, 3*time.Second, decision.Reset)
+
+	// Other keys have their own bucket
+	decision, _ = store.Allow(ctx, "other-client", limit)
+	assert.True(t, decision.Allowed)
+
+	// After one second one token is available again
+	now = now.Add(time.Second)
+	decision, _ = store.Allow(ctx, "client", limit)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+
+	// Idle buckets are swept
+	now = now.Add(10 * time.Minute)
+	store.Allow(ctx, "new-client", limit)
+	assert.Equal(t, 1, store.Size())
+}
+
+func TestMemoryStoreSweepKeepsDrainingBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+	store.sweepInterval = time.Second
+
+	read := Limit{Rate: 100, Burst: 100}
+	write := Limit{Rate: 0.01, Burst: 1}
+	ctx := context.Background()
+
+	decision, _ := store.Allow(ctx, "write:client", write)
+	assert.True(t, decision.Allowed)
+
+	// A sweep triggered by a fast-refilling limit keeps the write bucket, which
+	// takes 100 seconds to refill
+	now = now.Add(40 * time.Second)
+	store.Allow(ctx, "read:client", read)
+	assert.Equal(t, 2, store.Size())
+
+	decision, _ = store.Allow(ctx, "write:client", write)
+	assert.False(t, decision.Allowed)
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("2.5:10")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 2.5, Burst: 10}, limit)
+
+	for _, invalid := range []string{"", "5", "a:1", "5:b", "0:1", "5:0"} {
+		_, err := ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,158 @@
+// Package rpc internal/infrastructure/rpc/interceptor.go
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/middleware"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys matching the HTTP headers of the same purpose
+const (
+	RequestIDKey     = "x-request-id"
+	TenantKey        = "x-tenant-id"
+	AuthorizationKey = "authorization"
+)
+
+// incoming returns the first value of a key in the call's metadata
+func incoming(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// RequestIDInterceptor gives each call the request ID of its x-request-id
+// metadata, or a new one, and returns it in the response header
+func RequestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	requestID := incoming(ctx, RequestIDKey)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, requestID))
+
+	return handler(middleware.WithRequestID(ctx, requestID), req)
+}
+
+// LoggingInterceptor logs calls and their outcomes
+func LoggingInterceptor(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		startTime := time.Now()
+		reqLog := logger.ForContext(ctx, log)
+
+		remoteAddr := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddr = p.Addr.String()
+		}
+		reqLog.Info("Call received", map[string]interface{}{
+			"method":      info.FullMethod,
+			"remote_addr": remoteAddr,
+			"user_agent":  incoming(ctx, "user-agent"),
+		})
+
+		resp, err := handler(ctx, req)
+
+		reqLog.Info("Call completed", map[string]interface{}{
+			"method":      info.FullMethod,
+			"code":        status.Code(err).String(),
+			"duration_ms": time.Since(startTime).Milliseconds(),
+		})
+		return resp, err
+	}
+}
+
+// AuthInterceptor verifies the bearer token of each call and enforces the policy,
+// whose routes are full method names
+func AuthInterceptor(authenticator *auth.Authenticator, policy *auth.Policy, log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		reqLog := logger.ForContext(ctx, log)
+		route := info.FullMethod
+
+		if policy.IsPublic(route) {
+			return handler(ctx, req)
+		}
+
+		principal, err := authenticator.Authenticate(ctx, auth.BearerToken(incoming(ctx, AuthorizationKey)))
+		if err != nil {
+			reqLog.Warn("Authentication failed", map[string]interface{}{
+				"route": route,
+				"error": err.Error(),
+			})
+			return nil, status.Error(codes.Unauthenticated, "a valid bearer token is required")
+		}
+
+		if !policy.Authorize(route, principal) {
+			reqLog.Warn("Authorization failed", map[string]interface{}{
+				"subject":        principal.Subject,
+				"route":          route,
+				"required_roles": policy.RequiredRoles(route),
+			})
+			return nil, status.Error(codes.PermissionDenied, "the caller does not have the role required for this operation")
+		}
+
+		reqLog.Debug("Request authenticated", map[string]interface{}{
+			"subject": principal.Subject,
+			"route":   route,
+		})
+
+		ctx = middleware.WithPrincipal(ctx, principal)
+		ctx = logger.ContextWithFields(ctx, map[string]interface{}{"subject": principal.Subject})
+		return handler(ctx, req)
+	}
+}
+
+// TenantInterceptor resolves the tenant of each call, from the principal's claim
+// or the x-tenant-id metadata, as TenantMiddleware does for HTTP requests. It
+// must run after AuthInterceptor.
+func TenantInterceptor(registry *tenant.Registry, claim string, log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		reqLog := logger.ForContext(ctx, log)
+		requested := strings.TrimSpace(incoming(ctx, TenantKey))
+
+		config, err := middleware.ResolveTenant(registry, claim, middleware.GetPrincipal(ctx), requested)
+		switch {
+		case errors.Is(err, middleware.ErrTenantMismatch):
+			reqLog.Warn("Tenant metadata does not match token", map[string]interface{}{
+				"requested_tenant": requested,
+				"subject":          middleware.GetSubject(ctx),
+			})
+			return nil, status.Error(codes.PermissionDenied, "the requested tenant does not match the caller's tenant")
+		case errors.Is(err, middleware.ErrMissingTenantClaim):
+			reqLog.Warn("Token has no tenant claim", map[string]interface{}{
+				"requested_tenant": requested,
+				"subject":          middleware.GetSubject(ctx),
+			})
+			return nil, status.Error(codes.PermissionDenied, "the caller's token does not grant access to a tenant")
+		case errors.Is(err, middleware.ErrMissingTenant):
+			reqLog.Warn("Missing tenant", map[string]interface{}{})
+			return nil, status.Error(codes.InvalidArgument, "the "+TenantKey+" metadata is required")
+		case errors.Is(err, tenant.ErrInvalidID):
+			reqLog.Warn("Tenant rejected", map[string]interface{}{
+				"requested_tenant": requested,
+				"error":            err.Error(),
+			})
+			return nil, status.Error(codes.InvalidArgument, "the requested tenant is not available")
+		case err != nil:
+			reqLog.Warn("Tenant rejected", map[string]interface{}{
+				"requested_tenant": requested,
+				"error":            err.Error(),
+			})
+			return nil, status.Error(codes.PermissionDenied, "the requested tenant is not available")
+		}
+
+		ctx = logger.ContextWithFields(tenant.WithConfig(ctx, config), map[string]interface{}{"tenant_id": config.ID})
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,202 @@
+// Package rpc internal/infrastructure/rpc/server.go
+package rpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/rpc/transactionpb"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits on the transactions returned per ListTransactions call
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Config holds the settings of the gRPC server
+type Config struct {
+	// Authenticator verifies bearer tokens against Policy, whose routes are full
+	// method names. Authentication is disabled when it is nil.
+	Authenticator *auth.Authenticator
+	Policy        *auth.Policy
+	// Tenants resolves the tenant of each call, from the TenantClaim of the token
+	// or the x-tenant-id metadata
+	Tenants     *tenant.Registry
+	TenantClaim string
+	// MaxRecvMsgSize limits the size of requests
+	MaxRecvMsgSize int
+}
+
+// NewServer creates a gRPC server serving the transaction service. Calls pass
+// through the request ID, logging, authentication and tenant interceptors, in
+// the order of the HTTP middleware.
+func NewServer(transactions *service.TransactionService, conversions *service.ConversionService, config Config, log logger.Logger) *grpc.Server {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		RequestIDInterceptor,
+		LoggingInterceptor(log),
+	}
+	if config.Authenticator != nil {
+		interceptors = append(interceptors, AuthInterceptor(config.Authenticator, config.Policy, log))
+	}
+	interceptors = append(interceptors, TenantInterceptor(config.Tenants, config.TenantClaim, log))
+
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+	if config.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(config.MaxRecvMsgSize))
+	}
+
+	server := grpc.NewServer(opts...)
+	transactionpb.RegisterTransactionServiceServer(server, NewTransactionServer(transactions, conversions, log))
+	return server
+}
+
+// TransactionServer implements the gRPC transaction service with the same
+// services and validation as the REST handlers
+type TransactionServer struct {
+	transactionpb.UnimplementedTransactionServiceServer
+
+	transactions *service.TransactionService
+	conversions  *service.ConversionService
+	logger       logger.Logger
+}
+
+// NewTransactionServer creates a new gRPC transaction service
+func NewTransactionServer(transactions *service.TransactionService, conversions *service.ConversionService, log logger.Logger) *TransactionServer {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+
+	return &TransactionServer{
+		transactions: transactions,
+		conversions:  conversions,
+		logger:       log,
+	}
+}
+
+// CreateTransaction stores a purchase transaction
+func (s *TransactionServer) CreateTransaction(ctx context.Context, req *transactionpb.CreateTransactionRequest) (*transactionpb.CreateTransactionResponse, error) {
+	if len(req.GetDescription()) > 50 {
+		return nil, status.Error(codes.InvalidArgument, "description must not exceed 50 characters")
+	}
+	category := strings.TrimSpace(req.GetCategory())
+	if len(category) > entity.MaxCategoryLength {
+		return nil, status.Errorf(codes.InvalidArgument, "category must not exceed %d characters", entity.MaxCategoryLength)
+	}
+	if req.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be a positive value")
+	}
+	date, err := time.Parse("2006-01-02", req.GetDate())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "date must be in YYYY-MM-DD format")
+	}
+	if date.After(time.Now()) {
+		return nil, status.Error(codes.InvalidArgument, "transaction date cannot be in the future")
+	}
+
+	id, err := s.transactions.CreateCategorizedTransaction(ctx, req.GetDescription(), category, date, req.GetAmount())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &transactionpb.CreateTransactionResponse{Id: id}, nil
+}
+
+// GetTransaction retrieves a transaction by ID
+func (s *TransactionServer) GetTransaction(ctx context.Context, req *transactionpb.GetTransactionRequest) (*transactionpb.GetTransactionResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	tx, err := s.transactions.GetTransaction(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &transactionpb.GetTransactionResponse{Transaction: newTransaction(tx)}, nil
+}
+
+// ListTransactions returns the page of the tenant's transactions whose IDs follow
+// the page token, in ID order
+func (s *TransactionServer) ListTransactions(ctx context.Context, req *transactionpb.ListTransactionsRequest) (*transactionpb.ListTransactionsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0 || pageSize > maxPageSize:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	}
+	page, next, err := s.transactions.ListTransactionPage(ctx, req.GetPageToken(), pageSize)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &transactionpb.ListTransactionsResponse{NextPageToken: next}
+	for _, tx := range page {
+		resp.Transactions = append(resp.Transactions, newTransaction(tx))
+	}
+	return resp, nil
+}
+
+// ConvertTransaction retrieves a transaction converted to another currency,
+// falling back to the tenant's default currency
+func (s *TransactionServer) ConvertTransaction(ctx context.Context, req *transactionpb.ConvertTransactionRequest) (*transactionpb.ConvertTransactionResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	currency := req.GetCurrency()
+	if currency == "" {
+		currency = tenant.FromContext(ctx).DefaultCurrency()
+	}
+	if currency == "" {
+		return nil, status.Error(codes.InvalidArgument, "currency is required")
+	}
+	if len(currency) != 3 {
+		return nil, status.Error(codes.InvalidArgument, "currency code should be 3 characters (e.g., EUR, GBP, CAD)")
+	}
+
+	converted, err := s.conversions.GetTransactionInCurrency(ctx, req.GetId(), currency)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &transactionpb.ConvertTransactionResponse{
+		Id:              converted.ID,
+		Description:     converted.Description,
+		Date:            converted.Date.Format("2006-01-02"),
+		OriginalAmount:  converted.OriginalAmount,
+		Currency:        converted.Currency,
+		ExchangeRate:    converted.ExchangeRate,
+		ConvertedAmount: converted.ConvertedAmount,
+		RateDate:        converted.RateDate.Format("2006-01-02"),
+	}, nil
+}
+
+// newTransaction builds the message describing a transaction
+func newTransaction(tx *entity.Transaction) *transactionpb.Transaction {
+	msg := &transactionpb.Transaction{
+		Id:          tx.ID,
+		Description: tx.Description,
+		Date:        tx.Date.Format("2006-01-02"),
+		Amount:      tx.Amount,
+		Category:    tx.Category,
+	}
+	if tx.Held() {
+		msg.LegalHold = &transactionpb.LegalHold{
+			Reason:   tx.LegalHold.Reason,
+			PlacedBy: tx.LegalHold.PlacedBy,
+			PlacedAt: tx.LegalHold.PlacedAt.Format(time.RFC3339),
+		}
+	}
+	return msg
+}
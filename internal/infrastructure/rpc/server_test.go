@@ -0,0 +1,288 @@
+// internal/infrastructure/rpc/server_test.go
+package rpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/auth"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/rpc/transactionpb"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/mocks"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the transaction service over an in-memory connection and
+// returns a client of it
+func newTestClient(t *testing.T, rates *mocks.MockExchangeRateRepository, config Config) transactionpb.TransactionServiceClient {
+	t.Helper()
+
+	badgerDB, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { badgerDB.Close() })
+
+	log := logger.NewJSONLogger(nil, logger.InfoLevel)
+	txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
+	transactions := service.NewTransactionService(txRepo, log)
+	conversions := service.NewConversionService(txRepo, rates, log)
+
+	if config.Tenants == nil {
+		config.Tenants, err = tenant.NewRegistry()
+		require.NoError(t, err)
+	}
+	server := NewServer(transactions, conversions, config, log)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return transactionpb.NewTransactionServiceClient(conn)
+}
+
+func TestTransactionServer(t *testing.T) {
+	rates := new(mocks.MockExchangeRateRepository)
+	client := newTestClient(t, rates, Config{})
+	ctx := context.Background()
+
+	create := func(description string, amount float64) string {
+		resp, err := client.CreateTransaction(ctx, &transactionpb.CreateTransactionRequest{
+			Description: description,
+			Date:        "2023-04-15",
+			Amount:      amount,
+		})
+		require.NoError(t, err)
+		return resp.GetId()
+	}
+
+	t.Run("Created transactions can be retrieved", func(t *testing.T) {
+		id := create("Office supplies", 125.456)
+
+		resp, err := client.GetTransaction(ctx, &transactionpb.GetTransactionRequest{Id: id})
+		require.NoError(t, err)
+		assert.Equal(t, id, resp.GetTransaction().GetId())
+		assert.Equal(t, "Office supplies", resp.GetTransaction().GetDescription())
+		assert.Equal(t, "2023-04-15", resp.GetTransaction().GetDate())
+		assert.Equal(t, 125.46, resp.GetTransaction().GetAmount())
+		assert.Nil(t, resp.GetTransaction().GetLegalHold())
+		assert.Empty(t, resp.GetTransaction().GetCategory())
+	})
+
+	t.Run("Categories are stored with the transaction", func(t *testing.T) {
+		created, err := client.CreateTransaction(ctx, &transactionpb.CreateTransactionRequest{
+			Description: "Lunch",
+			Date:        "2023-04-15",
+			Amount:      12,
+			Category:    " Meals ",
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GetTransaction(ctx, &transactionpb.GetTransactionRequest{Id: created.GetId()})
+		require.NoError(t, err)
+		assert.Equal(t, "Meals", resp.GetTransaction().GetCategory())
+	})
+
+	t.Run("Invalid transactions are rejected", func(t *testing.T) {
+		tests := []*transactionpb.CreateTransactionRequest{
+			{Description: "This description is far too long to be accepted by the service", Date: "2023-04-15", Amount: 1},
+			{Description: "Zero", Date: "2023-04-15", Amount: 0},
+			{Description: "Bad date", Date: "15/04/2023", Amount: 1},
+			{Description: "Future", Date: time.Now().AddDate(0, 0, 2).Format("2006-01-02"), Amount: 1},
+			{Description: "Long category", Date: "2023-04-15", Amount: 1, Category: strings.Repeat("c", 51)},
+		}
+		for _, req := range tests {
+			_, err := client.CreateTransaction(ctx, req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), req.GetDescription())
+		}
+	})
+
+	t.Run("Unknown transactions are not found", func(t *testing.T) {
+		_, err := client.GetTransaction(ctx, &transactionpb.GetTransactionRequest{Id: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Conversion", func(t *testing.T) {
+		id := create("Fuel", 40)
+		rates.On("FindRate", mock.Anything, "EUR", mock.Anything).Return(&entity.ExchangeRate{
+			Currency: "EUR",
+			Date:     time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+			Rate:     0.85,
+		}, nil)
+		rates.On("FindRate", mock.Anything, "XYZ", mock.Anything).Return(nil, &repository.LookbackError{Months: 3,
+			Err: fmt.Errorf("%w within 3 months of 2023-04-15 for currency XYZ", repository.ErrNoExchangeRate)})
+		rates.On("FindRate", mock.Anything, "GBP", mock.Anything).Return(nil,
+			errors.New("failed to execute request after 3 attempts: connection refused"))
+
+		resp, err := client.ConvertTransaction(ctx, &transactionpb.ConvertTransactionRequest{Id: id, Currency: "EUR"})
+		require.NoError(t, err)
+		assert.Equal(t, 40.0, resp.GetOriginalAmount())
+		assert.Equal(t, 0.85, resp.GetExchangeRate())
+		assert.Equal(t, 34.0, resp.GetConvertedAmount())
+		assert.Equal(t, "2023-03-31", resp.GetRateDate())
+
+		for currency, code := range map[string]codes.Code{
+			"XYZ":  codes.FailedPrecondition,
+			"GBP":  codes.Unavailable,
+			"EURO": codes.InvalidArgument,
+			"":     codes.InvalidArgument,
+		} {
+			_, err := client.ConvertTransaction(ctx, &transactionpb.ConvertTransactionRequest{Id: id, Currency: currency})
+			assert.Equal(t, code, status.Code(err), currency)
+		}
+
+		// The message names the lookback window that was applied
+		_, err = client.ConvertTransaction(ctx, &transactionpb.ConvertTransactionRequest{Id: id, Currency: "XYZ"})
+		assert.Contains(t, status.Convert(err).Message(), "within 3 months before the transaction date")
+
+		_, err = client.ConvertTransaction(ctx, &transactionpb.ConvertTransactionRequest{Id: "unknown", Currency: "EUR"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Requests carry a request ID", func(t *testing.T) {
+		var header metadata.MD
+		callCtx := metadata.AppendToOutgoingContext(ctx, RequestIDKey, "req-123")
+		_, err := client.ListTransactions(callCtx, &transactionpb.ListTransactionsRequest{}, grpc.Header(&header))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-123"}, header.Get(RequestIDKey))
+
+		_, err = client.ListTransactions(ctx, &transactionpb.ListTransactionsRequest{}, grpc.Header(&header))
+		require.NoError(t, err)
+		assert.NotEmpty(t, header.Get(RequestIDKey))
+	})
+}
+
+func TestListTransactions(t *testing.T) {
+	client := newTestClient(t, new(mocks.MockExchangeRateRepository), Config{})
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		_, err := client.CreateTransaction(ctx, &transactionpb.CreateTransactionRequest{
+			Description: fmt.Sprintf("Purchase %d", i),
+			Date:        "2023-04-15",
+			Amount:      10,
+		})
+		require.NoError(t, err)
+	}
+
+	var ids []string
+	token := ""
+	pages := 0
+	for {
+		resp, err := client.ListTransactions(ctx, &transactionpb.ListTransactionsRequest{PageSize: 3, PageToken: token})
+		require.NoError(t, err)
+		pages++
+		for _, tx := range resp.GetTransactions() {
+			ids = append(ids, tx.GetId())
+		}
+		if resp.GetNextPageToken() == "" {
+			break
+		}
+		token = resp.GetNextPageToken()
+	}
+
+	assert.Equal(t, 3, pages)
+	require.Len(t, ids, 7)
+	assert.IsIncreasing(t, ids)
+
+	_, err := client.ListTransactions(ctx, &transactionpb.ListTransactionsRequest{PageSize: maxPageSize + 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInterceptors(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys := auth.NewStaticKeySet(map[string]crypto.PublicKey{"k1": &key.PublicKey})
+	policy := auth.NewPolicy().
+		Require(transactionpb.TransactionService_CreateTransaction_FullMethodName, "transactions:write").
+		Require(transactionpb.TransactionService_ListTransactions_FullMethodName, "transactions:read")
+	registry, err := tenant.NewRegistry(tenant.Config{ID: "acme"}, tenant.Config{ID: "globex"})
+	require.NoError(t, err)
+
+	client := newTestClient(t, new(mocks.MockExchangeRateRepository), Config{
+		Authenticator: auth.NewAuthenticator(keys, auth.Config{Issuer: "test-issuer"}),
+		Policy:        policy,
+		Tenants:       registry,
+		TenantClaim:   "tenant",
+	})
+
+	token := func(tenantID string, roles ...string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub":    "svc-ledger",
+			"iss":    "test-issuer",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"roles":  roles,
+			"tenant": tenantID,
+		})
+		tok.Header["kid"] = "k1"
+		signed, err := tok.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+	call := func(pairs ...string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), pairs...)
+		_, err := client.ListTransactions(ctx, &transactionpb.ListTransactionsRequest{})
+		return err
+	}
+
+	tests := []struct {
+		name  string
+		pairs []string
+		want  codes.Code
+	}{
+		{"Missing token", nil, codes.Unauthenticated},
+		{"Invalid token", []string{AuthorizationKey, "Bearer nonsense"}, codes.Unauthenticated},
+		{"Missing role", []string{AuthorizationKey, "Bearer " + token("acme", "transactions:write")}, codes.PermissionDenied},
+		{"Authorized", []string{AuthorizationKey, "Bearer " + token("acme", "transactions:read")}, codes.OK},
+		{"Matching tenant metadata", []string{AuthorizationKey, "Bearer " + token("acme", "transactions:read"), TenantKey, "acme"}, codes.OK},
+		{"Contradicting tenant metadata", []string{AuthorizationKey, "Bearer " + token("acme", "transactions:read"), TenantKey, "globex"}, codes.PermissionDenied},
+		{"Token without tenant claim", []string{AuthorizationKey, "Bearer " + token("", "transactions:read")}, codes.PermissionDenied},
+		{"Token without tenant claim selecting a tenant", []string{AuthorizationKey, "Bearer " + token("", "transactions:read"), TenantKey, "globex"}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(call(tt.pairs...)))
+		})
+	}
+
+	t.Run("Tenants only see their own transactions", func(t *testing.T) {
+		acme := metadata.AppendToOutgoingContext(context.Background(), AuthorizationKey, "Bearer "+token("acme", "transactions:read", "transactions:write"))
+		globex := metadata.AppendToOutgoingContext(context.Background(), AuthorizationKey, "Bearer "+token("globex", "transactions:read"))
+
+		_, err := client.CreateTransaction(acme, &transactionpb.CreateTransactionRequest{Description: "Fuel", Date: "2023-04-15", Amount: 40})
+		require.NoError(t, err)
+
+		resp, err := client.ListTransactions(acme, &transactionpb.ListTransactionsRequest{})
+		require.NoError(t, err)
+		assert.Len(t, resp.GetTransactions(), 1)
+		resp, err = client.ListTransactions(globex, &transactionpb.ListTransactionsRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.GetTransactions())
+	})
+}
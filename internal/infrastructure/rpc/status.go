@@ -0,0 +1,31 @@
+// Package rpc internal/infrastructure/rpc/status.go
+package rpc
+
+import (
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError converts a service error into the gRPC status the REST handlers'
+// HTTP status corresponds to
+func statusError(err error) error {
+	switch service.Classify(err) {
+	case service.ErrorCanceled:
+		return status.Error(codes.Canceled, "request cancelled")
+	case service.ErrorDeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, "request deadline exceeded")
+	case service.ErrorExpired:
+		return status.Error(codes.NotFound, "transaction has passed its retention period")
+	case service.ErrorNotFound:
+		return status.Error(codes.NotFound, "transaction not found")
+	case service.ErrorInvalid:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case service.ErrorNoExchangeRate, service.ErrorRateOutOfRange:
+		return status.Error(codes.FailedPrecondition, service.RateErrorMessage(err))
+	case service.ErrorRateServiceUnavailable:
+		return status.Error(codes.Unavailable, "the exchange rate service is temporarily unavailable")
+	default:
+		return status.Error(codes.Internal, "an unexpected error occurred")
+	}
+}
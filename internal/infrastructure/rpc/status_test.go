@@ -0,0 +1,38 @@
+// internal/infrastructure/rpc/status_test.go
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/application/service"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{fmt.Errorf("failed to get transaction: %w", context.Canceled), codes.Canceled},
+		{fmt.Errorf("failed to get transaction: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{fmt.Errorf("failed to get transaction: %w", repository.ErrTransactionNotFound), codes.NotFound},
+		{fmt.Errorf("failed to get transaction: %w", repository.ErrTransactionExpired), codes.NotFound},
+		{entity.ErrInvalidAmount, codes.InvalidArgument},
+		{entity.ErrCategoryTooLong, codes.InvalidArgument},
+		{fmt.Errorf("failed to get exchange rate: %w within 6 months", repository.ErrNoExchangeRate), codes.FailedPrecondition},
+		{fmt.Errorf("failed to get exchange rate: %w: rate date 2022-01-01", repository.ErrRateOutOfRange), codes.FailedPrecondition},
+		{fmt.Errorf("failed to get exchange rate: %w: connection refused", service.ErrRateServiceUnavailable), codes.Unavailable},
+		{errors.New("no exchange rate available, said by an unknown error"), codes.Internal},
+		{errors.New("failed to store transaction: disk full"), codes.Internal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, status.Code(statusError(tt.err)), tt.err.Error())
+	}
+}
@@ -0,0 +1,30 @@
+// Package tenant internal/infrastructure/tenant/context.go
+package tenant
+
+import "context"
+
+// contextKey is the type of the keys this package stores in a context
+type contextKey string
+
+const configKey contextKey = "tenant"
+
+// WithConfig returns a copy of ctx carrying the given tenant configuration
+func WithConfig(ctx context.Context, config Config) context.Context {
+	return context.WithValue(ctx, configKey, config)
+}
+
+// FromContext retrieves the tenant configuration from context, defaulting to the
+// default tenant
+func FromContext(ctx context.Context) Config {
+	config, ok := ctx.Value(configKey).(Config)
+	if !ok || config.ID == "" {
+		return Config{ID: DefaultID}
+	}
+	return config
+}
+
+// IDFromContext retrieves the tenant ID from context, defaulting to the default
+// tenant
+func IDFromContext(ctx context.Context) string {
+	return FromContext(ctx).ID
+}
@@ -0,0 +1,23 @@
+// internal/infrastructure/tenant/context_test.go
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("Contexts without a tenant use the default tenant", func(t *testing.T) {
+		assert.Equal(t, Config{ID: DefaultID}, FromContext(context.Background()))
+		assert.Equal(t, DefaultID, IDFromContext(WithConfig(context.Background(), Config{})))
+	})
+
+	t.Run("The stored tenant is returned", func(t *testing.T) {
+		config := Config{ID: "acme", DefaultCurrencies: []string{"EUR"}}
+		ctx := WithConfig(context.Background(), config)
+		assert.Equal(t, config, FromContext(ctx))
+		assert.Equal(t, "acme", IDFromContext(ctx))
+	})
+}
@@ -0,0 +1,157 @@
+// Package tenant internal/infrastructure/tenant/registry.go
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultID is the tenant used when the service runs in single-tenant mode
+const DefaultID = "default"
+
+var (
+	// ErrInvalidID is returned when a tenant ID contains unsupported characters
+	ErrInvalidID = errors.New("invalid tenant ID")
+	// ErrUnknownTenant is returned when a tenant is not present in the registry
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+// validID restricts tenant IDs to characters that are safe inside storage keys
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidateID checks that a tenant ID is well formed
+func ValidateID(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return nil
+}
+
+// Config holds the settings for a single tenant
+type Config struct {
+	ID                string   `json:"id" yaml:"id"`
+	Name              string   `json:"name,omitempty" yaml:"name,omitempty"`
+	DefaultCurrencies []string `json:"default_currencies,omitempty" yaml:"default_currencies,omitempty"`
+	// RetentionDays overrides the service-wide retention period for the tenant's
+	// transactions; zero uses the service-wide setting
+	RetentionDays int `json:"retention_days,omitempty" yaml:"retention_days,omitempty"`
+}
+
+// Retention returns how long the tenant's transactions are kept, or fallback if the
+// tenant does not override it
+func (c Config) Retention(fallback time.Duration) time.Duration {
+	if c.RetentionDays > 0 {
+		return time.Duration(c.RetentionDays) * 24 * time.Hour
+	}
+	return fallback
+}
+
+// DefaultCurrency returns the currency used when a conversion request names none
+func (c Config) DefaultCurrency() string {
+	if len(c.DefaultCurrencies) == 0 {
+		return ""
+	}
+	return c.DefaultCurrencies[0]
+}
+
+// Registry holds the known tenants. An empty registry accepts only the default tenant.
+type Registry struct {
+	tenants map[string]Config
+	mutex   sync.RWMutex
+}
+
+// NewRegistry creates a registry from the given tenant configurations
+func NewRegistry(configs ...Config) (*Registry, error) {
+	r := &Registry{tenants: make(map[string]Config, len(configs))}
+
+	for _, c := range configs {
+		if err := ValidateID(c.ID); err != nil {
+			return nil, err
+		}
+		if c.RetentionDays < 0 {
+			return nil, fmt.Errorf("tenant %s: retention_days must not be negative", c.ID)
+		}
+		if _, exists := r.tenants[c.ID]; exists {
+			return nil, fmt.Errorf("duplicate tenant ID: %s", c.ID)
+		}
+		for i, currency := range c.DefaultCurrencies {
+			c.DefaultCurrencies[i] = strings.ToUpper(currency)
+		}
+		r.tenants[c.ID] = c
+	}
+
+	return r, nil
+}
+
+// LoadRegistryFile reads tenant configurations from a JSON file
+func LoadRegistryFile(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+
+	var doc struct {
+		Tenants []Config `json:"tenants"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants file: %w", err)
+	}
+
+	return NewRegistry(doc.Tenants...)
+}
+
+// Resolve returns the configuration for a tenant. When no tenants are
+// registered, only the default tenant is accepted.
+func (r *Registry) Resolve(id string) (Config, error) {
+	if err := ValidateID(id); err != nil {
+		return Config{}, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.tenants) == 0 {
+		if id == DefaultID {
+			return Config{ID: DefaultID}, nil
+		}
+		return Config{}, fmt.Errorf("%w: %s", ErrUnknownTenant, id)
+	}
+
+	c, ok := r.tenants[id]
+	if !ok {
+		return Config{}, fmt.Errorf("%w: %s", ErrUnknownTenant, id)
+	}
+	return c, nil
+}
+
+// MultiTenant reports whether tenants have been registered explicitly
+func (r *Registry) MultiTenant() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.tenants) > 0
+}
+
+// IDs returns the registered tenant IDs in sorted order
+func (r *Registry) IDs() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.tenants) == 0 {
+		return []string{DefaultID}
+	}
+
+	ids := make([]string, 0, len(r.tenants))
+	for id := range r.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
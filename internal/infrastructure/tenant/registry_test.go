@@ -0,0 +1,81 @@
+// internal/infrastructure/tenant/registry_test.go
+package tenant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("Single-tenant mode", func(t *testing.T) {
+		registry, err := NewRegistry()
+		require.NoError(t, err)
+
+		assert.False(t, registry.MultiTenant())
+
+		config, err := registry.Resolve(DefaultID)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultID, config.ID)
+
+		_, err = registry.Resolve("acme")
+		assert.ErrorIs(t, err, ErrUnknownTenant)
+	})
+
+	t.Run("Registered tenants", func(t *testing.T) {
+		registry, err := NewRegistry(
+			Config{ID: "acme", DefaultCurrencies: []string{"eur", "gbp"}},
+			Config{ID: "globex"},
+		)
+		require.NoError(t, err)
+
+		assert.True(t, registry.MultiTenant())
+		assert.Equal(t, []string{"acme", "globex"}, registry.IDs())
+
+		config, err := registry.Resolve("acme")
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", config.DefaultCurrency())
+
+		_, err = registry.Resolve(DefaultID)
+		assert.ErrorIs(t, err, ErrUnknownTenant)
+	})
+
+	t.Run("Invalid IDs", func(t *testing.T) {
+		_, err := NewRegistry(Config{ID: "bad:id"})
+		assert.ErrorIs(t, err, ErrInvalidID)
+
+		_, err = NewRegistry(Config{ID: "dup"}, Config{ID: "dup"})
+		assert.Error(t, err)
+
+		_, err = NewRegistry(Config{ID: "acme", RetentionDays: -1})
+		assert.ErrorContains(t, err, "retention_days")
+
+		registry, _ := NewRegistry()
+		_, err = registry.Resolve("")
+		assert.ErrorIs(t, err, ErrInvalidID)
+	})
+
+	t.Run("Load from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tenants.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{
+			"tenants": [{"id": "acme", "name": "Acme Corp", "default_currencies": ["CAD"], "retention_days": 90}]
+		}`), 0600))
+
+		registry, err := LoadRegistryFile(path)
+		require.NoError(t, err)
+
+		config, err := registry.Resolve("acme")
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme Corp", config.Name)
+		assert.Equal(t, "CAD", config.DefaultCurrency())
+		assert.Equal(t, 90*24*time.Hour, config.Retention(time.Hour))
+	})
+
+	t.Run("Retention falls back to the service-wide setting", func(t *testing.T) {
+		assert.Equal(t, time.Hour, Config{ID: "acme"}.Retention(time.Hour))
+	})
+}
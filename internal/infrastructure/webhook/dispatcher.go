@@ -0,0 +1,62 @@
+// Package webhook internal/infrastructure/webhook/dispatcher.go
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/google/uuid"
+)
+
+// deliveryNamespace derives delivery IDs from event and subscription IDs
+var deliveryNamespace = uuid.MustParse("6f0b3c1e-52d4-4b8e-9a57-0c2f4d7e8a91")
+
+// Dispatcher is the outbox sink for webhook subscriptions. It enqueues a delivery
+// of each event to every subscription of the event's tenant that wants it; the
+// Worker sends them.
+type Dispatcher struct {
+	repo repository.WebhookRepository
+	now  func() time.Time
+}
+
+// NewDispatcher creates a dispatcher enqueueing deliveries in repo
+func NewDispatcher(repo repository.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Name identifies the sink
+func (d *Dispatcher) Name() string {
+	return "webhooks"
+}
+
+// Publish enqueues the deliveries of event. Delivery IDs are derived from the event
+// and subscription, so an event the relay offers again is not delivered twice.
+func (d *Dispatcher) Publish(ctx context.Context, event *entity.DomainEvent) error {
+	ctx = tenant.WithConfig(ctx, tenant.Config{ID: event.TenantID})
+	now := d.now().UTC()
+
+	var deliveries []*entity.WebhookDelivery
+	err := d.repo.ListSubscriptions(ctx, func(subscription *entity.WebhookSubscription) error {
+		if !subscription.Subscribes(event.Type) {
+			return nil
+		}
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			ID:             uuid.NewSHA1(deliveryNamespace, []byte(event.ID+"/"+subscription.ID)).String(),
+			SubscriptionID: subscription.ID,
+			Event:          *event,
+			Status:         entity.DeliveryPending,
+			CreatedAt:      now,
+		})
+		return nil
+	})
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+	return d.repo.Enqueue(ctx, deliveries)
+}
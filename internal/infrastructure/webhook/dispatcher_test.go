@@ -0,0 +1,47 @@
+// internal/infrastructure/webhook/dispatcher_test.go
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher(t *testing.T) {
+	repo := newTestRepository(t)
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	require.NoError(t, repo.CreateSubscription(acmeCtx, &entity.WebhookSubscription{
+		ID:     "deletions",
+		URL:    "https://example.com/hook",
+		Events: []string{entity.EventTransactionDeleted},
+	}))
+	subscribe(t, repo, "all", "https://example.com/all")
+	dispatcher := NewDispatcher(repo)
+	assert.Equal(t, "webhooks", dispatcher.Name())
+
+	t.Run("Events go to the subscriptions that want them", func(t *testing.T) {
+		publish(t, dispatcher, "event-1")
+		assert.Len(t, listDeliveries(t, repo, acmeCtx, "all"), 1)
+		assert.Empty(t, listDeliveries(t, repo, acmeCtx, "deletions"))
+	})
+
+	t.Run("Publishing an event again enqueues nothing new", func(t *testing.T) {
+		publish(t, dispatcher, "event-1")
+		assert.Len(t, listDeliveries(t, repo, acmeCtx, "all"), 1)
+	})
+
+	t.Run("Other tenants' subscriptions are not used", func(t *testing.T) {
+		event := &entity.DomainEvent{ID: "event-2", Type: entity.EventTransactionCreated, TenantID: "fleet"}
+		require.NoError(t, dispatcher.Publish(context.Background(), event))
+		assert.Len(t, listDeliveries(t, repo, acmeCtx, "all"), 1)
+
+		due, err := repo.Due(context.Background(), time.Now(), 10)
+		require.NoError(t, err)
+		assert.Len(t, due, 1)
+	})
+}
@@ -0,0 +1,39 @@
+// internal/infrastructure/webhook/signature_test.go
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":"event-1"}`)
+	header := Sign("whsec_test", at, body)
+
+	assert.Regexp(t, `^t=1709294400,v1=[0-9a-f]{64}$`, header)
+	assert.NoError(t, Verify("whsec_test", header, body, at.Add(time.Minute), 5*time.Minute))
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   string
+		now    time.Time
+		want   error
+	}{
+		{"Wrong secret", "whsec_other", header, string(body), at, ErrInvalidSignature},
+		{"Altered body", "whsec_test", header, `{"id":"event-2"}`, at, ErrInvalidSignature},
+		{"Replayed with a new timestamp", "whsec_test", "t=1709294460" + header[12:], string(body), at, ErrInvalidSignature},
+		{"Missing signature", "whsec_test", "t=1709294400", string(body), at, ErrInvalidSignature},
+		{"Malformed header", "whsec_test", "garbage", string(body), at, ErrInvalidSignature},
+		{"Too old", "whsec_test", header, string(body), at.Add(time.Hour), ErrStaleSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, Verify(tt.secret, tt.header, []byte(tt.body), tt.now, 5*time.Minute), tt.want)
+		})
+	}
+}
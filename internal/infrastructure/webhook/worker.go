@@ -0,0 +1,273 @@
+// Package webhook internal/infrastructure/webhook/worker.go
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/metrics"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+)
+
+// errPrivateAddress is returned when a webhook URL resolves to an address the
+// worker may not call
+var errPrivateAddress = errors.New("webhook address is not publicly routable")
+
+// WorkerConfig controls how webhooks are sent and retried
+type WorkerConfig struct {
+	// Timeout bounds each request
+	Timeout time.Duration
+	// PollInterval is the time between reads of the delivery queue
+	PollInterval time.Duration
+	// BatchSize is the most deliveries attempted per read
+	BatchSize int
+	// RetryInitial is the delay before the first retry; each further retry
+	// doubles it, up to RetryMax
+	RetryInitial time.Duration
+	RetryMax     time.Duration
+	// MaxAttempts is the number of failed attempts after which a delivery is
+	// moved to the dead-letter list
+	MaxAttempts int
+	// AllowPrivateNetworks permits URLs resolving to loopback, private and
+	// link-local addresses, which are refused by default
+	AllowPrivateNetworks bool
+}
+
+// DefaultWorkerConfig returns the default worker settings
+func DefaultWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		Timeout:      10 * time.Second,
+		PollInterval: time.Second,
+		BatchSize:    100,
+		RetryInitial: 10 * time.Second,
+		RetryMax:     time.Hour,
+		MaxAttempts:  8,
+	}
+}
+
+// Worker sends pending webhook deliveries. Each request is signed with its
+// subscription's secret; failures are retried with exponential backoff until
+// MaxAttempts, after which the delivery is dead-lettered.
+type Worker struct {
+	repo    repository.WebhookRepository
+	client  *http.Client
+	config  WorkerConfig
+	logger  logger.Logger
+	metrics metrics.Metrics
+	now     func() time.Time
+}
+
+// NewWorker creates a worker sending the deliveries queued in repo
+func NewWorker(repo repository.WebhookRepository, config WorkerConfig, log logger.Logger, m metrics.Metrics) *Worker {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	if m == nil {
+		m = metrics.GetDefaultMetrics()
+	}
+
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = refusePrivateAddresses
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Worker{
+		repo: repo,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: transport,
+			// A redirect would send the signed payload somewhere the subscriber
+			// did not register
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config:  config,
+		logger:  log,
+		metrics: m,
+		now:     time.Now,
+	}
+}
+
+// refusePrivateAddresses stops connections to addresses inside the deployment's
+// network, so a subscription cannot be used to probe it. It runs after DNS
+// resolution, so it also catches names pointing at such addresses.
+func refusePrivateAddresses(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", errPrivateAddress, ip)
+	}
+	return nil
+}
+
+// Run sends due deliveries every poll interval until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep going while full batches are attempted, so a backlog drains quickly
+		for {
+			attempted, err := w.RunOnce(ctx)
+			if err != nil {
+				w.logger.Error("Webhook delivery failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+				break
+			}
+			if attempted < w.config.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// RunOnce attempts each due delivery once and returns the number attempted
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := w.repo.Due(ctx, w.now(), w.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, delivery := range deliveries {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if err := w.attempt(ctx, delivery); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends one delivery and saves the outcome
+func (w *Worker) attempt(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	ctx = tenant.WithConfig(ctx, tenant.Config{ID: delivery.TenantID})
+
+	subscription, err := w.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	switch {
+	case errors.Is(err, repository.ErrSubscriptionNotFound):
+		// Nobody is listening any more; keep the delivery for inspection
+		delivery.Status = entity.DeliveryDead
+		delivery.Record(entity.DeliveryAttempt{At: w.now().UTC(), Error: "subscription deleted"})
+		return w.repo.UpdateDelivery(ctx, delivery)
+	case err != nil:
+		return err
+	}
+
+	attempt := w.send(ctx, subscription, delivery)
+	delivery.Record(attempt)
+	w.metrics.IncCounter(metrics.WebhookDeliveriesTotal, map[string]string{
+		"outcome": outcome(attempt),
+	})
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = entity.DeliveryDelivered
+	case delivery.Attempts >= w.config.MaxAttempts:
+		delivery.Status = entity.DeliveryDead
+		w.logger.Warn("Webhook delivery dead-lettered", map[string]interface{}{
+			"tenant_id":       delivery.TenantID,
+			"delivery_id":     delivery.ID,
+			"subscription_id": delivery.SubscriptionID,
+			"event_type":      delivery.Event.Type,
+			"attempts":        delivery.Attempts,
+			"error":           attempt.Error,
+		})
+	default:
+		delivery.NextAttemptAt = attempt.At.Add(w.backoff(delivery.Attempts))
+		w.logger.Debug("Webhook delivery failed, will retry", map[string]interface{}{
+			"tenant_id":       delivery.TenantID,
+			"delivery_id":     delivery.ID,
+			"subscription_id": delivery.SubscriptionID,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt.Format(time.RFC3339),
+			"error":           attempt.Error,
+		})
+	}
+	return w.repo.UpdateDelivery(ctx, delivery)
+}
+
+// send posts a delivery's event to its subscription and describes the outcome.
+// Any 2xx response accepts the event.
+func (w *Worker) send(ctx context.Context, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery) entity.DeliveryAttempt {
+	start := w.now().UTC()
+	attempt := entity.DeliveryAttempt{At: start}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to marshal event: %v", err)
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(EventIDHeader, delivery.Event.ID)
+	req.Header.Set(EventTypeHeader, delivery.Event.Type)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, start, body))
+
+	resp, err := w.client.Do(req)
+	attempt.Duration = w.now().UTC().Sub(start)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("endpoint returned status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// backoff returns the delay before the next attempt after the given number of
+// failed attempts
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.config.RetryInitial
+	for i := 1; i < attempts && delay < w.config.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, w.config.RetryMax)
+}
+
+// outcome labels an attempt for metrics
+func outcome(attempt entity.DeliveryAttempt) string {
+	if attempt.Error == "" {
+		return "success"
+	}
+	return "error"
+}
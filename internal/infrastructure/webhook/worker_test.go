@@ -0,0 +1,298 @@
+// internal/infrastructure/webhook/worker_test.go
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/db"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/logger"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/infrastructure/tenant"
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is an httptest endpoint that verifies and records webhooks, answering
+// with status
+type receiver struct {
+	*httptest.Server
+	secret string
+
+	mu       sync.Mutex
+	status   int
+	received []*http.Request
+	events   []entity.DomainEvent
+	invalid  int
+}
+
+func newReceiver(t *testing.T, secret string) *receiver {
+	r := &receiver{secret: secret, status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if err := Verify(r.secret, req.Header.Get(SignatureHeader), body, time.Now(), 5*time.Minute); err != nil {
+			r.invalid++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event entity.DomainEvent
+		json.Unmarshal(body, &event)
+		r.received = append(r.received, req)
+		r.events = append(r.events, event)
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// respond sets the status of later responses
+func (r *receiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// eventIDs returns the IDs of the events received with a valid signature
+func (r *receiver) eventIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, len(r.events))
+	for i, event := range r.events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+// newTestRepository returns a webhook repository in an in-memory database
+func newTestRepository(t *testing.T) repository.WebhookRepository {
+	t.Helper()
+	badgerDB, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	require.NoError(t, err)
+	t.Cleanup(func() { badgerDB.Close() })
+	return db.NewBadgerWebhookRepository(badgerDB, logger.NewJSONLogger(nil, logger.InfoLevel), nil)
+}
+
+// testWorkerConfig allows the loopback receivers of the tests
+func testWorkerConfig() WorkerConfig {
+	config := DefaultWorkerConfig()
+	config.Timeout = 2 * time.Second
+	config.RetryInitial = time.Minute
+	config.RetryMax = 4 * time.Minute
+	config.MaxAttempts = 3
+	config.AllowPrivateNetworks = true
+	return config
+}
+
+// subscribe registers a subscription of tenant acme to every event type
+func subscribe(t *testing.T, repo repository.WebhookRepository, id, url string) {
+	t.Helper()
+	ctx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+	require.NoError(t, repo.CreateSubscription(ctx, &entity.WebhookSubscription{
+		ID:     id,
+		URL:    url,
+		Events: entity.EventTypes,
+		Secret: "whsec_" + id,
+	}))
+}
+
+// publish dispatches a TransactionCreated event of tenant acme
+func publish(t *testing.T, dispatcher *Dispatcher, eventID string) {
+	t.Helper()
+	event := &entity.DomainEvent{
+		ID:          eventID,
+		Type:        entity.EventTransactionCreated,
+		TenantID:    "acme",
+		AggregateID: "tx-1",
+		Payload:     json.RawMessage(`{"id":"tx-1"}`),
+	}
+	require.NoError(t, dispatcher.Publish(context.Background(), event))
+}
+
+func TestWorker(t *testing.T) {
+	acmeCtx := tenant.WithConfig(context.Background(), tenant.Config{ID: "acme"})
+
+	t.Run("Delivers a signed request with the event", func(t *testing.T) {
+		repo := newTestRepository(t)
+		r := newReceiver(t, "whsec_sub-1")
+		subscribe(t, repo, "sub-1", r.URL)
+		publish(t, NewDispatcher(repo), "event-1")
+
+		attempted, err := NewWorker(repo, testWorkerConfig(), nil, nil).RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempted)
+
+		require.Equal(t, []string{"event-1"}, r.eventIDs())
+		req := r.received[0]
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "event-1", req.Header.Get(EventIDHeader))
+		assert.Equal(t, entity.EventTransactionCreated, req.Header.Get(EventTypeHeader))
+		assert.NotEmpty(t, req.Header.Get(DeliveryHeader))
+		assert.Equal(t, "acme", r.events[0].TenantID)
+
+		deliveries := listDeliveries(t, repo, acmeCtx, "sub-1")
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, entity.DeliveryDelivered, deliveries[0].Status)
+		require.Len(t, deliveries[0].History, 1)
+		assert.Equal(t, http.StatusOK, deliveries[0].History[0].StatusCode)
+		assert.Empty(t, deliveries[0].History[0].Error)
+	})
+
+	t.Run("Retries with backoff then dead-letters", func(t *testing.T) {
+		repo := newTestRepository(t)
+		r := newReceiver(t, "whsec_sub-1")
+		r.respond(http.StatusServiceUnavailable)
+		subscribe(t, repo, "sub-1", r.URL)
+		publish(t, NewDispatcher(repo), "event-1")
+
+		worker := NewWorker(repo, testWorkerConfig(), nil, nil)
+		now := time.Now()
+		worker.now = func() time.Time { return now }
+
+		// The first failure waits RetryInitial
+		_, err := worker.RunOnce(context.Background())
+		require.NoError(t, err)
+		delivery := listDeliveries(t, repo, acmeCtx, "sub-1")[0]
+		assert.Equal(t, entity.DeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.WithinDuration(t, now.Add(time.Minute), delivery.NextAttemptAt, time.Second)
+		assert.Equal(t, "endpoint returned status 503", delivery.History[0].Error)
+
+		// Nothing is attempted before the retry is due
+		attempted, err := worker.RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, attempted)
+
+		// The second failure doubles the delay
+		now = now.Add(time.Minute)
+		_, err = worker.RunOnce(context.Background())
+		require.NoError(t, err)
+		delivery = listDeliveries(t, repo, acmeCtx, "sub-1")[0]
+		assert.WithinDuration(t, now.Add(2*time.Minute), delivery.NextAttemptAt, time.Second)
+
+		// The third reaches MaxAttempts
+		now = now.Add(2 * time.Minute)
+		_, err = worker.RunOnce(context.Background())
+		require.NoError(t, err)
+		delivery = listDeliveries(t, repo, acmeCtx, "sub-1")[0]
+		assert.Equal(t, entity.DeliveryDead, delivery.Status)
+		assert.Len(t, delivery.History, 3)
+		assert.Len(t, r.eventIDs(), 3)
+
+		var dead []string
+		require.NoError(t, repo.ListDeadLetters(acmeCtx, func(d *entity.WebhookDelivery) error {
+			dead = append(dead, d.ID)
+			return nil
+		}))
+		assert.Equal(t, []string{delivery.ID}, dead)
+
+		now = now.Add(time.Hour)
+		attempted, err = worker.RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, attempted)
+	})
+
+	t.Run("Each subscription gets its own signature", func(t *testing.T) {
+		repo := newTestRepository(t)
+		r1 := newReceiver(t, "whsec_sub-1")
+		r2 := newReceiver(t, "whsec_sub-2")
+		subscribe(t, repo, "sub-1", r1.URL)
+		subscribe(t, repo, "sub-2", r2.URL)
+		publish(t, NewDispatcher(repo), "event-1")
+
+		_, err := NewWorker(repo, testWorkerConfig(), nil, nil).RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"event-1"}, r1.eventIDs())
+		assert.Equal(t, []string{"event-1"}, r2.eventIDs())
+		assert.Zero(t, r1.invalid)
+		assert.Zero(t, r2.invalid)
+	})
+
+	t.Run("Deliveries to a deleted subscription are dead-lettered unsent", func(t *testing.T) {
+		repo := newTestRepository(t)
+		r := newReceiver(t, "whsec_sub-1")
+		subscribe(t, repo, "sub-1", r.URL)
+		publish(t, NewDispatcher(repo), "event-1")
+		require.NoError(t, repo.DeleteSubscription(acmeCtx, "sub-1"))
+
+		_, err := NewWorker(repo, testWorkerConfig(), nil, nil).RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, r.eventIDs())
+
+		delivery := listDeliveries(t, repo, acmeCtx, "sub-1")[0]
+		assert.Equal(t, entity.DeliveryDead, delivery.Status)
+		assert.Equal(t, "subscription deleted", delivery.History[0].Error)
+	})
+
+	t.Run("Private addresses are refused by default", func(t *testing.T) {
+		repo := newTestRepository(t)
+		r := newReceiver(t, "whsec_sub-1")
+		subscribe(t, repo, "sub-1", r.URL)
+		publish(t, NewDispatcher(repo), "event-1")
+
+		config := testWorkerConfig()
+		config.AllowPrivateNetworks = false
+		_, err := NewWorker(repo, config, nil, nil).RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, r.eventIDs())
+
+		delivery := listDeliveries(t, repo, acmeCtx, "sub-1")[0]
+		assert.Equal(t, entity.DeliveryPending, delivery.Status)
+		assert.Contains(t, delivery.History[0].Error, "not publicly routable")
+	})
+
+	t.Run("Redirects are not followed", func(t *testing.T) {
+		repo := newTestRepository(t)
+		r := newReceiver(t, "whsec_sub-1")
+		redirect := httptest.NewServer(http.RedirectHandler(r.URL, http.StatusTemporaryRedirect))
+		defer redirect.Close()
+		subscribe(t, repo, "sub-1", redirect.URL)
+		publish(t, NewDispatcher(repo), "event-1")
+
+		_, err := NewWorker(repo, testWorkerConfig(), nil, nil).RunOnce(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, r.eventIDs())
+		assert.Equal(t, http.StatusTemporaryRedirect, listDeliveries(t, repo, acmeCtx, "sub-1")[0].History[0].StatusCode)
+	})
+}
+
+func TestWorkerRun(t *testing.T) {
+	repo := newTestRepository(t)
+	r := newReceiver(t, "whsec_sub-1")
+	subscribe(t, repo, "sub-1", r.URL)
+	publish(t, NewDispatcher(repo), "event-1")
+
+	config := testWorkerConfig()
+	config.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewWorker(repo, config, nil, nil).Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(r.eventIDs()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+// listDeliveries returns the deliveries to a subscription, oldest first
+func listDeliveries(t *testing.T, repo repository.WebhookRepository, ctx context.Context, subscriptionID string) []*entity.WebhookDelivery {
+	t.Helper()
+	var deliveries []*entity.WebhookDelivery
+	require.NoError(t, repo.ListDeliveries(ctx, subscriptionID, func(d *entity.WebhookDelivery) error {
+		deliveries = append(deliveries, d)
+		return nil
+	}))
+	return deliveries
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/entity"
+	"github.com/damon-houk/wex-tag-transaction-system/internal/domain/repository"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -19,6 +20,11 @@ func (m *MockTransactionRepository) Store(ctx context.Context, tx *entity.Transa
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StoreWithEvents(ctx context.Context, tx *entity.Transaction, events []*entity.DomainEvent, audit *entity.AuditEvent) (string, error) {
+	args := m.Called(ctx, tx, events, audit)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByID(ctx context.Context, id string) (*entity.Transaction, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -27,6 +33,82 @@ func (m *MockTransactionRepository) FindByID(ctx context.Context, id string) (*e
 	return args.Get(0).(*entity.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) List(ctx context.Context, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, fn)
+	if txs, ok := args.Get(0).([]*entity.Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListAfter(ctx context.Context, after string, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, after, fn)
+	if txs, ok := args.Get(0).([]*entity.Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListByDate(ctx context.Context, from, to time.Time, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, from, to, fn)
+	if txs, ok := args.Get(0).([]*entity.Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListByDateAfter(ctx context.Context, from, to time.Time, after *entity.Transaction, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, from, to, after, fn)
+	if txs, ok := args.Get(0).([]*entity.Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) PlaceLegalHold(ctx context.Context, id string, hold entity.LegalHold, event repository.EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error) {
+	args := m.Called(ctx, id, hold, event, audit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReleaseLegalHold(ctx context.Context, id string, event repository.EventFunc, audit *entity.AuditEvent) (*entity.Transaction, error) {
+	args := m.Called(ctx, id, event, audit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListLegalHolds(ctx context.Context, fn func(*entity.Transaction) error) error {
+	args := m.Called(ctx, fn)
+	if txs, ok := args.Get(0).([]*entity.Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 // MockExchangeRateRepository mocks the ExchangeRateRepository interface
 type MockExchangeRateRepository struct {
 	mock.Mock
@@ -45,6 +127,123 @@ func (m *MockExchangeRateRepository) StoreRate(ctx context.Context, rate *entity
 	return args.Error(0)
 }
 
+// MockAuditRepository mocks the AuditRepository interface
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, event *entity.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, fn func(*entity.AuditEvent) error) error {
+	args := m.Called(ctx, fn)
+	if events, ok := args.Get(0).([]*entity.AuditEvent); ok {
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockAuditRepository) ListByTransaction(ctx context.Context, transactionID string, fn func(*entity.AuditEvent) error) error {
+	args := m.Called(ctx, transactionID, fn)
+	if events, ok := args.Get(0).([]*entity.AuditEvent); ok {
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+// MockWebhookRepository mocks the WebhookRepository interface
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context, fn func(*entity.WebhookSubscription) error) error {
+	args := m.Called(ctx, fn)
+	if subscriptions, ok := args.Get(0).([]*entity.WebhookSubscription); ok {
+		for _, subscription := range subscriptions {
+			if err := fn(subscription); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Enqueue(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Due(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, fn func(*entity.WebhookDelivery) error) error {
+	args := m.Called(ctx, subscriptionID, fn)
+	return visitDeliveries(args.Get(0), fn, args.Error(1))
+}
+
+func (m *MockWebhookRepository) ListDeadLetters(ctx context.Context, fn func(*entity.WebhookDelivery) error) error {
+	args := m.Called(ctx, fn)
+	return visitDeliveries(args.Get(0), fn, args.Error(1))
+}
+
+// visitDeliveries calls fn for each delivery a mocked listing returns
+func visitDeliveries(deliveries interface{}, fn func(*entity.WebhookDelivery) error, err error) error {
+	if deliveries, ok := deliveries.([]*entity.WebhookDelivery); ok {
+		for _, delivery := range deliveries {
+			if err := fn(delivery); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
 // MockExchangeRateProvider mocks the exchange rate provider interface
 type MockExchangeRateProvider struct {
 	mock.Mock
@@ -92,3 +291,38 @@ func (m *MockLogger) WithFields(fields map[string]interface{}) interface{} {
 	args := m.Called(fields)
 	return args.Get(0)
 }
+
+// MockTransactionStreamRepository mocks the TransactionStreamRepository interface.
+// ListAfter passes fn the listed entries past after, up to limit.
+type MockTransactionStreamRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransactionStreamRepository) Head(ctx context.Context) (uint64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockTransactionStreamRepository) ListAfter(ctx context.Context, after uint64, limit int, fn func(*entity.StreamEntry) error) error {
+	args := m.Called(ctx, after, limit, fn)
+	if entries, ok := args.Get(0).([]*entity.StreamEntry); ok {
+		for _, entry := range entries {
+			if entry.Sequence <= after {
+				continue
+			}
+			if limit == 0 {
+				break
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+			limit--
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionStreamRepository) Watch(ctx context.Context) <-chan struct{} {
+	args := m.Called(ctx)
+	return args.Get(0).(chan struct{})
+}
@@ -40,7 +40,7 @@ func TestPerformance(t *testing.T) {
 	log := logger.NewJSONLogger(nil, logger.InfoLevel)
 
 	// Initialize repositories and services
-	txRepo := db.NewBadgerTransactionRepository(badgerDB, log)
+	txRepo := db.NewBadgerTransactionRepository(badgerDB, log, nil)
 	txService := service.NewTransactionService(txRepo, log)
 
 	// Performance test configuration